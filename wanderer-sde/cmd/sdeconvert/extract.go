@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/downloader"
+	"github.com/guarzo/wanderer-sde/internal/subset"
+)
+
+// extractFormatSDE writes the extracted subset as SDE YAML files.
+const extractFormatSDE = "sde"
+
+var extractFormat string
+
+var extractCmd = &cobra.Command{
+	Use:   "extract",
+	Short: "Extract a region or system subset of the SDE",
+	Long: `Extracts the regions, constellations and solar systems selected with
+--regions, --constellations and --systems, either as a smaller SDE the
+converter can read or as converted CSV/JSON files.
+
+The output is referentially closed: it only holds jumps, stations and
+wormhole classes inside the selection. With --boundary the systems one
+jump outside the selection, and the jumps to them, are kept as well.`,
+	Example: `  # Build a test fixture SDE from two systems and their neighbours
+  sdeconvert extract --sde-path ./sde --output ./fixture --systems Jita,Perimeter --boundary
+
+  # Convert a single region to CSV
+  sdeconvert extract --sde-path ./sde --output ./forge --regions "The Forge" --format csv`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch extractFormat {
+		case extractFormatSDE:
+		case "csv":
+			cfg.OutputFormat = config.FormatCSV
+		case "json":
+			cfg.OutputFormat = config.FormatJSON
+		default:
+			return fmt.Errorf("invalid format '%s': must be 'sde', 'csv' or 'json'", extractFormat)
+		}
+		return nil
+	},
+	RunE: runExtract,
+}
+
+func init() {
+	extractCmd.Flags().StringVarP(&cfg.SDEPath, "sde-path", "s", "", "Path to the SDE directory to extract from")
+	extractCmd.Flags().StringVarP(&cfg.OutputDir, "output", "o", "./output", "Output directory for the extracted files")
+	extractCmd.Flags().StringVarP(&extractFormat, "format", "f", extractFormatSDE, "Output format: sde, csv or json")
+	extractCmd.Flags().BoolVar(&cfg.PrettyPrint, "pretty", true, "Pretty-print JSON output (only applies to JSON format)")
+	addSelectionFlags(extractCmd)
+	addLoggingFlags(extractCmd)
+}
+
+func runExtract(cmd *cobra.Command, args []string) error {
+	if cfg.SDEPath == "" {
+		return fmt.Errorf("--sde-path must be specified")
+	}
+	sel := selection()
+	if sel.IsEmpty() {
+		return config.ErrNoSelection
+	}
+
+	// Converted output runs the normal pipeline with the selection applied
+	if extractFormat != extractFormatSDE {
+		return runConversion(cmd, args)
+	}
+
+	cfg.Version = Version
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	logger, err := newLogger()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := interruptContext(logger)
+	defer stop()
+
+	if err := downloader.New(cfg, logger).Validate(cfg.SDEPath); err != nil {
+		return fmt.Errorf("SDE validation failed: %w", err)
+	}
+
+	set, err := subset.ExtractSDE(ctx, cfg, logger, cfg.SDEPath, cfg.OutputDir, sel)
+	if err != nil {
+		return fmt.Errorf("failed to extract SDE: %w", err)
+	}
+
+	logger.Info("extraction complete",
+		"outputDir", cfg.OutputDir,
+		"systems", len(set.Systems),
+		"boundarySystems", len(set.Boundary),
+		"constellations", len(set.Constellations),
+		"regions", len(set.Regions))
+	return nil
+}
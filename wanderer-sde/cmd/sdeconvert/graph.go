@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/guarzo/wanderer-sde/internal/export"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+	"github.com/guarzo/wanderer-sde/internal/subset"
+	"github.com/guarzo/wanderer-sde/internal/transformer"
+)
+
+var graphFlags struct {
+	output     string
+	format     string
+	spaceKinds []string
+	aggregate  string
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the stargate network as DOT, GraphML or GEXF",
+	Long: `Exports the stargate network for graph tools: DOT for Graphviz, and
+GraphML or GEXF for Gephi. Each solar system is a node with its name,
+region, constellation, security status, space kind, wormhole class and
+coordinates; each stargate is an edge.
+
+--regions, --constellations and --systems limit the export to a selection
+and --space-kinds to systems of the given kinds. --aggregate region
+collapses each region into one node, with edges weighted by the number of
+gates between regions.
+
+The format follows the --output extension (.dot or .gv, .graphml, .gexf)
+unless --format is given. Without --output the graph is written to stdout.`,
+	Example: `  # Known space as a Gephi file
+  sdeconvert graph --sde-path ./sde --space-kinds highsec,lowsec,nullsec --output new-eden.gexf
+
+  # Region overview for Graphviz
+  sdeconvert graph --sde-path ./sde --aggregate region --format dot | neato -Tsvg > regions.svg`,
+	RunE: runGraph,
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+
+	graphCmd.Flags().StringVarP(&cfg.SDEPath, "sde-path", "s", "", "Path to the SDE directory")
+	graphCmd.Flags().StringVarP(&graphFlags.output, "output", "o", "", "File to write the graph to (default stdout)")
+	graphCmd.Flags().StringVarP(&graphFlags.format, "format", "f", "", "Graph format: dot, graphml or gexf (default from the --output extension, else dot)")
+	graphCmd.Flags().StringSliceVar(&graphFlags.spaceKinds, "space-kinds", nil, "Only export systems of these space kinds (e.g. highsec,lowsec,nullsec,c5)")
+	graphCmd.Flags().StringVar(&graphFlags.aggregate, "aggregate", string(export.AggregateSystems), "Node level: system or region")
+	addSelectionFlags(graphCmd)
+	addLoggingFlags(graphCmd)
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	if cfg.SDEPath == "" {
+		return fmt.Errorf("--sde-path must be specified")
+	}
+	format := export.FormatDOT
+	if graphFlags.format != "" {
+		f, err := export.ParseFormat(graphFlags.format)
+		if err != nil {
+			return err
+		}
+		format = f
+	} else if f, ok := export.FormatFromPath(graphFlags.output); ok {
+		format = f
+	}
+	aggregate, err := export.ParseAggregation(graphFlags.aggregate)
+	if err != nil {
+		return err
+	}
+
+	cfg.Version = Version
+	logger, err := newLogger()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := interruptContext(logger)
+	defer stop()
+
+	universe, jumps, err := loadUniverse(ctx, logger)
+	if err != nil {
+		return err
+	}
+	classLocations, err := parser.New(cfg, cfg.SDEPath, logger).ExtractAllWormholeClasses(ctx)
+	if err != nil {
+		return err
+	}
+	classes := transformer.ResolveSystemWormholeClasses(universe.SolarSystems, classLocations)
+	transformer.ClassifySystems(universe.SolarSystems, classes)
+
+	opts := export.Options{SpaceKinds: graphFlags.spaceKinds, Aggregate: aggregate}
+	if sel := selection(); !sel.IsEmpty() {
+		set, err := subset.Resolve(sel, universe, jumps)
+		if err != nil {
+			return err
+		}
+		opts.Systems = set.Systems
+	}
+
+	g, err := export.Build(universe, jumps, classes, opts)
+	if err != nil {
+		return err
+	}
+	logger.Debug("built export graph", "nodes", len(g.Nodes), "edges", len(g.Edges), "format", format)
+
+	if graphFlags.output == "" {
+		return export.Write(cmd.OutOrStdout(), g, format)
+	}
+	return writeGraphFile(graphFlags.output, g, format)
+}
+
+// writeGraphFile writes a graph to a file, creating or truncating it.
+func writeGraphFile(path string, g *export.Graph, format export.Format) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create graph file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close graph file: %w", closeErr)
+		}
+	}()
+
+	if err := export.Write(f, g, format); err != nil {
+		return fmt.Errorf("failed to write graph file: %w", err)
+	}
+	return nil
+}
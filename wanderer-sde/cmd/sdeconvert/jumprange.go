@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/transformer"
+)
+
+var jumpFlags struct {
+	from   string
+	to     string
+	ship   string
+	jdc    int
+	prefer string
+	avoid  []string
+}
+
+var jumpRangeCmd = &cobra.Command{
+	Use:   "jumprange",
+	Short: "List systems in jump drive range or plan a capital route",
+	Long: `Lists the systems a jump-capable ship can reach from a system in one
+jump, nearest first, with their distance in light years.
+
+With --to it plans a multi-jump route instead. --prefer fewest-jumps
+minimises the number of jumps and least-fatigue the jump fatigue built up;
+the route shows each jump's distance and the fatigue and cooldown waits of
+flying it without pauses.
+
+Jump drives cannot be used in wormhole space or Pochven, and cannot target
+high security systems. Ranges use the ship class's base range plus 20% per
+level of Jump Drive Calibration (--jdc).`,
+	Example: `  # Systems a carrier with JDC V can reach from 1DQ1-A
+  sdeconvert jumprange --sde-path ./sde --from 1DQ1-A --ship carrier
+
+  # Jump freighter route with the least fatigue
+  sdeconvert jumprange --sde-path ./sde --from Jita --to 1DQ1-A --ship jump-freighter --prefer least-fatigue`,
+	RunE: runJumpRange,
+}
+
+func init() {
+	rootCmd.AddCommand(jumpRangeCmd)
+
+	jumpRangeCmd.Flags().StringVarP(&cfg.SDEPath, "sde-path", "s", "", "Path to the SDE directory")
+	jumpRangeCmd.Flags().StringVar(&jumpFlags.from, "from", "", "Origin system (name or ID)")
+	jumpRangeCmd.Flags().StringVar(&jumpFlags.to, "to", "", "Destination system (name or ID); plans a route instead of listing systems in range")
+	jumpRangeCmd.Flags().StringVar(&jumpFlags.ship, "ship", "", "Ship class: black-ops, carrier, dreadnought, fax, jump-freighter, rorqual, supercarrier or titan")
+	jumpRangeCmd.Flags().IntVar(&jumpFlags.jdc, "jdc", graph.MaxJDCLevel, "Jump Drive Calibration skill level (0-5)")
+	jumpRangeCmd.Flags().StringVar(&jumpFlags.prefer, "prefer", string(graph.PreferFewestJumps), "Route preference: fewest-jumps or least-fatigue")
+	jumpRangeCmd.Flags().StringSliceVar(&jumpFlags.avoid, "avoid", nil, "Systems the route must not jump to (names or IDs, repeatable or comma-separated)")
+	addLoggingFlags(jumpRangeCmd)
+}
+
+func runJumpRange(cmd *cobra.Command, args []string) error {
+	if cfg.SDEPath == "" {
+		return fmt.Errorf("--sde-path must be specified")
+	}
+	if jumpFlags.from == "" || jumpFlags.ship == "" {
+		return fmt.Errorf("--from and --ship must be specified")
+	}
+	ship, err := graph.ParseShipClass(jumpFlags.ship)
+	if err != nil {
+		return err
+	}
+	if jumpFlags.jdc < 0 || jumpFlags.jdc > graph.MaxJDCLevel {
+		return fmt.Errorf("--jdc must be between 0 and %d", graph.MaxJDCLevel)
+	}
+	prefer, err := graph.ParseJumpPreference(jumpFlags.prefer)
+	if err != nil {
+		return err
+	}
+
+	cfg.Version = Version
+	logger, err := newLogger()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := interruptContext(logger)
+	defer stop()
+
+	g, regionNames, err := loadGraph(ctx, logger)
+	if err != nil {
+		return err
+	}
+	from, err := g.Lookup(jumpFlags.from)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	rangeLY := ship.Range(jumpFlags.jdc)
+
+	if jumpFlags.to == "" {
+		targets, err := g.JumpNeighbors(from.ID, rangeLY)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "%s (%s, JDC %d): %.2f LY range, %d systems\n",
+			from.Name, ship.Name, jumpFlags.jdc, rangeLY, len(targets))
+		for _, target := range targets {
+			sys := target.System
+			_, _ = fmt.Fprintf(tw, "%.2f LY\t%s\t%s\t%s\n", target.Distance, sys.Name,
+				transformer.DisplaySecurity(sys.Security), regionNames[sys.RegionID])
+		}
+		return tw.Flush()
+	}
+
+	to, err := g.Lookup(jumpFlags.to)
+	if err != nil {
+		return err
+	}
+	opts := graph.JumpOptions{Ship: ship, JDC: jumpFlags.jdc, Prefer: prefer}
+	for _, name := range jumpFlags.avoid {
+		sys, err := g.Lookup(name)
+		if err != nil {
+			return err
+		}
+		opts.Avoid = append(opts.Avoid, sys.ID)
+	}
+
+	route, err := g.JumpRoute(from.ID, to.ID, opts)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "%s to %s (%s, JDC %d, %s): %d jumps, %.2f LY, fatigue %s, cooldown waits %s\n",
+		from.Name, to.Name, ship.Name, jumpFlags.jdc, prefer, route.Jumps(), route.Distance(), route.Fatigue, route.Wait)
+	for i, sys := range route.Systems {
+		distance := ""
+		if i > 0 {
+			distance = fmt.Sprintf("%.2f LY", route.Distances[i-1])
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", i, sys.Name,
+			transformer.DisplaySecurity(sys.Security), regionNames[sys.RegionID], distance)
+	}
+	return tw.Flush()
+}
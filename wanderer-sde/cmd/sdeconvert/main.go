@@ -3,9 +3,9 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -16,7 +16,11 @@ import (
 
 	"github.com/guarzo/wanderer-sde/internal/config"
 	"github.com/guarzo/wanderer-sde/internal/downloader"
+	"github.com/guarzo/wanderer-sde/internal/logging"
+	"github.com/guarzo/wanderer-sde/internal/models"
 	"github.com/guarzo/wanderer-sde/internal/parser"
+	"github.com/guarzo/wanderer-sde/internal/report"
+	"github.com/guarzo/wanderer-sde/internal/subset"
 	"github.com/guarzo/wanderer-sde/internal/transformer"
 	"github.com/guarzo/wanderer-sde/internal/writer"
 )
@@ -24,18 +28,6 @@ import (
 // Version is set at build time via ldflags.
 var Version = "dev"
 
-// SDEMetadata contains metadata about the SDE conversion.
-type SDEMetadata struct {
-	SDEVersion  string `json:"sde_version"`
-	ReleaseDate string `json:"release_date"`
-	GeneratedBy string `json:"generated_by"`
-	GeneratedAt string `json:"generated_at"`
-	Source      string `json:"source"`
-}
-
-// MetadataFileName is the name of the metadata output file.
-const MetadataFileName = "sde_metadata.json"
-
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -64,7 +56,10 @@ compatible with Wanderer's data format.`,
   sdeconvert --sde-path ./sde --output ./output
 
   # Include Wanderer passthrough files (wormholes.json, etc.)
-  sdeconvert --sde-path ./sde --output ./output --passthrough ../wanderer/priv/repo/data`,
+  sdeconvert --sde-path ./sde --output ./output --passthrough ../wanderer/priv/repo/data
+
+  # Convert only The Forge and the systems bordering it
+  sdeconvert --sde-path ./sde --output ./output --regions "The Forge" --boundary`,
 	RunE: runConversion,
 }
 
@@ -79,12 +74,37 @@ var versionCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(extractCmd)
 
 	rootCmd.Flags().StringVarP(&cfg.SDEPath, "sde-path", "s", "", "Path to SDE directory or ZIP file")
 	rootCmd.Flags().StringVarP(&cfg.OutputDir, "output", "o", "./output", "Output directory for output files")
 	rootCmd.Flags().BoolVarP(&cfg.DownloadSDE, "download", "d", false, "Download latest SDE from CCP")
 	rootCmd.Flags().StringVarP(&cfg.PassthroughDir, "passthrough", "p", "", "Directory with Wanderer JSON files to copy")
-	rootCmd.Flags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Enable verbose output")
+	rootCmd.Flags().StringVar(&cfg.WormholeOverlay, "wormhole-overlay", "", "Wanderer wormholes.json with hand-curated fields to merge (default: from --passthrough)")
+	rootCmd.Flags().StringVar(&cfg.OverridesDir, "overrides", "", "Directory of JSON/YAML patch files to apply to the converted data")
+	rootCmd.Flags().StringVar(&cfg.TypeSetsFile, "type-sets", "", "YAML/JSON rules selecting types for invTypes and additional type set files")
+	rootCmd.Flags().BoolVar(&cfg.ConnectivityColumns, "connectivity-columns", false, "Add gateReachable and componentID fields to solar systems and write mapGateComponents")
+	rootCmd.Flags().BoolVar(&cfg.SpaceKindColumn, "space-kind-column", false, "Add the derived spaceKind field to solar systems")
+	rootCmd.Flags().BoolVar(&cfg.SecurityColumns, "security-columns", false, "Add derived trueSecurity, displaySecurity and securityBand fields to solar systems")
+	rootCmd.Flags().BoolVar(&cfg.EnrichedJumps, "enriched-jumps", false, "Keep region and constellation IDs of both ends in mapSolarSystemJumps.csv")
+	rootCmd.Flags().BoolVar(&cfg.JumpNeighbors, "jump-neighbors", false, "Write every allowed jump within 10 LY to mapJumpNeighbors (enables the jumpNeighbors stage)")
+	rootCmd.Flags().BoolVar(&cfg.Chokepoints, "chokepoints", false, "Write stargate chokepoints to mapChokepoints and mapRegionChokepoints (enables the chokepoints stage)")
+	rootCmd.Flags().StringSliceVar(&cfg.DistanceAnchors, "distance-anchors", nil, "Systems to count gate jumps to in systemDistances (names or IDs; enables the systemDistances stage)")
+	rootCmd.Flags().BoolVar(&cfg.DistanceMatrix, "distance-matrix", false, "Write gate jumps between every pair of systems to "+writer.FileDistanceMatrix)
+	rootCmd.Flags().StringSliceVar(&cfg.NearestStationOwners, "nearest-station-owners", nil, "Corporations (names or IDs) whose stations nearestStations finds (enables the nearestStations stage)")
+	rootCmd.Flags().StringSliceVar(&cfg.NearestStationServices, "nearest-station-services", nil, "Station services (names or IDs) nearestStations looks for (enables the nearestStations stage)")
+	rootCmd.Flags().Int64SliceVar(&cfg.NearestStationFactions, "nearest-station-factions", nil, "Faction IDs whose corporations' stations nearestStations finds (enables the nearestStations stage)")
+	rootCmd.Flags().StringSliceVar(&cfg.EnableStages, "enable-stage", nil, "Optional transform stages to run (repeatable or comma-separated)")
+	rootCmd.Flags().StringSliceVar(&cfg.DisableStages, "disable-stage", nil, "Transform stages to skip (repeatable or comma-separated)")
+	rootCmd.Flags().BoolVar(&cfg.Reproducible, "reproducible", false, "Write byte-for-byte reproducible output with a SHA-256 manifest (honours SOURCE_DATE_EPOCH)")
+	rootCmd.Flags().BoolVar(&cfg.Strict, "strict", false, "Fail when passthrough files violate their schema or reference unknown data")
+	rootCmd.Flags().StringVar(&cfg.BaselineDir, "baseline", "", "Previous output directory to compare against; fails on unexpected removals or changes")
+	rootCmd.Flags().StringVar(&cfg.BaselineAllowFile, "baseline-allow", "", "YAML/JSON list of IDs expected to change since the baseline")
+	rootCmd.Flags().Float64Var(&cfg.BaselineMaxRemoved, "baseline-max-removed", config.DefaultBaselineMaxRemoved, "Percentage of a table's baseline IDs that may be removed")
+	rootCmd.Flags().Float64Var(&cfg.BaselineMaxChanged, "baseline-max-changed", config.DefaultBaselineMaxChanged, "Percentage of a table's baseline rows that may be added, removed or modified")
+	addSelectionFlags(rootCmd)
+	addLoggingFlags(rootCmd)
+	rootCmd.Flags().StringVar(&cfg.ReportFile, "report", "", "Write a JSON report of stage durations, record counts, warnings, errors and output files")
 	rootCmd.Flags().BoolVar(&cfg.PrettyPrint, "pretty", true, "Pretty-print JSON output (only applies to JSON format)")
 	rootCmd.Flags().StringVar(&cfg.SDEUrl, "sde-url", config.SDELatestURL, "URL to download SDE from")
 
@@ -103,48 +123,125 @@ func init() {
 	}
 }
 
-func runConversion(cmd *cobra.Command, args []string) error {
-	// Set the version for User-Agent headers
-	cfg.Version = Version
+// addSelectionFlags registers the flags selecting part of New Eden.
+func addSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&cfg.Regions, "regions", nil, "Only keep these regions (names or IDs, repeatable or comma-separated)")
+	cmd.Flags().StringSliceVar(&cfg.Constellations, "constellations", nil, "Only keep these constellations (names or IDs, repeatable or comma-separated)")
+	cmd.Flags().StringSliceVar(&cfg.Systems, "systems", nil, "Only keep these solar systems (names or IDs, repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&cfg.BoundarySystems, "boundary", false, "Also keep systems one jump outside the selected regions, constellations and systems")
+}
 
-	if err := cfg.Validate(); err != nil {
-		return err
+// addLoggingFlags registers the verbosity and log format flags.
+func addLoggingFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Enable verbose output (debug-level logging)")
+	cmd.Flags().StringVar(&cfg.LogFormat, "log-format", logging.FormatText, "Log format: text or json")
+	cmd.Flags().StringVar(&cfg.LogLevel, "log-level", "", "Minimum log level: debug, info, warn or error (default: info, debug with --verbose)")
+}
+
+// selection returns the subset selected by the --regions, --constellations,
+// --systems and --boundary flags.
+func selection() subset.Selection {
+	return subset.Selection{
+		Regions:        cfg.Regions,
+		Constellations: cfg.Constellations,
+		Systems:        cfg.Systems,
+		Boundary:       cfg.BoundarySystems,
 	}
+}
 
-	// Setup context with cancellation for graceful shutdown
+// interruptContext returns a context cancelled on SIGINT or SIGTERM, and a
+// function releasing it.
+func interruptContext(logger *slog.Logger) (context.Context, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
 
-	// Handle interrupt signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	defer signal.Stop(sigChan)
 	go func() {
 		select {
 		case <-sigChan:
-			fmt.Println("\nInterrupt received, shutting down...")
+			logger.Warn("interrupt received, shutting down")
 			cancel()
 		case <-ctx.Done():
 			// Context already cancelled, exit goroutine
 		}
 	}()
 
+	return ctx, func() {
+		signal.Stop(sigChan)
+		cancel()
+	}
+}
+
+func runConversion(cmd *cobra.Command, args []string) error {
+	// Set the version for User-Agent headers
+	cfg.Version = Version
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	logger, err := newLogger()
+	if err != nil {
+		return err
+	}
+
+	// Setup context with cancellation for graceful shutdown
+	ctx, stop := interruptContext(logger)
+	defer stop()
+
+	rep := report.New(Version, cfg.OutputDir, time.Now())
+	err = convert(ctx, logger, rep)
+
+	if cfg.ReportFile != "" {
+		rep.Finish(err)
+		if reportErr := rep.Write(cfg.ReportFile); reportErr != nil {
+			if err == nil {
+				return reportErr
+			}
+			logger.Error("could not write report", "error", reportErr)
+		}
+	}
+
+	return err
+}
+
+// newLogger builds the logger from the --log-format and --log-level flags.
+// Records go to stderr; --verbose lowers the default level to debug.
+func newLogger() (*slog.Logger, error) {
+	level := slog.LevelInfo
 	if cfg.Verbose {
-		fmt.Println("Configuration:")
-		fmt.Printf("  SDE Path:     %s\n", cfg.SDEPath)
-		fmt.Printf("  Output Dir:   %s\n", cfg.OutputDir)
-		fmt.Printf("  Output Format: %s\n", cfg.OutputFormat)
-		fmt.Printf("  Download:     %v\n", cfg.DownloadSDE)
-		fmt.Printf("  Passthrough:  %s\n", cfg.PassthroughDir)
+		level = slog.LevelDebug
+	}
+	if cfg.LogLevel != "" {
+		var err error
+		if level, err = logging.ParseLevel(cfg.LogLevel); err != nil {
+			return nil, err
+		}
 	}
+	return logging.New(os.Stderr, cfg.LogFormat, level)
+}
+
+// convert runs the conversion pipeline, recording its progress in rep.
+func convert(ctx context.Context, logger *slog.Logger, rep *report.Report) error {
+	logger.Debug("configuration",
+		"sdePath", cfg.SDEPath,
+		"outputDir", cfg.OutputDir,
+		"format", cfg.OutputFormat,
+		"download", cfg.DownloadSDE,
+		"passthrough", cfg.PassthroughDir,
+		"wormholeOverlay", cfg.WormholeOverlay,
+		"overrides", cfg.OverridesDir,
+		"typeSets", cfg.TypeSetsFile,
+		"baseline", cfg.BaselineDir)
 
 	sdePath := cfg.SDEPath
 	var versionInfo *downloader.VersionInfo
 
 	// Step 1: Download SDE if requested
 	if cfg.DownloadSDE {
-		dl := downloader.New(cfg)
-		vc := downloader.NewVersionChecker(cfg)
+		start := time.Now()
+		dl := downloader.New(cfg, logger)
+		vc := downloader.NewVersionChecker(cfg, logger)
 
 		// Default SDE path when downloading is <output-dir>/sde
 		if sdePath == "" {
@@ -156,9 +253,12 @@ func runConversion(cmd *cobra.Command, args []string) error {
 		var err error
 		needsUpdate, versionInfo, err = vc.NeedsUpdate(ctx, cfg.OutputDir)
 		if err != nil {
-			fmt.Printf("Warning: could not check SDE version: %v\n", err)
+			logger.Warn("could not check SDE version", "error", err)
 			needsUpdate = true // Proceed with download anyway
 		}
+		if versionInfo != nil {
+			rep.SDEVersion = versionInfo.BuildNumber
+		}
 
 		// Also check if the SDE directory exists
 		if _, err := os.Stat(sdePath); os.IsNotExist(err) {
@@ -166,7 +266,7 @@ func runConversion(cmd *cobra.Command, args []string) error {
 		}
 
 		if needsUpdate {
-			fmt.Println("Downloading latest SDE...")
+			logger.Info("downloading latest SDE", "url", cfg.SDEUrl)
 
 			// Download to a temp location first
 			downloadedPath, err := dl.DownloadAndExtract(ctx)
@@ -176,7 +276,7 @@ func runConversion(cmd *cobra.Command, args []string) error {
 
 			// Remove old SDE directory if it exists
 			if err := os.RemoveAll(sdePath); err != nil && !os.IsNotExist(err) {
-				fmt.Printf("Warning: could not remove old SDE: %v\n", err)
+				logger.Warn("could not remove old SDE", "path", sdePath, "error", err)
 			}
 
 			// Move downloaded SDE to the persistent location
@@ -190,17 +290,18 @@ func runConversion(cmd *cobra.Command, args []string) error {
 				_ = os.RemoveAll(downloadedPath)
 			}
 
-			fmt.Printf("SDE downloaded and extracted to: %s\n", sdePath)
+			logger.Info("SDE downloaded and extracted", "path", sdePath)
 
 			// Store the version
 			if versionInfo != nil {
 				if err := vc.StoreVersion(cfg.OutputDir, versionInfo.BuildNumber); err != nil {
-					fmt.Printf("Warning: could not store version: %v\n", err)
+					logger.Warn("could not store SDE version", "error", err)
 				}
 			}
 		} else {
-			fmt.Println("SDE is up to date, using cached version")
+			logger.Info("SDE is up to date, using cached version")
 		}
+		rep.AddPhase("download", start)
 	}
 
 	if sdePath == "" {
@@ -208,90 +309,172 @@ func runConversion(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate the SDE structure
-	dl := downloader.New(cfg)
+	dl := downloader.New(cfg, logger)
 	if err := dl.Validate(sdePath); err != nil {
 		return fmt.Errorf("SDE validation failed: %w", err)
 	}
 
-	fmt.Printf("Using SDE at: %s\n", sdePath)
+	logger.Info("using SDE", "path", sdePath)
 
 	// Step 2: Parse SDE YAML files
-	p := parser.New(cfg, sdePath)
-	parseResult, err := p.ParseAll()
+	start := time.Now()
+	p := parser.New(cfg, sdePath, logger)
+	parseResult, err := p.ParseAll(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to parse SDE: %w", err)
 	}
-
-	fmt.Printf("\nParsing complete:\n")
-	fmt.Printf("  Regions:         %d\n", len(parseResult.Regions))
-	fmt.Printf("  Constellations:  %d\n", len(parseResult.Constellations))
-	fmt.Printf("  Solar Systems:   %d\n", len(parseResult.SolarSystems))
-	fmt.Printf("  Types:           %d\n", len(parseResult.Types))
-	fmt.Printf("  Groups:          %d\n", len(parseResult.Groups))
-	fmt.Printf("  Categories:      %d\n", len(parseResult.Categories))
-	fmt.Printf("  Wormhole Classes: %d\n", len(parseResult.WormholeClasses))
-	fmt.Printf("  System Jumps:    %d\n", len(parseResult.SystemJumps))
+	rep.AddPhase("parse", start)
+
+	logger.Info("parsing complete",
+		"regions", len(parseResult.Regions),
+		"constellations", len(parseResult.Constellations),
+		"solarSystems", len(parseResult.SolarSystems),
+		"types", len(parseResult.Types),
+		"groups", len(parseResult.Groups),
+		"categories", len(parseResult.Categories),
+		"wormholeClasses", len(parseResult.WormholeClasses),
+		"systemJumps", len(parseResult.SystemJumps),
+		"typeDogma", len(parseResult.TypeDogma))
 
 	// Step 3: Transform data
-	t := transformer.New(cfg)
-	convertedData, err := t.Transform(parseResult)
+	start = time.Now()
+	t := transformer.New(cfg, logger)
+	convertedData, err := t.Transform(ctx, parseResult)
+	for _, timing := range t.StageTimings() {
+		rep.AddStage(timing.Name, timing.Duration)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to transform data: %w", err)
 	}
+	rep.AddPhase("transform", start)
+
+	// Local overrides were applied during the transform
+	appliedPatches := t.AppliedOverrides()
+	if cfg.OverridesDir != "" {
+		logger.Info("applied overrides", "applied", len(appliedPatches))
+	}
+
+	// Cut the data down to the selected regions, constellations and systems
+	if sel := selection(); !sel.IsEmpty() {
+		set, err := subset.Resolve(sel, convertedData.Universe, convertedData.SystemJumps)
+		if err != nil {
+			return fmt.Errorf("invalid selection: %w", err)
+		}
+		subset.Filter(convertedData, set)
+		logger.Info("selected subset",
+			"systems", len(set.Systems),
+			"boundarySystems", len(set.Boundary),
+			"constellations", len(set.Constellations),
+			"regions", len(set.Regions))
+	}
+	rep.SetCounts(convertedData)
 
 	// Validate the converted data
+	start = time.Now()
 	validationResult := t.Validate(convertedData)
-	fmt.Printf("\nValidation results:\n")
-	fmt.Printf("  Regions:         %d\n", validationResult.Regions)
-	fmt.Printf("  Constellations:  %d\n", validationResult.Constellations)
-	fmt.Printf("  Solar Systems:   %d\n", validationResult.SolarSystems)
-	fmt.Printf("  Types:           %d\n", validationResult.InvTypes)
-	fmt.Printf("  Groups:          %d\n", validationResult.InvGroups)
-	fmt.Printf("  Wormhole Classes: %d\n", validationResult.WormholeClasses)
-	fmt.Printf("  System Jumps:    %d\n", validationResult.SystemJumps)
-
-	if len(validationResult.Warnings) > 0 {
-		fmt.Println("\nWarnings:")
-		for _, warning := range validationResult.Warnings {
-			fmt.Printf("  - %s\n", warning)
+
+	// Check passthrough files before anything is written, so --strict
+	// fails early and the report lists every violation
+	if cfg.PassthroughDir != "" {
+		findings, err := writer.CheckPassthroughFiles(cfg, cfg.PassthroughDir, writer.NewReferenceData(convertedData))
+		if err != nil {
+			return fmt.Errorf("failed to check passthrough files: %w", err)
 		}
+		validationResult.Findings = append(validationResult.Findings, findings...)
+		for _, f := range findings {
+			message := fmt.Sprintf("[%s] %s", f.Code, f.Message)
+			if f.Severity == models.SeverityError {
+				validationResult.Errors = append(validationResult.Errors, message)
+			} else {
+				validationResult.Warnings = append(validationResult.Warnings, message)
+			}
+		}
+	}
+	rep.AddPhase("validate", start)
+	rep.SetValidation(validationResult)
+
+	logger.Info("validation complete",
+		"regions", validationResult.Regions,
+		"constellations", validationResult.Constellations,
+		"solarSystems", validationResult.SolarSystems,
+		"types", validationResult.InvTypes,
+		"groups", validationResult.InvGroups,
+		"wormholeClasses", validationResult.WormholeClasses,
+		"systemJumps", validationResult.SystemJumps)
+
+	for _, warning := range validationResult.Warnings {
+		logger.Warn(warning)
 	}
 
 	if len(validationResult.Errors) > 0 {
-		fmt.Println("\nErrors:")
 		for _, err := range validationResult.Errors {
-			fmt.Printf("  - %s\n", err)
+			logger.Error(err)
 		}
 		return fmt.Errorf("validation failed with %d errors", len(validationResult.Errors))
 	}
 
 	// Step 4: Write output files
-	w, err := writer.NewWriter(cfg)
+	start = time.Now()
+	w, err := writer.NewWriter(cfg, logger)
 	if err != nil {
 		return fmt.Errorf("failed to create writer: %w", err)
 	}
-	if err := w.WriteAll(convertedData); err != nil {
+	err = w.WriteAll(ctx, convertedData)
+	rep.AddOutputFiles(w.Files()...)
+	if err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
 
 	// Step 5: Write metadata file
-	if versionInfo != nil {
-		if err := writeMetadata(cfg.OutputDir, versionInfo); err != nil {
-			fmt.Printf("Warning: could not write metadata file: %v\n", err)
-		} else if cfg.Verbose {
-			fmt.Printf("  Wrote %s\n", MetadataFileName)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	generatedAt, err := writer.MetadataTime(cfg, versionInfo)
+	if err != nil {
+		return err
+	}
+	metadataWritten := false
+	if versionInfo != nil || len(appliedPatches) > 0 {
+		if err := writer.WriteMetadata(cfg.OutputDir, generatedAt, versionInfo, appliedPatches); err != nil {
+			logger.Warn("could not write metadata file", "error", err)
+		} else {
+			metadataWritten = true
+			rep.AddOutputFiles(writer.MetadataFileName)
+			logger.Debug("wrote file", "file", writer.MetadataFileName)
 		}
 	}
+	rep.AddPhase("write", start)
 
 	// Step 6: Copy passthrough files
 	if cfg.PassthroughDir != "" {
-		if err := w.CopyPassthroughFiles(cfg.PassthroughDir); err != nil {
+		start = time.Now()
+		copied := len(w.Files())
+		err := w.CopyPassthroughFiles(ctx, cfg.PassthroughDir)
+		rep.AddOutputFiles(w.Files()[copied:]...)
+		if err != nil {
 			return fmt.Errorf("failed to copy passthrough files: %w", err)
 		}
+		rep.AddPhase("passthrough", start)
+	}
+
+	// Step 7: Write the manifest last so it covers every file this run wrote
+	if cfg.Reproducible {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		files := w.Files()
+		if metadataWritten {
+			files = append(files, writer.MetadataFileName)
+		}
+		count, err := writer.WriteManifest(cfg.OutputDir, files)
+		if err != nil {
+			return err
+		}
+		rep.AddOutputFiles(writer.ManifestFileName)
+		logger.Debug("wrote file", "file", writer.ManifestFileName, "entries", count)
 	}
 
-	fmt.Printf("\nConversion complete! Output written to: %s\n", cfg.OutputDir)
-	fmt.Printf("Generated files (%s format):\n", cfg.OutputFormat)
+	logger.Info("conversion complete", "outputDir", cfg.OutputDir, "format", cfg.OutputFormat)
 
 	outputFiles := writer.GetOutputFiles(cfg.OutputFormat)
 	counts := []int{
@@ -316,7 +499,29 @@ func runConversion(cmd *cobra.Command, args []string) error {
 	}
 
 	for i := 0; i < minLen; i++ {
-		fmt.Printf("  - %s (%d %s)\n", outputFiles[i], counts[i], labels[i])
+		logger.Info("generated file", "file", outputFiles[i], labels[i], counts[i])
+	}
+
+	for _, set := range convertedData.TypeSets {
+		logger.Info("generated file", "file", writer.TypeSetFile(set.Name, cfg.OutputFormat), "types", len(set.Types))
+	}
+	for _, dataset := range convertedData.Datasets {
+		logger.Info("generated file", "file", writer.DatasetFile(dataset.Name, cfg.OutputFormat), "rows", len(dataset.Rows))
+	}
+	if len(convertedData.Wormholes) > 0 {
+		logger.Info("generated file", "file", writer.FileWormholes, "wormholeTypes", len(convertedData.Wormholes))
+	}
+	if len(convertedData.SunTypes) > 0 {
+		logger.Info("generated file", "file", writer.FileSunTypes, "sunTypes", len(convertedData.SunTypes))
+	}
+	if len(convertedData.TriglavianSystems) > 0 {
+		logger.Info("generated file", "file", writer.FileTriglavianSystems, "systems", len(convertedData.TriglavianSystems))
+	}
+	if len(convertedData.ShatteredConstellations) > 0 {
+		logger.Info("generated file", "file", writer.FileShatteredConstellations, "constellations", len(convertedData.ShatteredConstellations))
+	}
+	if len(convertedData.SystemEffects) > 0 {
+		logger.Info("generated file", "file", writer.FileSystemEffects, "systems", len(convertedData.SystemEffects))
 	}
 
 	return nil
@@ -367,26 +572,3 @@ func copyFile(src, dst string) error {
 	}
 	return dstFile.Close()
 }
-
-// writeMetadata writes the SDE metadata file to the output directory.
-func writeMetadata(outputDir string, versionInfo *downloader.VersionInfo) error {
-	metadata := SDEMetadata{
-		SDEVersion:  versionInfo.BuildNumber,
-		ReleaseDate: versionInfo.ReleaseDate,
-		GeneratedBy: "wanderer-sde",
-		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-		Source:      "https://developers.eveonline.com/static-data",
-	}
-
-	data, err := json.MarshalIndent(metadata, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
-	}
-
-	metadataPath := filepath.Join(outputDir, MetadataFileName)
-	if err := os.WriteFile(metadataPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write metadata file: %w", err)
-	}
-
-	return nil
-}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/guarzo/wanderer-sde/internal/downloader"
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+	"github.com/guarzo/wanderer-sde/internal/transformer"
+)
+
+var routeFlags struct {
+	from            string
+	to              string
+	prefer          string
+	avoid           []string
+	securityPenalty float64
+	overlay         string
+	shipSize        string
+}
+
+var routeCmd = &cobra.Command{
+	Use:   "route",
+	Short: "Plan a stargate route between two systems",
+	Long: `Plans a stargate route between two solar systems and prints each
+system on it with its security status and region.
+
+--prefer selects the autopilot weighting: shortest (fewest jumps), safer
+(avoid low and null security space) or less-secure (avoid high security
+space). --security-penalty scales how strongly safer and less-secure avoid
+the other kind of space, as in-game. Systems named with --avoid are never
+entered.
+
+--overlay adds wormhole, jump bridge and Thera connections from a JSON file
+to the stargates; expired connections are ignored and --ship-size leaves
+out connections too small for the ship.`,
+	Example: `  # Safest route from Jita to Amarr
+  sdeconvert route --sde-path ./sde --from Jita --to Amarr --prefer safer
+
+  # Shortest route avoiding Uedama and Niarja
+  sdeconvert route --sde-path ./sde --from Jita --to Amarr --avoid Uedama,Niarja
+
+  # Route a battleship through the current wormhole chain
+  sdeconvert route --sde-path ./sde --from Jita --to Amarr --overlay connections.json --ship-size large`,
+	RunE: runRoute,
+}
+
+func init() {
+	rootCmd.AddCommand(routeCmd)
+
+	routeCmd.Flags().StringVarP(&cfg.SDEPath, "sde-path", "s", "", "Path to the SDE directory")
+	routeCmd.Flags().StringVar(&routeFlags.from, "from", "", "Origin system (name or ID)")
+	routeCmd.Flags().StringVar(&routeFlags.to, "to", "", "Destination system (name or ID)")
+	routeCmd.Flags().StringVar(&routeFlags.prefer, "prefer", string(graph.PreferShortest), "Route preference: shortest, safer or less-secure")
+	routeCmd.Flags().StringSliceVar(&routeFlags.avoid, "avoid", nil, "Systems the route must not enter (names or IDs, repeatable or comma-separated)")
+	routeCmd.Flags().Float64Var(&routeFlags.securityPenalty, "security-penalty", graph.DefaultSecurityPenalty, "Autopilot security penalty from 0 to 100 for safer and less-secure routes")
+	routeCmd.Flags().StringVar(&routeFlags.overlay, "overlay", "", "JSON file of wormhole, jump bridge and Thera connections to route through")
+	routeCmd.Flags().StringVar(&routeFlags.shipSize, "ship-size", "", "Ship size for overlay connections: small, medium, large, xlarge or capital")
+	addLoggingFlags(routeCmd)
+}
+
+func runRoute(cmd *cobra.Command, args []string) error {
+	if cfg.SDEPath == "" {
+		return fmt.Errorf("--sde-path must be specified")
+	}
+	if routeFlags.from == "" || routeFlags.to == "" {
+		return fmt.Errorf("--from and --to must be specified")
+	}
+	prefer, err := graph.ParsePreference(routeFlags.prefer)
+	if err != nil {
+		return err
+	}
+	if routeFlags.securityPenalty < 0 || routeFlags.securityPenalty > 100 {
+		return fmt.Errorf("--security-penalty must be between 0 and 100")
+	}
+	shipSize, err := graph.ParseShipSize(routeFlags.shipSize)
+	if err != nil {
+		return err
+	}
+
+	cfg.Version = Version
+	logger, err := newLogger()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := interruptContext(logger)
+	defer stop()
+
+	g, regionNames, err := loadGraph(ctx, logger)
+	if err != nil {
+		return err
+	}
+	if routeFlags.overlay != "" {
+		overlay, err := graph.LoadOverlay(routeFlags.overlay)
+		if err != nil {
+			return err
+		}
+		if g, err = g.WithOverlay(overlay, time.Now()); err != nil {
+			return err
+		}
+		logger.Debug("applied overlay", "file", routeFlags.overlay, "connections", len(overlay.Connections))
+	}
+
+	from, err := g.Lookup(routeFlags.from)
+	if err != nil {
+		return err
+	}
+	to, err := g.Lookup(routeFlags.to)
+	if err != nil {
+		return err
+	}
+	opts := graph.RouteOptions{Prefer: prefer, SecurityPenalty: routeFlags.securityPenalty, ShipSize: shipSize}
+	for _, name := range routeFlags.avoid {
+		sys, err := g.Lookup(name)
+		if err != nil {
+			return err
+		}
+		opts.Avoid = append(opts.Avoid, sys.ID)
+	}
+	// A zero penalty is a valid in-game setting; it weighs every jump alike
+	if opts.SecurityPenalty == 0 {
+		opts.Prefer = graph.PreferShortest
+	}
+
+	route, err := g.Route(from.ID, to.ID, opts)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "%s to %s (%s): %d jumps\n", from.Name, to.Name, prefer, route.Jumps())
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for i, sys := range route.Systems {
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", i, sys.Name, transformer.DisplaySecurity(sys.Security), regionNames[sys.RegionID], route.Via[i])
+	}
+	return tw.Flush()
+}
+
+// loadGraph parses the solar systems and stargates of the SDE at
+// cfg.SDEPath into a graph, and returns it with region names by ID.
+func loadGraph(ctx context.Context, logger *slog.Logger) (*graph.Graph, map[int64]string, error) {
+	universe, jumps, err := loadUniverse(ctx, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	regionNames := make(map[int64]string, len(universe.Regions))
+	for _, r := range universe.Regions {
+		regionNames[r.RegionID] = r.RegionName
+	}
+
+	g := graph.New(universe.SolarSystems, jumps)
+	logger.Debug("built gate graph", "systems", g.Len(), "jumps", len(jumps))
+	return g, regionNames, nil
+}
+
+// loadUniverse validates the SDE at cfg.SDEPath and parses its regions,
+// constellations, solar systems and stargates.
+func loadUniverse(ctx context.Context, logger *slog.Logger) (*models.UniverseData, []models.SystemJump, error) {
+	if err := downloader.New(cfg, logger).Validate(cfg.SDEPath); err != nil {
+		return nil, nil, fmt.Errorf("SDE validation failed: %w", err)
+	}
+
+	p := parser.New(cfg, cfg.SDEPath, logger)
+	regions, err := p.ParseRegions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	constellations, err := p.ParseConstellations(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	systems, err := p.ParseSolarSystems(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	jumps, err := p.ParseStargates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	universe := &models.UniverseData{Regions: regions, Constellations: constellations, SolarSystems: systems}
+	return universe, jumps, nil
+}
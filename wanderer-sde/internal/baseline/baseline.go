@@ -0,0 +1,484 @@
+// Package baseline guards against regressions by comparing converted data
+// with the output of a previous run.
+package baseline
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/pkg/yaml"
+)
+
+// Baseline finding codes.
+const (
+	CodeRemovedIDs   models.FindingCode = "BASELINE_REMOVED_IDS"
+	CodeChangedIDs   models.FindingCode = "BASELINE_CHANGED_IDS"
+	CodeMissingTable models.FindingCode = "BASELINE_MISSING_TABLE"
+	CodeMainCluster  models.FindingCode = "BASELINE_MAIN_CLUSTER_CHANGED"
+)
+
+// maxListedIDs caps how many IDs are listed in a finding message.
+const maxListedIDs = 10
+
+// table describes how to read the IDs of one output table. Its values are
+// the columns of models.CSVHeaders for the table.
+type table struct {
+	name string   // Output file name without extension
+	keys []string // Columns forming the ID, joined with ":"
+}
+
+// tables lists the output tables compared against the baseline.
+var tables = []table{
+	{"mapSolarSystems", []string{"solarSystemID"}},
+	{"mapRegions", []string{"regionID"}},
+	{"mapConstellations", []string{"constellationID"}},
+	{"mapLocationWormholeClasses", []string{"locationID"}},
+	{"invTypes", []string{"typeID"}},
+	{"invGroups", []string{"groupID"}},
+	{"mapSolarSystemJumps", []string{"fromSolarSystemID", "toSolarSystemID"}},
+	{"npcStations", []string{"stationID"}},
+}
+
+// Snapshot maps the IDs in each table to a hash of the record's values.
+type Snapshot map[string]map[string]string
+
+// Thresholds bound how much a table may change relative to the baseline
+// before the comparison fails. Values are percentages of the baseline's row
+// count.
+type Thresholds struct {
+	MaxRemovedPercent float64 // IDs present in the baseline but not in the new data
+	MaxChangedPercent float64 // IDs added, removed or with changed values
+}
+
+// Allowlist holds IDs expected to be added or removed, e.g. systems CCP
+// announced it would delete. They are ignored by the comparison.
+type Allowlist map[string]map[string]bool
+
+// allowEntry is one entry of an allow-list file.
+type allowEntry struct {
+	Table  string   `yaml:"table"`
+	IDs    []string `yaml:"ids"`
+	Reason string   `yaml:"reason"`
+}
+
+// LoadAllowlist reads an allow-list file: a YAML or JSON list of entries
+// with a table, the IDs expected to change and an optional reason.
+func LoadAllowlist(path string) (Allowlist, error) {
+	var entries []allowEntry
+	if err := yaml.ParseFile(path, &entries); err != nil {
+		return nil, fmt.Errorf("failed to load baseline allow-list %s: %w", path, err)
+	}
+
+	known := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		known[t.name] = true
+	}
+
+	allow := make(Allowlist)
+	for _, entry := range entries {
+		if !known[entry.Table] {
+			return nil, fmt.Errorf("baseline allow-list %s: unknown table %q", path, entry.Table)
+		}
+		if allow[entry.Table] == nil {
+			allow[entry.Table] = make(map[string]bool)
+		}
+		for _, id := range entry.IDs {
+			allow[entry.Table][id] = true
+		}
+	}
+	return allow, nil
+}
+
+// Load reads the records of every table from a previous output directory.
+// Each table is read from its CSV file, or its JSON file if there is no CSV.
+// Tables with neither file are left out of the snapshot.
+func Load(dir string) (Snapshot, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to read baseline directory %s: %w", dir, err)
+	}
+
+	snapshot := make(Snapshot)
+	for _, t := range tables {
+		csvPath := filepath.Join(dir, t.name+".csv")
+		jsonPath := filepath.Join(dir, t.name+".json")
+
+		var ids map[string]string
+		var err error
+		switch {
+		case fileExists(csvPath):
+			ids, err = readCSVRecords(csvPath, t.keys, models.CSVHeaders[t.name])
+		case fileExists(jsonPath):
+			ids, err = readJSONRecords(jsonPath, t.keys, models.CSVHeaders[t.name])
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		snapshot[t.name] = ids
+	}
+
+	return snapshot, nil
+}
+
+// FromConvertedData builds a snapshot of the records in converted data.
+func FromConvertedData(data *models.ConvertedData) Snapshot {
+	id := func(values ...int64) string {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = strconv.FormatInt(v, 10)
+		}
+		return strings.Join(parts, ":")
+	}
+
+	snapshot := make(Snapshot, len(tables))
+	for _, t := range tables {
+		snapshot[t.name] = make(map[string]string)
+	}
+
+	if data.Universe != nil {
+		for i := range data.Universe.SolarSystems {
+			s := &data.Universe.SolarSystems[i]
+			snapshot["mapSolarSystems"][id(s.SolarSystemID)] = hashValues(s.ToCSVRow())
+		}
+		for i := range data.Universe.Regions {
+			r := &data.Universe.Regions[i]
+			snapshot["mapRegions"][id(r.RegionID)] = hashValues(r.ToCSVRow())
+		}
+		for i := range data.Universe.Constellations {
+			c := &data.Universe.Constellations[i]
+			snapshot["mapConstellations"][id(c.ConstellationID)] = hashValues(c.ToCSVRow())
+		}
+	}
+	for i := range data.WormholeClasses {
+		wc := &data.WormholeClasses[i]
+		snapshot["mapLocationWormholeClasses"][id(wc.LocationID)] = hashValues(wc.ToCSVRow())
+	}
+	for i := range data.InvTypes {
+		typ := &data.InvTypes[i]
+		snapshot["invTypes"][id(typ.TypeID)] = hashValues(typ.ToCSVRow())
+	}
+	for i := range data.InvGroups {
+		g := &data.InvGroups[i]
+		snapshot["invGroups"][id(g.GroupID)] = hashValues(g.ToCSVRow())
+	}
+	for i := range data.SystemJumps {
+		j := &data.SystemJumps[i]
+		snapshot["mapSolarSystemJumps"][id(j.FromSolarSystemID, j.ToSolarSystemID)] = hashValues(j.ToCSVRow())
+	}
+	for i := range data.NPCStations {
+		st := &data.NPCStations[i]
+		snapshot["npcStations"][id(st.StationID)] = hashValues(st.ToCSVRow())
+	}
+
+	return snapshot
+}
+
+// Compare compares current data with the baseline table by table. A row
+// has changed when an ID was added or removed or its values differ.
+// Removals or total changes above the thresholds are errors; smaller
+// removals are warnings and other smaller changes are informational.
+// Allow-listed IDs are ignored.
+func Compare(previous, current Snapshot, thresholds Thresholds, allow Allowlist) []models.Finding {
+	var findings []models.Finding
+
+	for _, t := range tables {
+		prevIDs, ok := previous[t.name]
+		if !ok {
+			findings = append(findings, models.Finding{
+				Code:     CodeMissingTable,
+				Severity: models.SeverityInfo,
+				Table:    t.name,
+				Message:  fmt.Sprintf("%s: not in baseline, skipped", t.name),
+			})
+			continue
+		}
+		currIDs := current[t.name]
+
+		removed := difference(prevIDs, currIDs, allow[t.name])
+		added := difference(currIDs, prevIDs, allow[t.name])
+		changed := modified(prevIDs, currIDs, allow[t.name])
+		if len(removed) == 0 && len(added) == 0 && len(changed) == 0 {
+			continue
+		}
+
+		removedPct := percent(len(removed), len(prevIDs))
+		changedPct := percent(len(removed)+len(added)+len(changed), len(prevIDs))
+
+		if len(removed) > 0 {
+			severity := models.SeverityWarning
+			if removedPct > thresholds.MaxRemovedPercent {
+				severity = models.SeverityError
+			}
+			findings = append(findings, models.Finding{
+				Code:     CodeRemovedIDs,
+				Severity: severity,
+				Table:    t.name,
+				Message: fmt.Sprintf("%s: %d of %d baseline IDs removed (%.2f%%, limit %.2f%%): %s",
+					t.name, len(removed), len(prevIDs), removedPct, thresholds.MaxRemovedPercent, listIDs(removed)),
+			})
+		}
+
+		severity := models.SeverityInfo
+		if changedPct > thresholds.MaxChangedPercent {
+			severity = models.SeverityError
+		}
+		findings = append(findings, models.Finding{
+			Code:     CodeChangedIDs,
+			Severity: severity,
+			Table:    t.name,
+			Message: fmt.Sprintf("%s: %d -> %d rows, %d added, %d removed, %d modified (%.2f%% changed, limit %.2f%%)",
+				t.name, len(prevIDs), len(currIDs), len(added), len(removed), len(changed), changedPct, thresholds.MaxChangedPercent),
+		})
+	}
+
+	return findings
+}
+
+// CompareMainCluster warns when the largest group of systems connected by
+// stargates, i.e. known space, has a different size than in the baseline.
+// A new or removed gate that joins or cuts off part of the map shows up
+// here even when the jump table barely changed. It is skipped when the
+// baseline lacks the systems or jumps table.
+func CompareMainCluster(previous, current Snapshot) []models.Finding {
+	before, ok := mainClusterSize(previous)
+	if !ok {
+		return nil
+	}
+	after, _ := mainClusterSize(current)
+	if before == after {
+		return nil
+	}
+	return []models.Finding{{
+		Code:     CodeMainCluster,
+		Severity: models.SeverityWarning,
+		Table:    "mapSolarSystemJumps",
+		Message:  fmt.Sprintf("main stargate cluster: %d -> %d systems", before, after),
+	}}
+}
+
+// mainClusterSize returns the number of systems in the largest stargate
+// component of a snapshot.
+func mainClusterSize(s Snapshot) (int, bool) {
+	systemIDs, okSystems := s["mapSolarSystems"]
+	jumpIDs, okJumps := s["mapSolarSystemJumps"]
+	if !okSystems || !okJumps {
+		return 0, false
+	}
+
+	systems := make([]models.SolarSystem, 0, len(systemIDs))
+	for id := range systemIDs {
+		if n, err := strconv.ParseInt(id, 10, 64); err == nil {
+			systems = append(systems, models.SolarSystem{SolarSystemID: n})
+		}
+	}
+	jumps := make([]models.SystemJump, 0, len(jumpIDs))
+	for id := range jumpIDs {
+		from, to, found := strings.Cut(id, ":")
+		if !found {
+			continue
+		}
+		fromID, errFrom := strconv.ParseInt(from, 10, 64)
+		toID, errTo := strconv.ParseInt(to, 10, 64)
+		if errFrom == nil && errTo == nil {
+			jumps = append(jumps, models.SystemJump{FromSolarSystemID: fromID, ToSolarSystemID: toID})
+		}
+	}
+
+	components := graph.New(systems, jumps).Components()
+	if len(components) == 0 {
+		return 0, true
+	}
+	return len(components[0]), true
+}
+
+// difference returns the sorted IDs in a but not in b, skipping allowed IDs.
+func difference(a, b map[string]string, allowed map[string]bool) []string {
+	var result []string
+	for id := range a {
+		if _, ok := b[id]; !ok && !allowed[id] {
+			result = append(result, id)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// modified returns the sorted IDs in both a and b whose values differ,
+// skipping allowed IDs.
+func modified(a, b map[string]string, allowed map[string]bool) []string {
+	var result []string
+	for id, hash := range a {
+		if other, ok := b[id]; ok && other != hash && !allowed[id] {
+			result = append(result, id)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// hashValues hashes the values of a record in CSV column order. Numbers are
+// compared by value and "None" as empty, so CSV and JSON output of the same
+// record hash alike.
+func hashValues(values []string) string {
+	h := fnv.New64a()
+	for _, v := range values {
+		if v == "None" {
+			v = ""
+		} else if f, err := strconv.ParseFloat(v, 64); err == nil {
+			v = strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		_, _ = h.Write([]byte(v))
+		_, _ = h.Write([]byte{0})
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// percent returns n as a percentage of total. Any change to an empty
+// baseline table counts as 100%.
+func percent(n, total int) float64 {
+	if total == 0 {
+		if n == 0 {
+			return 0
+		}
+		return 100
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// listIDs formats up to maxListedIDs IDs.
+func listIDs(ids []string) string {
+	if len(ids) <= maxListedIDs {
+		return strings.Join(ids, ", ")
+	}
+	return fmt.Sprintf("%s, ... and %d more", strings.Join(ids[:maxListedIDs], ", "), len(ids)-maxListedIDs)
+}
+
+// readCSVRecords reads a CSV file with a header row and maps the IDs formed
+// by the key columns to a hash of the value columns.
+func readCSVRecords(path string, keys, values []string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open baseline file %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header of baseline file %s: %w", path, err)
+	}
+
+	keyColumns, err := findColumns(path, header, keys)
+	if err != nil {
+		return nil, err
+	}
+	valueColumns, err := findColumns(path, header, values)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]string)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read baseline file %s: %w", path, err)
+		}
+
+		if len(record) != len(header) {
+			return nil, fmt.Errorf("baseline file %s has a short row", path)
+		}
+		parts := make([]string, len(keyColumns))
+		for i, col := range keyColumns {
+			parts[i] = record[col]
+		}
+		fields := make([]string, len(valueColumns))
+		for i, col := range valueColumns {
+			fields[i] = record[col]
+		}
+		ids[strings.Join(parts, ":")] = hashValues(fields)
+	}
+
+	return ids, nil
+}
+
+// findColumns returns the index of each named column in a CSV header.
+func findColumns(path string, header, names []string) ([]int, error) {
+	columns := make([]int, len(names))
+	for i, name := range names {
+		columns[i] = -1
+		for j, column := range header {
+			if column == name {
+				columns[i] = j
+			}
+		}
+		if columns[i] < 0 {
+			return nil, fmt.Errorf("baseline file %s has no %s column", path, name)
+		}
+	}
+	return columns, nil
+}
+
+// readJSONRecords reads a JSON array of objects and maps the IDs formed by
+// the key fields to a hash of the value fields. Missing and null fields
+// hash as empty.
+func readJSONRecords(path string, keys, values []string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file %s: %w", path, err)
+	}
+
+	var entries []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline file %s: %w", path, err)
+	}
+
+	ids := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := make([]string, len(keys))
+		for i, key := range keys {
+			n, ok := entry[key].(json.Number)
+			if !ok {
+				return nil, fmt.Errorf("baseline file %s has an entry without numeric %s", path, key)
+			}
+			parts[i] = n.String()
+		}
+		fields := make([]string, len(values))
+		for i, name := range values {
+			switch v := entry[name].(type) {
+			case nil:
+			case string:
+				fields[i] = v
+			case bool:
+				fields[i] = models.FormatBool(v)
+			default:
+				fields[i] = fmt.Sprint(v)
+			}
+		}
+		ids[strings.Join(parts, ":")] = hashValues(fields)
+	}
+
+	return ids, nil
+}
+
+// fileExists reports whether path exists and is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
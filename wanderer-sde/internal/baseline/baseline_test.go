@@ -0,0 +1,303 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "baseline_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	writeFiles(t, tmpDir, map[string]string{
+		"mapRegions.csv":          "regionID,regionName\n10000002,The Forge\n10000043,Domain\n",
+		"mapSolarSystems.csv":     "solarSystemID,solarSystemName,regionID,constellationID,security,sunTypeID\n30000142,Jita,10000002,20000020,0.9459131166648389,None\n",
+		"mapSolarSystemJumps.csv": "fromRegionID,fromConstellationID,fromSolarSystemID,toSolarSystemID,toConstellationID,toRegionID\n1,2,30000142,30000144,2,1\n",
+		"npcStations.json":        `[{"stationID": 60003760, "solarSystemID": 30000142, "ownerID": 1000035, "ownerName": "Caldari Navy", "typeID": 1529}]`,
+	})
+
+	snapshot, err := Load(tmpDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if _, ok := snapshot["mapRegions"]["10000043"]; !ok || len(snapshot["mapRegions"]) != 2 {
+		t.Errorf("Unexpected regions: %v", snapshot["mapRegions"])
+	}
+	if _, ok := snapshot["mapSolarSystemJumps"]["30000142:30000144"]; !ok {
+		t.Errorf("Expected jump key from:to, got %v", snapshot["mapSolarSystemJumps"])
+	}
+	if _, ok := snapshot["mapConstellations"]; ok {
+		t.Error("Expected missing tables to be left out of the snapshot")
+	}
+
+	// The same records in converted data hash alike, from CSV and JSON
+	data := &models.ConvertedData{
+		Universe: &models.UniverseData{
+			SolarSystems: []models.SolarSystem{{
+				SolarSystemID: 30000142, SolarSystemName: "Jita", RegionID: 10000002,
+				ConstellationID: 20000020, Security: 0.9459131166648389,
+			}},
+		},
+		NPCStations: []models.NPCStation{{
+			StationID: 60003760, SolarSystemID: 30000142, OwnerID: 1000035, OwnerName: "Caldari Navy", TypeID: 1529,
+		}},
+	}
+	current := FromConvertedData(data)
+	if got, want := current["mapSolarSystems"]["30000142"], snapshot["mapSolarSystems"]["30000142"]; got != want {
+		t.Errorf("Expected CSV system hash %s, got %s", want, got)
+	}
+	if got, want := current["npcStations"]["60003760"], snapshot["npcStations"]["60003760"]; got != want {
+		t.Errorf("Expected JSON station hash %s, got %s", want, got)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	if _, err := Load("/nonexistent/baseline"); err == nil {
+		t.Error("Expected error for missing baseline directory")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "baseline_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	writeFiles(t, tmpDir, map[string]string{"mapRegions.csv": "id,name\n1,Test\n"})
+	_, err = Load(tmpDir)
+	if err == nil || !strings.Contains(err.Error(), "no regionID column") {
+		t.Errorf("Expected missing column error, got %v", err)
+	}
+}
+
+func TestFromConvertedData(t *testing.T) {
+	data := &models.ConvertedData{
+		Universe: &models.UniverseData{
+			SolarSystems: []models.SolarSystem{{SolarSystemID: 30000142}},
+		},
+		SystemJumps: []models.SystemJump{{FromSolarSystemID: 30000142, ToSolarSystemID: 30000144}},
+	}
+
+	snapshot := FromConvertedData(data)
+
+	if _, ok := snapshot["mapSolarSystems"]["30000142"]; !ok {
+		t.Errorf("Expected solar system in snapshot, got %v", snapshot["mapSolarSystems"])
+	}
+	if _, ok := snapshot["mapSolarSystemJumps"]["30000142:30000144"]; !ok {
+		t.Errorf("Expected jump in snapshot, got %v", snapshot["mapSolarSystemJumps"])
+	}
+	if snapshot["npcStations"] == nil {
+		t.Error("Expected empty tables to be present in the snapshot")
+	}
+}
+
+// idSet returns n consecutive IDs starting after offset.
+func idSet(n, offset int) map[string]bool {
+	set := make(map[string]bool, n)
+	for i := offset; i < offset+n; i++ {
+		set[strconv.Itoa(10000000+i)] = true
+	}
+	return set
+}
+
+// records returns n consecutive IDs starting after offset, the first
+// modified of them with different values.
+func records(n, offset, modified int) map[string]string {
+	result := make(map[string]string, n)
+	for id := range idSet(n, offset) {
+		result[id] = "a"
+	}
+	for id := range idSet(modified, offset) {
+		result[id] = "b"
+	}
+	return result
+}
+
+func TestCompare(t *testing.T) {
+	thresholds := Thresholds{MaxRemovedPercent: 1, MaxChangedPercent: 10}
+
+	tests := []struct {
+		name     string
+		previous map[string]string
+		current  map[string]string
+		allow    Allowlist
+		codes    map[models.FindingCode]models.Severity
+	}{
+		{
+			name:     "unchanged",
+			previous: records(200, 0, 0),
+			current:  records(200, 0, 0),
+			codes:    map[models.FindingCode]models.Severity{},
+		},
+		{
+			name:     "small addition",
+			previous: records(200, 0, 0),
+			current:  records(201, 0, 0),
+			codes:    map[models.FindingCode]models.Severity{CodeChangedIDs: models.SeverityInfo},
+		},
+		{
+			name:     "removal within threshold",
+			previous: records(200, 0, 0),
+			current:  records(199, 0, 0),
+			codes: map[models.FindingCode]models.Severity{
+				CodeRemovedIDs: models.SeverityWarning,
+				CodeChangedIDs: models.SeverityInfo,
+			},
+		},
+		{
+			name:     "removal over threshold",
+			previous: records(200, 0, 0),
+			current:  records(190, 0, 0),
+			codes: map[models.FindingCode]models.Severity{
+				CodeRemovedIDs: models.SeverityError,
+				CodeChangedIDs: models.SeverityInfo,
+			},
+		},
+		{
+			name:     "additions over change threshold",
+			previous: records(100, 0, 0),
+			current:  records(120, 0, 0),
+			codes:    map[models.FindingCode]models.Severity{CodeChangedIDs: models.SeverityError},
+		},
+		{
+			name:     "modified values",
+			previous: records(200, 0, 0),
+			current:  records(200, 0, 5),
+			codes:    map[models.FindingCode]models.Severity{CodeChangedIDs: models.SeverityInfo},
+		},
+		{
+			name:     "modified values over change threshold",
+			previous: records(200, 0, 0),
+			current:  records(200, 0, 30),
+			codes:    map[models.FindingCode]models.Severity{CodeChangedIDs: models.SeverityError},
+		},
+		{
+			name:     "allow-listed modifications",
+			previous: records(200, 0, 0),
+			current:  records(200, 0, 30),
+			allow:    Allowlist{"mapRegions": idSet(30, 0)},
+			codes:    map[models.FindingCode]models.Severity{},
+		},
+		{
+			name:     "allow-listed removals",
+			previous: records(200, 0, 0),
+			current:  records(190, 0, 0),
+			allow:    Allowlist{"mapRegions": idSet(10, 190)},
+			codes:    map[models.FindingCode]models.Severity{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := Snapshot{"mapRegions": tt.previous}
+			current := Snapshot{"mapRegions": tt.current}
+
+			codes := make(map[models.FindingCode]models.Severity)
+			for _, f := range Compare(previous, current, thresholds, tt.allow) {
+				if f.Code == CodeMissingTable {
+					continue
+				}
+				if f.Table != "mapRegions" {
+					t.Errorf("Expected finding for mapRegions, got %+v", f)
+				}
+				codes[f.Code] = f.Severity
+			}
+
+			if len(codes) != len(tt.codes) {
+				t.Fatalf("Expected findings %v, got %v", tt.codes, codes)
+			}
+			for code, severity := range tt.codes {
+				if codes[code] != severity {
+					t.Errorf("Expected %s with severity %s, got %s", code, severity, codes[code])
+				}
+			}
+		})
+	}
+}
+
+func TestCompareMainCluster(t *testing.T) {
+	ids := func(values ...string) map[string]string {
+		set := make(map[string]string)
+		for _, v := range values {
+			set[v] = ""
+		}
+		return set
+	}
+	systems := ids("1", "2", "3", "4")
+	// 1-2-3 with 4 alone
+	previous := Snapshot{"mapSolarSystems": systems, "mapSolarSystemJumps": ids("1:2", "2:1", "2:3", "3:2")}
+
+	tests := []struct {
+		name     string
+		previous Snapshot
+		jumps    map[string]string
+		wantWarn bool
+	}{
+		{"unchanged", previous, ids("1:2", "2:3"), false},
+		{"system joined", previous, ids("1:2", "2:3", "3:4"), true},
+		{"system cut off", previous, ids("1:2"), true},
+		{"baseline without jumps", Snapshot{"mapSolarSystems": systems}, ids("1:2"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := Snapshot{"mapSolarSystems": systems, "mapSolarSystemJumps": tt.jumps}
+			findings := CompareMainCluster(tt.previous, current)
+			if !tt.wantWarn {
+				if len(findings) != 0 {
+					t.Errorf("Expected no findings, got %+v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].Code != CodeMainCluster || findings[0].Severity != models.SeverityWarning {
+				t.Errorf("Expected one %s warning, got %+v", CodeMainCluster, findings)
+			}
+		})
+	}
+}
+
+func TestLoadAllowlist(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "baseline_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	writeFiles(t, tmpDir, map[string]string{
+		"allow.yaml": `- table: mapSolarSystems
+  ids: ["30000001", "30000002"]
+  reason: Systems removed in the expansion
+`,
+		"bad.yaml": "- table: stargates\n  ids: [\"1\"]\n",
+	})
+
+	allow, err := LoadAllowlist(filepath.Join(tmpDir, "allow.yaml"))
+	if err != nil {
+		t.Fatalf("LoadAllowlist failed: %v", err)
+	}
+	if !allow["mapSolarSystems"]["30000002"] {
+		t.Errorf("Expected allow-listed system, got %v", allow)
+	}
+
+	_, err = LoadAllowlist(filepath.Join(tmpDir, "bad.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "unknown table") {
+		t.Errorf("Expected unknown table error, got %v", err)
+	}
+}
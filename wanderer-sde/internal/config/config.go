@@ -1,10 +1,23 @@
 // Package config provides configuration management for the SDE converter.
 package config
 
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
 // SDELatestURL is the download URL for the latest EVE SDE YAML archive.
 // This is a shorthand URL that redirects to the latest build number.
 const SDELatestURL = "https://developers.eveonline.com/static-data/eve-online-static-data-latest-yaml.zip"
 
+// Default baseline comparison thresholds, in percent of a table's rows.
+const (
+	DefaultBaselineMaxRemoved = 1.0
+	DefaultBaselineMaxChanged = 10.0
+)
+
 // OutputFormat specifies the output file format.
 type OutputFormat string
 
@@ -29,12 +42,118 @@ type Config struct {
 	// DownloadSDE indicates whether to download the SDE.
 	DownloadSDE bool
 
-	// Verbose enables verbose logging.
+	// Verbose enables verbose logging: debug-level log records unless
+	// LogLevel is set.
 	Verbose bool
 
+	// LogFormat is the log output format: text or json.
+	LogFormat string
+
+	// LogLevel is the minimum level logged: debug, info, warn or error.
+	// Defaults to info, or debug with Verbose.
+	LogLevel string
+
+	// ReportFile is where a JSON report of the run is written, if set.
+	ReportFile string
+
 	// PassthroughDir is the directory containing existing Wanderer JSON files to copy.
 	PassthroughDir string
 
+	// WormholeOverlay is a Wanderer wormholes.json whose hand-curated fields
+	// (src, static, respawn) are merged into the generated wormhole data.
+	// Defaults to wormholes.json in PassthroughDir when empty.
+	WormholeOverlay string
+
+	// OverridesDir is a directory of JSON/YAML patch files applied during
+	// the transform: patches to tables other data is derived from before
+	// the derived data is computed, the rest to the converted data.
+	OverridesDir string
+
+	// TypeSetsFile is a YAML/JSON rules file selecting the types written to
+	// invTypes and to each additional type set file. Defaults are used when
+	// empty.
+	TypeSetsFile string
+
+	// SpaceKindColumn adds the derived spaceKind field to solar systems, in
+	// both CSV and JSON output.
+	SpaceKindColumn bool
+
+	// SecurityColumns adds derived trueSecurity, displaySecurity and
+	// securityBand fields to solar systems.
+	SecurityColumns bool
+
+	// ConnectivityColumns adds gateReachable and componentID fields to
+	// solar systems and writes the mapGateComponents table.
+	ConnectivityColumns bool
+
+	// EnrichedJumps keeps the region and constellation IDs of both ends in
+	// the mapSolarSystemJumps CSV. JSON output always has them.
+	EnrichedJumps bool
+
+	// JumpNeighbors writes every allowed jump within the longest ship range
+	// to the mapJumpNeighbors table, enabling the jumpNeighbors stage.
+	JumpNeighbors bool
+
+	// Chokepoints writes the articulation points, bridge gates, pipes and
+	// dead ends of the stargate graph, enabling the chokepoints stage.
+	Chokepoints bool
+
+	// DistanceAnchors lists the systems, by name or ID, the systemDistances
+	// table counts gate jumps to. Setting it enables the stage.
+	DistanceAnchors []string
+
+	// DistanceMatrix writes the gate jumps between every pair of systems to
+	// a binary file, enabling the distanceMatrix stage.
+	DistanceMatrix bool
+
+	// NearestStationOwners, NearestStationServices and NearestStationFactions
+	// select the NPC stations the nearestStations table finds the closest
+	// of: owner corporations and services by name or ID, and factions of
+	// the owner by ID. Setting any enables the stage; with none set it finds
+	// blue loot buyers.
+	NearestStationOwners   []string
+	NearestStationServices []string
+	NearestStationFactions []int64
+
+	// EnableStages lists optional transform stages to run.
+	EnableStages []string
+
+	// DisableStages lists transform stages to skip.
+	DisableStages []string
+
+	// Reproducible makes output byte-for-byte identical across runs on the
+	// same input: metadata timestamps come from SOURCE_DATE_EPOCH, tables
+	// are sorted and floats normalized, and a SHA-256 manifest is written.
+	Reproducible bool
+
+	// Regions, Constellations and Systems select the part of New Eden to
+	// convert, by name or ID. Empty selects everything.
+	Regions        []string
+	Constellations []string
+	Systems        []string
+
+	// BoundarySystems also keeps systems one jump outside the selection.
+	BoundarySystems bool
+
+	// Strict turns passthrough validation violations into a failed run.
+	Strict bool
+
+	// BaselineDir is a previous output directory the converted data is
+	// compared with to catch unexpected removals and changes.
+	BaselineDir string
+
+	// BaselineAllowFile lists IDs expected to be added or removed since the
+	// baseline, which the comparison ignores.
+	BaselineAllowFile string
+
+	// BaselineMaxRemoved is the percentage of a table's baseline IDs that
+	// may be removed before the run fails.
+	BaselineMaxRemoved float64
+
+	// BaselineMaxChanged is the percentage of a table's baseline rows that
+	// may be added, removed or modified in total before the run fails.
+	BaselineMaxChanged float64
+
 	// PrettyPrint enables indented JSON output (only applies to JSON format).
 	PrettyPrint bool
 
@@ -52,6 +171,9 @@ func NewConfig() *Config {
 		SDEUrl:       SDELatestURL,
 		PrettyPrint:  true,
 		OutputFormat: FormatCSV, // Default to CSV for Fuzzwork compatibility
+
+		BaselineMaxRemoved: DefaultBaselineMaxRemoved,
+		BaselineMaxChanged: DefaultBaselineMaxChanged,
 	}
 }
 
@@ -65,3 +187,17 @@ func (c *Config) Validate() error {
 	}
 	return nil
 }
+
+// SourceDateEpoch returns the time set by the SOURCE_DATE_EPOCH environment
+// variable (see reproducible-builds.org), and false if it is unset.
+func SourceDateEpoch() (time.Time, bool, error) {
+	value := os.Getenv("SOURCE_DATE_EPOCH")
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", value, err)
+	}
+	return time.Unix(seconds, 0).UTC(), true, nil
+}
@@ -36,6 +36,14 @@ func TestNewConfig(t *testing.T) {
 	if cfg.Verbose {
 		t.Error("Expected Verbose to default to false")
 	}
+	if cfg.Strict {
+		t.Error("Expected Strict to default to false")
+	}
+
+	if cfg.BaselineMaxRemoved != DefaultBaselineMaxRemoved || cfg.BaselineMaxChanged != DefaultBaselineMaxChanged {
+		t.Errorf("Expected default baseline thresholds %v/%v, got %v/%v",
+			DefaultBaselineMaxRemoved, DefaultBaselineMaxChanged, cfg.BaselineMaxRemoved, cfg.BaselineMaxChanged)
+	}
 
 	if cfg.PassthroughDir != "" {
 		t.Errorf("Expected empty PassthroughDir, got %q", cfg.PassthroughDir)
@@ -137,3 +145,24 @@ func TestErrors(t *testing.T) {
 		t.Error("ErrNoOutputDir has empty message")
 	}
 }
+
+func TestSourceDateEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	if _, ok, err := SourceDateEpoch(); ok || err != nil {
+		t.Errorf("Expected unset SOURCE_DATE_EPOCH, got ok=%v err=%v", ok, err)
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	epoch, ok, err := SourceDateEpoch()
+	if err != nil || !ok {
+		t.Fatalf("Expected SOURCE_DATE_EPOCH to be read, got ok=%v err=%v", ok, err)
+	}
+	if epoch.Format("2006-01-02T15:04:05Z07:00") != "2023-11-14T22:13:20Z" {
+		t.Errorf("Unexpected epoch time %v", epoch)
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	if _, _, err := SourceDateEpoch(); err == nil {
+		t.Error("Expected error for invalid SOURCE_DATE_EPOCH")
+	}
+}
@@ -8,4 +8,8 @@ var (
 
 	// ErrNoOutputDir is returned when no output directory is specified.
 	ErrNoOutputDir = errors.New("output directory must be specified")
+
+	// ErrNoSelection is returned when extracting without selecting any
+	// regions, constellations or systems.
+	ErrNoSelection = errors.New("at least one of --regions, --constellations or --systems must be specified")
 )
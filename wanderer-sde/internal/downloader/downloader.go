@@ -6,12 +6,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/logging"
 )
 
 // ExpectedFiles are the files we expect to find in a valid SDE.
@@ -29,13 +31,16 @@ var ExpectedFiles = []string{
 // Downloader handles downloading and extracting the SDE.
 type Downloader struct {
 	config     *config.Config
+	logger     *slog.Logger
 	httpClient *http.Client
 }
 
-// New creates a new Downloader with the given configuration.
-func New(cfg *config.Config) *Downloader {
+// New creates a new Downloader with the given configuration. A nil logger
+// discards log output.
+func New(cfg *config.Config, logger *slog.Logger) *Downloader {
 	return &Downloader{
 		config: cfg,
+		logger: logging.OrDiscard(logger),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Minute, // SDE is large, allow generous timeout
 		},
@@ -53,9 +58,7 @@ type DownloadResult struct {
 // Download downloads the SDE from the configured URL.
 // Returns the path to the downloaded ZIP file.
 func (d *Downloader) Download(ctx context.Context) (*DownloadResult, error) {
-	if d.config.Verbose {
-		fmt.Printf("Downloading SDE from: %s\n", d.config.SDEUrl)
-	}
+	d.logger.Debug("downloading SDE", "url", d.config.SDEUrl)
 
 	// Create a temporary directory for the download
 	tempDir, err := os.MkdirTemp("", "wanderer-sde-")
@@ -112,7 +115,7 @@ func (d *Downloader) Download(ctx context.Context) (*DownloadResult, error) {
 	pw := &progressWriter{
 		writer:        out,
 		total:         contentLength,
-		verbose:       d.config.Verbose,
+		logger:        d.logger,
 		lastPrintTime: time.Now(),
 	}
 
@@ -122,9 +125,7 @@ func (d *Downloader) Download(ctx context.Context) (*DownloadResult, error) {
 		return nil, fmt.Errorf("failed to write SDE file: %w", err)
 	}
 
-	if d.config.Verbose {
-		fmt.Printf("\nDownload complete: %d bytes\n", bytesWritten)
-	}
+	d.logger.Debug("download complete", "bytes", bytesWritten)
 
 	success = true
 	return &DownloadResult{
@@ -136,9 +137,7 @@ func (d *Downloader) Download(ctx context.Context) (*DownloadResult, error) {
 // Extract extracts a ZIP archive to the specified destination directory.
 // Returns the path to the extracted SDE directory.
 func (d *Downloader) Extract(zipPath, destDir string) (string, error) {
-	if d.config.Verbose {
-		fmt.Printf("Extracting SDE to: %s\n", destDir)
-	}
+	d.logger.Debug("extracting SDE", "dir", destDir)
 
 	// Open the ZIP file
 	r, err := zip.OpenReader(zipPath)
@@ -162,14 +161,12 @@ func (d *Downloader) Extract(zipPath, destDir string) (string, error) {
 		}
 		extractedCount++
 
-		if d.config.Verbose && extractedCount%1000 == 0 {
-			fmt.Printf("Extracted %d/%d files...\n", extractedCount, totalFiles)
+		if extractedCount%1000 == 0 {
+			d.logger.Debug("extracting SDE", "files", extractedCount, "total", totalFiles)
 		}
 	}
 
-	if d.config.Verbose {
-		fmt.Printf("Extraction complete: %d files\n", extractedCount)
-	}
+	d.logger.Debug("extraction complete", "files", extractedCount)
 
 	// The new SDE format extracts directly to the destination directory
 	return destDir, nil
@@ -215,9 +212,7 @@ func (d *Downloader) extractFile(f *zip.File, destDir string) error {
 
 // Validate checks that the SDE directory has the expected structure.
 func (d *Downloader) Validate(sdePath string) error {
-	if d.config.Verbose {
-		fmt.Printf("Validating SDE structure at: %s\n", sdePath)
-	}
+	d.logger.Debug("validating SDE structure", "path", sdePath)
 
 	// Check for expected files (new flat SDE format)
 	for _, file := range ExpectedFiles {
@@ -227,9 +222,7 @@ func (d *Downloader) Validate(sdePath string) error {
 		}
 	}
 
-	if d.config.Verbose {
-		fmt.Println("SDE validation successful")
-	}
+	d.logger.Debug("SDE validation successful")
 
 	return nil
 }
@@ -254,8 +247,8 @@ func (d *Downloader) DownloadAndExtract(ctx context.Context) (string, error) {
 	}
 
 	// Clean up ZIP file
-	if err := os.Remove(result.ZipPath); err != nil && d.config.Verbose {
-		fmt.Printf("Warning: failed to remove ZIP file: %v\n", err)
+	if err := os.Remove(result.ZipPath); err != nil {
+		d.logger.Warn("failed to remove ZIP file", "path", result.ZipPath, "error", err)
 	}
 
 	// Validate
@@ -268,12 +261,12 @@ func (d *Downloader) DownloadAndExtract(ctx context.Context) (string, error) {
 	return sdePath, nil
 }
 
-// progressWriter wraps an io.Writer to track and display progress.
+// progressWriter wraps an io.Writer to track and log progress.
 type progressWriter struct {
 	writer        io.Writer
 	total         int64
 	written       int64
-	verbose       bool
+	logger        *slog.Logger
 	lastPrintTime time.Time
 }
 
@@ -281,8 +274,8 @@ func (pw *progressWriter) Write(p []byte) (int, error) {
 	n, err := pw.writer.Write(p)
 	pw.written += int64(n)
 
-	// Print progress every second
-	if pw.verbose && time.Since(pw.lastPrintTime) > time.Second {
+	// Log progress every second
+	if time.Since(pw.lastPrintTime) > time.Second {
 		pw.printProgress()
 		pw.lastPrintTime = time.Now()
 	}
@@ -293,12 +286,12 @@ func (pw *progressWriter) Write(p []byte) (int, error) {
 func (pw *progressWriter) printProgress() {
 	if pw.total > 0 {
 		percent := float64(pw.written) / float64(pw.total) * 100
-		fmt.Printf("\rDownloading: %.1f%% (%s / %s)",
-			percent,
-			formatBytes(pw.written),
-			formatBytes(pw.total))
+		pw.logger.Debug("downloading SDE",
+			"percent", fmt.Sprintf("%.1f", percent),
+			"written", formatBytes(pw.written),
+			"total", formatBytes(pw.total))
 	} else {
-		fmt.Printf("\rDownloading: %s", formatBytes(pw.written))
+		pw.logger.Debug("downloading SDE", "written", formatBytes(pw.written))
 	}
 }
 
@@ -29,7 +29,7 @@ func TestDownloader_Validate(t *testing.T) {
 	}
 
 	cfg := &config.Config{Verbose: false}
-	dl := New(cfg)
+	dl := New(cfg, nil)
 
 	// Test valid structure
 	if err := dl.Validate(tmpDir); err != nil {
@@ -90,7 +90,7 @@ func TestDownloader_Extract(t *testing.T) {
 	_ = zipFile.Close()
 
 	cfg := &config.Config{Verbose: false}
-	dl := New(cfg)
+	dl := New(cfg, nil)
 
 	// Extract the ZIP
 	sdePath, err := dl.Extract(zipPath, extractDir)
@@ -141,7 +141,7 @@ func TestDownloader_ExtractZipSlipProtection(t *testing.T) {
 	_ = zipFile.Close()
 
 	cfg := &config.Config{Verbose: false}
-	dl := New(cfg)
+	dl := New(cfg, nil)
 
 	// Extract should fail due to zip slip protection
 	_, err = dl.Extract(zipPath, extractDir)
@@ -164,7 +164,7 @@ func TestDownloader_Download(t *testing.T) {
 		SDEUrl:  server.URL,
 		Verbose: false,
 	}
-	dl := New(cfg)
+	dl := New(cfg, nil)
 
 	ctx := context.Background()
 	result, err := dl.Download(ctx)
@@ -202,7 +202,7 @@ func TestDownloader_DownloadError(t *testing.T) {
 		SDEUrl:  server.URL,
 		Verbose: false,
 	}
-	dl := New(cfg)
+	dl := New(cfg, nil)
 
 	ctx := context.Background()
 	_, err := dl.Download(ctx)
@@ -229,7 +229,7 @@ func TestDownloader_DownloadContextCancellation(t *testing.T) {
 		SDEUrl:  server.URL,
 		Verbose: false,
 	}
-	dl := New(cfg)
+	dl := New(cfg, nil)
 
 	// Create a canceled context
 	ctx, cancel := context.WithCancel(context.Background())
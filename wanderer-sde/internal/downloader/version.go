@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/logging"
 )
 
 // LatestJSONLURL is the URL to check for the latest SDE build number.
@@ -23,14 +25,17 @@ const VersionFileName = ".last-sde-version"
 // VersionChecker handles checking and tracking SDE versions.
 type VersionChecker struct {
 	config     *config.Config
+	logger     *slog.Logger
 	httpClient *http.Client
 	versionURL string // URL to check for latest version (defaults to LatestJSONLURL)
 }
 
-// NewVersionChecker creates a new VersionChecker.
-func NewVersionChecker(cfg *config.Config) *VersionChecker {
+// NewVersionChecker creates a new VersionChecker. A nil logger discards log
+// output.
+func NewVersionChecker(cfg *config.Config, logger *slog.Logger) *VersionChecker {
 	return &VersionChecker{
 		config: cfg,
+		logger: logging.OrDiscard(logger),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -54,9 +59,7 @@ type latestRecord struct {
 
 // GetLatestVersion fetches the latest SDE version from CCP.
 func (vc *VersionChecker) GetLatestVersion(ctx context.Context) (*VersionInfo, error) {
-	if vc.config.Verbose {
-		fmt.Println("Checking latest SDE version...")
-	}
+	vc.logger.Debug("checking latest SDE version")
 
 	url := vc.versionURL
 	if url == "" {
@@ -96,9 +99,7 @@ func (vc *VersionChecker) GetLatestVersion(ctx context.Context) (*VersionInfo, e
 		var record latestRecord
 		if err := json.Unmarshal([]byte(line), &record); err != nil {
 			// Skip malformed lines
-			if vc.config.Verbose {
-				fmt.Printf("Warning: skipping malformed JSONL line: %v\n", err)
-			}
+			vc.logger.Warn("skipping malformed JSONL line", "error", err)
 			continue
 		}
 
@@ -156,9 +157,7 @@ func (vc *VersionChecker) NeedsUpdate(ctx context.Context, storageDir string) (b
 		return false, nil, err
 	}
 
-	if vc.config.Verbose {
-		fmt.Printf("Latest SDE version: %s\n", latest.BuildNumber)
-	}
+	vc.logger.Debug("latest SDE version", "build", latest.BuildNumber)
 
 	// Get the stored version
 	stored, err := vc.GetStoredVersion(storageDir)
@@ -167,24 +166,12 @@ func (vc *VersionChecker) NeedsUpdate(ctx context.Context, storageDir string) (b
 	}
 
 	if stored == "" {
-		if vc.config.Verbose {
-			fmt.Println("No stored version found, update needed")
-		}
+		vc.logger.Debug("no stored SDE version, update needed")
 		return true, latest, nil
 	}
 
-	if vc.config.Verbose {
-		fmt.Printf("Stored SDE version: %s\n", stored)
-	}
-
 	needsUpdate := stored != latest.BuildNumber
-	if vc.config.Verbose {
-		if needsUpdate {
-			fmt.Println("Update available")
-		} else {
-			fmt.Println("SDE is up to date")
-		}
-	}
+	vc.logger.Debug("stored SDE version", "build", stored, "update", needsUpdate)
 
 	return needsUpdate, latest, nil
 }
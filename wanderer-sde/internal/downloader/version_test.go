@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/logging"
 )
 
 func TestVersionChecker_GetStoredVersion(t *testing.T) {
@@ -19,7 +20,7 @@ func TestVersionChecker_GetStoredVersion(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	vc := NewVersionChecker(cfg)
+	vc := NewVersionChecker(cfg, nil)
 
 	// Test no stored version
 	version, err := vc.GetStoredVersion(tmpDir)
@@ -54,7 +55,7 @@ func TestVersionChecker_StoreVersion(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	vc := NewVersionChecker(cfg)
+	vc := NewVersionChecker(cfg, nil)
 
 	// Store a version
 	subDir := filepath.Join(tmpDir, "subdir")
@@ -90,6 +91,7 @@ func TestVersionChecker_GetLatestVersion(t *testing.T) {
 	cfg := &config.Config{Verbose: false}
 	vc := &VersionChecker{
 		config:     cfg,
+		logger:     logging.Discard(),
 		httpClient: http.DefaultClient,
 		versionURL: server.URL,
 	}
@@ -131,6 +133,7 @@ func TestVersionChecker_NeedsUpdate(t *testing.T) {
 	cfg := &config.Config{Verbose: false}
 	vc := &VersionChecker{
 		config:     cfg,
+		logger:     logging.Discard(),
 		httpClient: http.DefaultClient,
 		versionURL: server.URL,
 	}
@@ -241,6 +244,7 @@ func TestVersionChecker_CheckETag(t *testing.T) {
 			cfg := &config.Config{Verbose: false}
 			vc := &VersionChecker{
 				config:     cfg,
+				logger:     logging.Discard(),
 				httpClient: server.Client(),
 			}
 
@@ -267,7 +271,7 @@ func TestVersionChecker_CheckETag(t *testing.T) {
 
 func TestNewVersionChecker(t *testing.T) {
 	cfg := &config.Config{Verbose: true}
-	vc := NewVersionChecker(cfg)
+	vc := NewVersionChecker(cfg, nil)
 
 	if vc == nil {
 		t.Fatal("NewVersionChecker returned nil")
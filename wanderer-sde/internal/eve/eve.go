@@ -0,0 +1,13 @@
+// Package eve holds EVE Online constants and rules shared by the transformer
+// and the graph packages, such as ID ranges and security rounding.
+package eve
+
+// Solar system ID range of known space (high, low and null security,
+// including Pochven and the Jove regions).
+const (
+	KSpaceSystemIDMin = 30000000
+	KSpaceSystemIDMax = 30999999
+)
+
+// PochvenRegionID is the region of the Triglavian Pochven systems.
+const PochvenRegionID = 10000070
@@ -0,0 +1,51 @@
+package eve
+
+import (
+	"math"
+	"testing"
+)
+
+// TestTrueSecurity_Bands checks the values on either side of the high,
+// low and null security boundaries, which route planning and space kinds
+// depend on.
+func TestTrueSecurity_Bands(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected float64
+	}{
+		{0.45, 0.5},
+		{0.4499, 0.4},
+		{0.449, 0.4},
+		{0.0001, 0.1},
+		{0.0, 0.0},
+		{-0.0001, 0.0},
+		{-0.05, -0.1},
+		{1.0, 1.0},
+		{-1.0, -1.0},
+	}
+
+	for _, tt := range tests {
+		result := TrueSecurity(tt.input)
+		if math.Abs(result-tt.expected) > 0.001 {
+			t.Errorf("TrueSecurity(%v) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTruncateToTwoDigits(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected float64
+	}{
+		{0.456, 0.45},
+		{-0.789, -0.78},
+		{0.05, 0.05},
+	}
+
+	for _, tt := range tests {
+		result := TruncateToTwoDigits(tt.input)
+		if math.Abs(result-tt.expected) > 0.001 {
+			t.Errorf("TruncateToTwoDigits(%v) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// WriteDOT writes a graph in the Graphviz DOT language. Node attributes
+// become DOT attributes and edge weights the weight attribute.
+func WriteDOT(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, "graph %s {\n", dotQuote(g.Name))
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(n.Label)}
+		for i, a := range g.Attributes {
+			if i < len(n.Values) {
+				attrs = append(attrs, a.Name+"="+dotQuote(models.FormatValue(n.Values[i])))
+			}
+		}
+		_, _ = fmt.Fprintf(bw, "  %d [%s];\n", n.ID, strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		_, _ = fmt.Fprintf(bw, "  %d -- %d [weight=%d];\n", e.Source, e.Target, e.Weight)
+	}
+	_, _ = bw.WriteString("}\n")
+	// bufio.Writer keeps the first write error and returns it from Flush
+	return bw.Flush()
+}
+
+// dotQuote returns s as a double-quoted DOT string.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,312 @@
+// Package export writes the stargate network in graph file formats read by
+// graph tools: DOT for Graphviz, and GraphML and GEXF for Gephi.
+package export
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/transformer"
+)
+
+// Format is a graph file format.
+type Format string
+
+// Graph file formats.
+const (
+	FormatDOT     Format = "dot"
+	FormatGraphML Format = "graphml"
+	FormatGEXF    Format = "gexf"
+)
+
+// ParseFormat parses a graph format name (case-insensitive).
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatDOT, FormatGraphML, FormatGEXF:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid graph format %q: must be %q, %q or %q", name, FormatDOT, FormatGraphML, FormatGEXF)
+	}
+}
+
+// FormatFromPath returns the format matching a file extension (.dot or .gv,
+// .graphml, .gexf).
+func FormatFromPath(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dot", ".gv":
+		return FormatDOT, true
+	case ".graphml":
+		return FormatGraphML, true
+	case ".gexf":
+		return FormatGEXF, true
+	default:
+		return "", false
+	}
+}
+
+// Write writes a graph in the given format.
+func Write(w io.Writer, g *Graph, format Format) error {
+	switch format {
+	case FormatDOT:
+		return WriteDOT(w, g)
+	case FormatGraphML:
+		return WriteGraphML(w, g)
+	case FormatGEXF:
+		return WriteGEXF(w, g)
+	default:
+		return fmt.Errorf("invalid graph format %q", format)
+	}
+}
+
+// AttributeType is the value type of a node attribute.
+type AttributeType string
+
+// Attribute types, named as in GraphML and GEXF.
+const (
+	TypeString AttributeType = "string"
+	TypeLong   AttributeType = "long"
+	TypeDouble AttributeType = "double"
+)
+
+// Attribute declares a node attribute.
+type Attribute struct {
+	Name string
+	Type AttributeType
+}
+
+// Node is a graph node. Values holds one value per graph attribute: a
+// string, int64 or float64 matching the attribute's type.
+type Node struct {
+	ID     int64
+	Label  string
+	Values []interface{}
+}
+
+// Edge is an undirected edge. Weight is the number of stargates it stands
+// for.
+type Edge struct {
+	Source int64
+	Target int64
+	Weight int64
+}
+
+// Graph is an undirected graph ready to be written.
+type Graph struct {
+	Name       string
+	Attributes []Attribute
+	Nodes      []Node // Sorted by ID
+	Edges      []Edge // Sorted by source and then target, source < target
+}
+
+// Aggregation selects what the nodes of an exported graph are.
+type Aggregation string
+
+// Aggregation levels.
+const (
+	AggregateSystems Aggregation = "system"
+	AggregateRegions Aggregation = "region"
+)
+
+// ParseAggregation parses an aggregation level. The empty string means
+// AggregateSystems.
+func ParseAggregation(name string) (Aggregation, error) {
+	switch a := Aggregation(name); a {
+	case "":
+		return AggregateSystems, nil
+	case AggregateSystems, AggregateRegions:
+		return a, nil
+	default:
+		return "", fmt.Errorf("invalid aggregation %q: must be %q or %q", name, AggregateSystems, AggregateRegions)
+	}
+}
+
+// Options selects the part of the network to export.
+type Options struct {
+	// Systems limits the export to these systems; nil keeps all.
+	Systems map[int64]bool
+
+	// SpaceKinds limits the export to systems of these space kinds, such
+	// as "highsec" or "c5"; empty keeps all.
+	SpaceKinds []string
+
+	// Aggregate collapses systems into one node per region when set to
+	// AggregateRegions.
+	Aggregate Aggregation
+}
+
+// systemAttributes are the node attributes of a system-level graph.
+var systemAttributes = []Attribute{
+	{"name", TypeString},
+	{"regionID", TypeLong},
+	{"region", TypeString},
+	{"constellationID", TypeLong},
+	{"constellation", TypeString},
+	{"security", TypeDouble},
+	{"spaceKind", TypeString},
+	{"wormholeClass", TypeLong},
+	{"x", TypeDouble},
+	{"y", TypeDouble},
+	{"z", TypeDouble},
+}
+
+// regionAttributes are the node attributes of a region-level graph.
+var regionAttributes = []Attribute{
+	{"name", TypeString},
+	{"systems", TypeLong},
+	{"x", TypeDouble},
+	{"y", TypeDouble},
+	{"z", TypeDouble},
+}
+
+// Build prepares the stargate network for export. Solar systems must have
+// their space kind set; classes are the resolved wormhole class of each
+// system (0 or missing for none). Only gates between kept systems become
+// edges. It fails on unknown space kinds.
+func Build(universe *models.UniverseData, jumps []models.SystemJump, classes map[int64]int64, opts Options) (*Graph, error) {
+	kinds := make(map[string]bool, len(opts.SpaceKinds))
+	for _, kind := range opts.SpaceKinds {
+		if !isSpaceKind(kind) {
+			return nil, fmt.Errorf("invalid space kind %q: must be one of %s", kind, strings.Join(transformer.SpaceKinds, ", "))
+		}
+		kinds[kind] = true
+	}
+	aggregate, err := ParseAggregation(string(opts.Aggregate))
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[int64]models.SolarSystem)
+	for _, sys := range universe.SolarSystems {
+		if opts.Systems != nil && !opts.Systems[sys.SolarSystemID] {
+			continue
+		}
+		if len(kinds) > 0 && !kinds[sys.SpaceKind] {
+			continue
+		}
+		kept[sys.SolarSystemID] = sys
+	}
+
+	if aggregate == AggregateRegions {
+		return buildRegionGraph(universe, jumps, kept), nil
+	}
+	return buildSystemGraph(universe, jumps, classes, kept), nil
+}
+
+func isSpaceKind(kind string) bool {
+	for _, k := range transformer.SpaceKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func buildSystemGraph(universe *models.UniverseData, jumps []models.SystemJump, classes map[int64]int64, kept map[int64]models.SolarSystem) *Graph {
+	regionNames := make(map[int64]string, len(universe.Regions))
+	for _, r := range universe.Regions {
+		regionNames[r.RegionID] = r.RegionName
+	}
+	constellationNames := make(map[int64]string, len(universe.Constellations))
+	for _, c := range universe.Constellations {
+		constellationNames[c.ConstellationID] = c.ConstellationName
+	}
+
+	g := &Graph{Name: "New Eden", Attributes: systemAttributes}
+	for _, sys := range kept {
+		g.Nodes = append(g.Nodes, Node{
+			ID:    sys.SolarSystemID,
+			Label: sys.SolarSystemName,
+			Values: []interface{}{
+				sys.SolarSystemName,
+				sys.RegionID,
+				regionNames[sys.RegionID],
+				sys.ConstellationID,
+				constellationNames[sys.ConstellationID],
+				sys.Security,
+				sys.SpaceKind,
+				classes[sys.SolarSystemID],
+				sys.X, sys.Y, sys.Z,
+			},
+		})
+	}
+	sortNodes(g.Nodes)
+
+	gates := make(map[[2]int64]bool)
+	for _, j := range jumps {
+		_, okFrom := kept[j.FromSolarSystemID]
+		_, okTo := kept[j.ToSolarSystemID]
+		if okFrom && okTo && j.FromSolarSystemID != j.ToSolarSystemID {
+			gates[pairKey(j.FromSolarSystemID, j.ToSolarSystemID)] = true
+		}
+	}
+	for pair := range gates {
+		g.Edges = append(g.Edges, Edge{Source: pair[0], Target: pair[1], Weight: 1})
+	}
+	sortEdges(g.Edges)
+	return g
+}
+
+func buildRegionGraph(universe *models.UniverseData, jumps []models.SystemJump, kept map[int64]models.SolarSystem) *Graph {
+	systems := make(map[int64]int64)
+	for _, sys := range kept {
+		systems[sys.RegionID]++
+	}
+
+	g := &Graph{Name: "New Eden regions", Attributes: regionAttributes}
+	for _, r := range universe.Regions {
+		if systems[r.RegionID] == 0 {
+			continue
+		}
+		g.Nodes = append(g.Nodes, Node{
+			ID:     r.RegionID,
+			Label:  r.RegionName,
+			Values: []interface{}{r.RegionName, systems[r.RegionID], r.X, r.Y, r.Z},
+		})
+	}
+	sortNodes(g.Nodes)
+
+	// Jumps may list a gate in both directions; count each system pair once
+	counted := make(map[[2]int64]bool)
+	gates := make(map[[2]int64]int64)
+	for _, j := range jumps {
+		from, okFrom := kept[j.FromSolarSystemID]
+		to, okTo := kept[j.ToSolarSystemID]
+		if !okFrom || !okTo || from.RegionID == to.RegionID {
+			continue
+		}
+		if gate := pairKey(from.SolarSystemID, to.SolarSystemID); !counted[gate] {
+			counted[gate] = true
+			gates[pairKey(from.RegionID, to.RegionID)]++
+		}
+	}
+	for pair, n := range gates {
+		g.Edges = append(g.Edges, Edge{Source: pair[0], Target: pair[1], Weight: n})
+	}
+	sortEdges(g.Edges)
+	return g
+}
+
+// pairKey orders two IDs so an undirected pair has one key.
+func pairKey(a, b int64) [2]int64 {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int64{a, b}
+}
+
+func sortNodes(nodes []Node) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+}
+
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Source != edges[j].Source {
+			return edges[i].Source < edges[j].Source
+		}
+		return edges[i].Target < edges[j].Target
+	})
+}
@@ -0,0 +1,266 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// testUniverse is Jita - Perimeter in The Forge joined to Amarr in Domain,
+// plus a wormhole system without gates.
+func testUniverse() (*models.UniverseData, []models.SystemJump, map[int64]int64) {
+	universe := &models.UniverseData{
+		Regions: []models.Region{
+			{RegionID: 10000002, RegionName: "The Forge", X: 1, Y: 2, Z: 3},
+			{RegionID: 10000043, RegionName: "Domain"},
+			{RegionID: 11000001, RegionName: "A-R00001"},
+		},
+		Constellations: []models.Constellation{
+			{ConstellationID: 20000020, ConstellationName: "Kimotoro"},
+			{ConstellationID: 20000322, ConstellationName: "Throne Worlds"},
+		},
+		SolarSystems: []models.SolarSystem{
+			{SolarSystemID: 30000144, SolarSystemName: "Perimeter", RegionID: 10000002, ConstellationID: 20000020, Security: 0.95, SpaceKind: "highsec"},
+			{SolarSystemID: 30000142, SolarSystemName: "Jita", RegionID: 10000002, ConstellationID: 20000020, Security: 0.9459, SpaceKind: "highsec",
+				X: 2 * graph.MetersPerLightYear, Z: -graph.MetersPerLightYear},
+			{SolarSystemID: 30002187, SolarSystemName: "Amarr", RegionID: 10000043, ConstellationID: 20000322, Security: 1.0, SpaceKind: "highsec"},
+			{SolarSystemID: 31000005, SolarSystemName: "J105443", RegionID: 11000001, Security: -1, SpaceKind: "c2"},
+		},
+	}
+	var jumps []models.SystemJump
+	for _, pair := range [][2]int64{{30000142, 30000144}, {30000144, 30002187}} {
+		jumps = append(jumps,
+			models.SystemJump{FromSolarSystemID: pair[0], ToSolarSystemID: pair[1]},
+			models.SystemJump{FromSolarSystemID: pair[1], ToSolarSystemID: pair[0]})
+	}
+	return universe, jumps, map[int64]int64{31000005: 2}
+}
+
+func TestBuild(t *testing.T) {
+	universe, jumps, classes := testUniverse()
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantNodes []int64
+		wantEdges []Edge
+	}{
+		{
+			name:      "all systems",
+			wantNodes: []int64{30000142, 30000144, 30002187, 31000005},
+			wantEdges: []Edge{{30000142, 30000144, 1}, {30000144, 30002187, 1}},
+		},
+		{
+			name:      "space kinds",
+			opts:      Options{SpaceKinds: []string{"c2"}},
+			wantNodes: []int64{31000005},
+		},
+		{
+			name:      "selected systems",
+			opts:      Options{Systems: map[int64]bool{30000144: true, 30002187: true}},
+			wantNodes: []int64{30000144, 30002187},
+			wantEdges: []Edge{{30000144, 30002187, 1}},
+		},
+		{
+			name:      "regions",
+			opts:      Options{Aggregate: AggregateRegions},
+			wantNodes: []int64{10000002, 10000043, 11000001},
+			wantEdges: []Edge{{10000002, 10000043, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := Build(universe, jumps, classes, tt.opts)
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+			var ids []int64
+			for _, n := range g.Nodes {
+				ids = append(ids, n.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantNodes) {
+				t.Errorf("Expected nodes %v, got %v", tt.wantNodes, ids)
+			}
+			if len(g.Edges) != len(tt.wantEdges) || (len(g.Edges) > 0 && !reflect.DeepEqual(g.Edges, tt.wantEdges)) {
+				t.Errorf("Expected edges %v, got %v", tt.wantEdges, g.Edges)
+			}
+		})
+	}
+
+	g, err := Build(universe, jumps, classes, Options{})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	jita := g.Nodes[0]
+	if jita.Values[2] != "The Forge" || jita.Values[4] != "Kimotoro" || jita.Values[6] != "highsec" {
+		t.Errorf("Unexpected Jita attributes: %v", jita.Values)
+	}
+	if wormhole := g.Nodes[3]; wormhole.Values[7] != int64(2) {
+		t.Errorf("Expected wormhole class 2, got %v", wormhole.Values[7])
+	}
+
+	if _, err := Build(universe, jumps, classes, Options{SpaceKinds: []string{"hisec"}}); err == nil {
+		t.Error("Expected an error for an unknown space kind")
+	}
+	if _, err := Build(universe, jumps, classes, Options{Aggregate: "galaxy"}); err == nil {
+		t.Error("Expected an error for an unknown aggregation")
+	}
+}
+
+// writerGraph is a two-node graph with characters that need escaping.
+func writerGraph() *Graph {
+	return &Graph{
+		Name:       `Test "graph"`,
+		Attributes: []Attribute{{"name", TypeString}, {"systems", TypeLong}, {"x", TypeDouble}, {"z", TypeDouble}},
+		Nodes: []Node{
+			{ID: 1, Label: "A & B", Values: []interface{}{"A & B", int64(3), float64(2 * graph.MetersPerLightYear), float64(-4 * graph.MetersPerLightYear)}},
+			{ID: 2, Label: `<"C">`, Values: []interface{}{`<"C">`, int64(1), 0.0, 0.0}},
+		},
+		Edges: []Edge{{Source: 1, Target: 2, Weight: 5}},
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, writerGraph()); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+
+	expected := `graph "Test \"graph\"" {
+  1 [label="A & B", name="A & B", systems="3", x="18921460945161600", z="-37842921890323200"];
+  2 [label="<\"C\">", name="<\"C\">", systems="1", x="0", z="0"];
+  1 -- 2 [weight=5];
+}
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, writerGraph()); err != nil {
+		t.Fatalf("WriteGraphML failed: %v", err)
+	}
+
+	var doc struct {
+		Keys []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"key"`
+		Graph struct {
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Weight string `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Output is not valid XML: %v", err)
+	}
+
+	if len(doc.Keys) != 6 {
+		t.Errorf("Expected label, 4 attribute and weight keys, got %d", len(doc.Keys))
+	}
+	if doc.Graph.EdgeDefault != "undirected" || len(doc.Graph.Nodes) != 2 || len(doc.Graph.Edges) != 1 {
+		t.Fatalf("Unexpected graph: %+v", doc.Graph)
+	}
+	if data := doc.Graph.Nodes[1].Data[0]; data.Key != "label" || data.Value != `<"C">` {
+		t.Errorf("Expected escaped label to round-trip, got %+v", data)
+	}
+	if e := doc.Graph.Edges[0]; e.Source != "1" || e.Target != "2" || e.Weight != "5" {
+		t.Errorf("Unexpected edge: %+v", e)
+	}
+}
+
+func TestWriteGEXF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGEXF(&buf, writerGraph()); err != nil {
+		t.Fatalf("WriteGEXF failed: %v", err)
+	}
+
+	var doc struct {
+		Graph struct {
+			Attributes []struct {
+				Title string `xml:"title,attr"`
+				Type  string `xml:"type,attr"`
+			} `xml:"attributes>attribute"`
+			Nodes []struct {
+				Label     string `xml:"label,attr"`
+				AttValues []struct {
+					For   string `xml:"for,attr"`
+					Value string `xml:"value,attr"`
+				} `xml:"attvalues>attvalue"`
+				Position struct {
+					X string `xml:"x,attr"`
+					Y string `xml:"y,attr"`
+				} `xml:"position"`
+			} `xml:"nodes>node"`
+			Edges []struct {
+				Weight string `xml:"weight,attr"`
+			} `xml:"edges>edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Output is not valid XML: %v", err)
+	}
+
+	if len(doc.Graph.Attributes) != 4 || doc.Graph.Attributes[1].Type != "long" {
+		t.Errorf("Unexpected attributes: %+v", doc.Graph.Attributes)
+	}
+	if len(doc.Graph.Nodes) != 2 || len(doc.Graph.Edges) != 1 || doc.Graph.Edges[0].Weight != "5" {
+		t.Fatalf("Unexpected graph: %+v", doc.Graph)
+	}
+	node := doc.Graph.Nodes[0]
+	if node.Label != "A & B" || len(node.AttValues) != 4 {
+		t.Errorf("Unexpected node: %+v", node)
+	}
+	if node.Position.X != "2" || node.Position.Y != "-4" {
+		t.Errorf("Expected position (2, -4) in light years, got (%s, %s)", node.Position.X, node.Position.Y)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		path string
+		want Format
+		ok   bool
+	}{
+		{"map.dot", FormatDOT, true},
+		{"map.GV", FormatDOT, true},
+		{"out/map.graphml", FormatGraphML, true},
+		{"map.gexf", FormatGEXF, true},
+		{"map.json", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := FormatFromPath(tt.path); got != tt.want || ok != tt.ok {
+			t.Errorf("FormatFromPath(%q): expected %q (%v), got %q (%v)", tt.path, tt.want, tt.ok, got, ok)
+		}
+	}
+
+	if f, err := ParseFormat("GEXF"); err != nil || f != FormatGEXF {
+		t.Errorf("Expected gexf, got %q (%v)", f, err)
+	}
+	if _, err := ParseFormat("svg"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, writerGraph(), FormatGEXF); err != nil || !strings.Contains(buf.String(), "<gexf") {
+		t.Errorf("Expected Write to produce GEXF, got %v", err)
+	}
+}
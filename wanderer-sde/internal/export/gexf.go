@@ -0,0 +1,64 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// WriteGEXF writes a graph as GEXF 1.3. When the graph has x and z
+// attributes, nodes also get a viz:position in light years looking down on
+// the galactic plane (SDE x and z), so Gephi can draw the map without a
+// layout run.
+func WriteGEXF(w io.Writer, g *Graph) error {
+	xIndex, zIndex := -1, -1
+	for i, a := range g.Attributes {
+		switch a.Name {
+		case "x":
+			xIndex = i
+		case "z":
+			zIndex = i
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString(xml.Header)
+	_, _ = bw.WriteString(`<gexf xmlns="http://gexf.net/1.3" xmlns:viz="http://gexf.net/1.3/viz" version="1.3">` + "\n")
+	_, _ = fmt.Fprintf(bw, "  <meta><description>%s</description></meta>\n", xmlEscape(g.Name))
+	_, _ = bw.WriteString(`  <graph mode="static" defaultedgetype="undirected">` + "\n")
+
+	_, _ = bw.WriteString(`    <attributes class="node">` + "\n")
+	for i, a := range g.Attributes {
+		_, _ = fmt.Fprintf(bw, "      <attribute id=\"%d\" title=\"%s\" type=\"%s\"/>\n", i, xmlEscape(a.Name), a.Type)
+	}
+	_, _ = bw.WriteString("    </attributes>\n    <nodes>\n")
+
+	for _, n := range g.Nodes {
+		_, _ = fmt.Fprintf(bw, "      <node id=\"%d\" label=\"%s\">\n        <attvalues>\n", n.ID, xmlEscape(n.Label))
+		for i := range g.Attributes {
+			if i < len(n.Values) {
+				_, _ = fmt.Fprintf(bw, "          <attvalue for=\"%d\" value=\"%s\"/>\n", i, xmlEscape(models.FormatValue(n.Values[i])))
+			}
+		}
+		_, _ = bw.WriteString("        </attvalues>\n")
+		if xIndex >= 0 && zIndex >= 0 && xIndex < len(n.Values) && zIndex < len(n.Values) {
+			x, _ := n.Values[xIndex].(float64)
+			z, _ := n.Values[zIndex].(float64)
+			_, _ = fmt.Fprintf(bw, "        <viz:position x=\"%s\" y=\"%s\" z=\"0\"/>\n",
+				models.FormatFloat(x/graph.MetersPerLightYear), models.FormatFloat(z/graph.MetersPerLightYear))
+		}
+		_, _ = bw.WriteString("      </node>\n")
+	}
+	_, _ = bw.WriteString("    </nodes>\n    <edges>\n")
+
+	for i, e := range g.Edges {
+		_, _ = fmt.Fprintf(bw, "      <edge id=\"%d\" source=\"%d\" target=\"%d\" weight=\"%d\"/>\n", i, e.Source, e.Target, e.Weight)
+	}
+
+	_, _ = bw.WriteString("    </edges>\n  </graph>\n</gexf>\n")
+	return bw.Flush()
+}
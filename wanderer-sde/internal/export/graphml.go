@@ -0,0 +1,52 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// WriteGraphML writes a graph as GraphML. Each node attribute is a key of
+// the same name; node labels use the label key Gephi recognises and edge
+// weights the weight key.
+func WriteGraphML(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString(xml.Header)
+	_, _ = bw.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	_, _ = bw.WriteString(`  <key id="label" for="node" attr.name="label" attr.type="string"/>` + "\n")
+	for _, a := range g.Attributes {
+		name := xmlEscape(a.Name)
+		_, _ = fmt.Fprintf(bw, "  <key id=\"%s\" for=\"node\" attr.name=\"%s\" attr.type=\"%s\"/>\n", name, name, a.Type)
+	}
+	_, _ = bw.WriteString(`  <key id="weight" for="edge" attr.name="weight" attr.type="long"/>` + "\n")
+	_, _ = fmt.Fprintf(bw, "  <graph id=\"%s\" edgedefault=\"undirected\">\n", xmlEscape(g.Name))
+
+	for _, n := range g.Nodes {
+		_, _ = fmt.Fprintf(bw, "    <node id=\"%d\">\n", n.ID)
+		_, _ = fmt.Fprintf(bw, "      <data key=\"label\">%s</data>\n", xmlEscape(n.Label))
+		for i, a := range g.Attributes {
+			if i < len(n.Values) {
+				_, _ = fmt.Fprintf(bw, "      <data key=\"%s\">%s</data>\n", xmlEscape(a.Name), xmlEscape(models.FormatValue(n.Values[i])))
+			}
+		}
+		_, _ = bw.WriteString("    </node>\n")
+	}
+	for _, e := range g.Edges {
+		_, _ = fmt.Fprintf(bw, "    <edge source=\"%d\" target=\"%d\"><data key=\"weight\">%d</data></edge>\n",
+			e.Source, e.Target, e.Weight)
+	}
+
+	_, _ = bw.WriteString("  </graph>\n</graphml>\n")
+	return bw.Flush()
+}
+
+// xmlEscape escapes s for use in XML text and attribute values.
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,272 @@
+package graph
+
+import "sort"
+
+// MinPipeLength is the fewest systems a pipe has. A single system with two
+// neighbours is too common, for example on any ring, to be a chokepoint.
+const MinPipeLength = 2
+
+// Bridge is a stargate whose removal disconnects the systems it joins.
+// From is the lower system ID.
+type Bridge struct {
+	From int64
+	To   int64
+}
+
+// Pocket is a dead end: systems that can only be reached through a single
+// entrance system.
+type Pocket struct {
+	Entrance int64
+	Systems  []int64 // Sorted by ID; the entrance is not included
+}
+
+// Chokepoints are the weak points of the stargate graph.
+type Chokepoints struct {
+	// ArticulationPoints are systems whose removal disconnects space,
+	// sorted by ID.
+	ArticulationPoints []int64
+
+	// Bridges are the stargates whose removal disconnects space, sorted by
+	// From and then To.
+	Bridges []Bridge
+
+	// Pipes are maximal chains of at least MinPipeLength systems with
+	// exactly two stargate neighbours, in travel order from the lower end
+	// ID. Pipes are ordered by their lowest system ID.
+	Pipes [][]int64
+
+	// DeadEnds are the pockets behind each articulation point: every side
+	// of it except the largest. Pockets nest, so a system can be in
+	// several. Sorted by entrance and then by first system.
+	DeadEnds []Pocket
+}
+
+// Chokepoints finds the articulation points, bridges, pipes and dead ends
+// of the stargate graph. Overlay connections are ignored.
+func (g *Graph) Chokepoints() *Chokepoints {
+	adj := make([][]int, len(g.systems))
+	for i, edges := range g.adj {
+		for _, e := range edges {
+			if e.conn == nil {
+				adj[i] = append(adj[i], e.to)
+			}
+		}
+	}
+
+	d := newCutSearch(adj)
+	c := &Chokepoints{}
+	for root := range adj {
+		if d.disc[root] >= 0 || len(adj[root]) == 0 {
+			continue
+		}
+		start := len(d.order)
+		d.visit(root, -1)
+		c.DeadEnds = append(c.DeadEnds, d.pockets(g, d.order[start:])...)
+	}
+
+	for u, children := range d.separated {
+		// The root of a DFS tree is a cut vertex only with two subtrees
+		if len(children) > 1 || (len(children) == 1 && d.parent[u] >= 0) {
+			c.ArticulationPoints = append(c.ArticulationPoints, g.systems[u].ID)
+		}
+	}
+	for _, b := range d.bridges {
+		from, to := g.systems[b[0]].ID, g.systems[b[1]].ID
+		if from > to {
+			from, to = to, from
+		}
+		c.Bridges = append(c.Bridges, Bridge{From: from, To: to})
+	}
+	sort.Slice(c.Bridges, func(i, j int) bool {
+		if c.Bridges[i].From != c.Bridges[j].From {
+			return c.Bridges[i].From < c.Bridges[j].From
+		}
+		return c.Bridges[i].To < c.Bridges[j].To
+	})
+	sort.Slice(c.DeadEnds, func(i, j int) bool {
+		if c.DeadEnds[i].Entrance != c.DeadEnds[j].Entrance {
+			return c.DeadEnds[i].Entrance < c.DeadEnds[j].Entrance
+		}
+		return c.DeadEnds[i].Systems[0] < c.DeadEnds[j].Systems[0]
+	})
+
+	c.Pipes = g.pipes(adj)
+	return c
+}
+
+// cutSearch is a depth-first search finding cut vertices and bridges with
+// Tarjan's low-link values.
+type cutSearch struct {
+	adj       [][]int
+	disc      []int   // Discovery order; -1 when unvisited
+	low       []int   // Lowest discovery order reachable from the subtree
+	size      []int   // Subtree size
+	parent    []int   // DFS tree parent; -1 for roots
+	order     []int   // Positions in discovery order
+	separated [][]int // Children whose subtree only connects through the node
+	bridges   [][2]int
+}
+
+func newCutSearch(adj [][]int) *cutSearch {
+	n := len(adj)
+	d := &cutSearch{
+		adj:       adj,
+		disc:      make([]int, n),
+		low:       make([]int, n),
+		size:      make([]int, n),
+		parent:    make([]int, n),
+		order:     make([]int, 0, n),
+		separated: make([][]int, n),
+	}
+	for i := range d.disc {
+		d.disc[i] = -1
+		d.parent[i] = -1
+	}
+	return d
+}
+
+func (d *cutSearch) visit(u, parent int) {
+	d.disc[u] = len(d.order)
+	d.low[u] = d.disc[u]
+	d.size[u] = 1
+	d.parent[u] = parent
+	d.order = append(d.order, u)
+
+	for _, v := range d.adj[u] {
+		if v == parent {
+			continue
+		}
+		if d.disc[v] >= 0 {
+			d.low[u] = min(d.low[u], d.disc[v])
+			continue
+		}
+		d.visit(v, u)
+		d.size[u] += d.size[v]
+		d.low[u] = min(d.low[u], d.low[v])
+		if d.low[v] > d.disc[u] {
+			d.bridges = append(d.bridges, [2]int{u, v})
+		}
+		if d.low[v] >= d.disc[u] {
+			d.separated[u] = append(d.separated[u], v)
+		}
+	}
+}
+
+// subtree returns the positions in the DFS subtree of v.
+func (d *cutSearch) subtree(v int) []int {
+	return d.order[d.disc[v] : d.disc[v]+d.size[v]]
+}
+
+// pockets returns the dead ends of a connected component that has been
+// searched. Removing a cut vertex splits its component into the separated
+// subtrees and, unless it is the root, the rest of the component; every
+// part except the largest is a pocket. On a tie the part holding the DFS
+// root is kept as the main side.
+func (d *cutSearch) pockets(g *Graph, component []int) []Pocket {
+	var pockets []Pocket
+	for _, u := range component {
+		children := d.separated[u]
+		if len(children) == 0 || (len(children) == 1 && d.parent[u] < 0) {
+			continue
+		}
+
+		parts := make([][]int, 0, len(children)+1)
+		rest := len(component) - 1
+		for _, v := range children {
+			parts = append(parts, d.subtree(v))
+			rest -= d.size[v]
+		}
+		if rest > 0 {
+			parts = append([][]int{d.remainder(component, u, children)}, parts...)
+		}
+
+		main := 0
+		for i, part := range parts {
+			if len(part) > len(parts[main]) {
+				main = i
+			}
+		}
+		for i, part := range parts {
+			if i == main {
+				continue
+			}
+			ids := make([]int64, len(part))
+			for k, n := range part {
+				ids[k] = g.systems[n].ID
+			}
+			sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+			pockets = append(pockets, Pocket{Entrance: g.systems[u].ID, Systems: ids})
+		}
+	}
+	return pockets
+}
+
+// remainder returns the systems of a component outside u and the subtrees
+// of its separated children.
+func (d *cutSearch) remainder(component []int, u int, children []int) []int {
+	excluded := make(map[int]bool, len(component))
+	excluded[u] = true
+	for _, v := range children {
+		for _, n := range d.subtree(v) {
+			excluded[n] = true
+		}
+	}
+	var rest []int
+	for _, n := range component {
+		if !excluded[n] {
+			rest = append(rest, n)
+		}
+	}
+	return rest
+}
+
+// pipes returns the maximal chains of at least MinPipeLength systems with
+// exactly two neighbours in adj. A ring of such systems is one pipe.
+func (g *Graph) pipes(adj [][]int) [][]int64 {
+	seen := make([]bool, len(adj))
+	// walk follows the chain from start through next until it reaches a
+	// system without two neighbours or one already in the pipe
+	walk := func(start, next int) []int {
+		var chain []int
+		prev := start
+		for len(adj[next]) == 2 && !seen[next] {
+			seen[next] = true
+			chain = append(chain, next)
+			if adj[next][0] == prev {
+				prev, next = next, adj[next][1]
+			} else {
+				prev, next = next, adj[next][0]
+			}
+		}
+		return chain
+	}
+
+	var pipes [][]int64
+	for i := range adj {
+		if len(adj[i]) != 2 || seen[i] {
+			continue
+		}
+		seen[i] = true
+		left := walk(i, adj[i][0])
+		right := walk(i, adj[i][1])
+		if len(left)+1+len(right) < MinPipeLength {
+			continue
+		}
+
+		pipe := make([]int64, 0, len(left)+1+len(right))
+		for k := len(left) - 1; k >= 0; k-- {
+			pipe = append(pipe, g.systems[left[k]].ID)
+		}
+		pipe = append(pipe, g.systems[i].ID)
+		for _, n := range right {
+			pipe = append(pipe, g.systems[n].ID)
+		}
+		if pipe[0] > pipe[len(pipe)-1] {
+			for a, b := 0, len(pipe)-1; a < b; a, b = a+1, b-1 {
+				pipe[a], pipe[b] = pipe[b], pipe[a]
+			}
+		}
+		pipes = append(pipes, pipe)
+	}
+	return pipes
+}
@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// chokepointGraph is a ring with a tail and a triangle hanging off it, a
+// separate pair of systems and an isolated system:
+//
+//	 8 - 9
+//	  \ /
+//	1 - 2          11 - 12
+//	|   |
+//	4 - 3          10
+//	|
+//	5 - 6 - 7
+func chokepointGraph() *Graph {
+	var systems []models.SolarSystem
+	for _, id := range []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12} {
+		systems = append(systems, models.SolarSystem{SolarSystemID: id, RegionID: 100 + id/10})
+	}
+	var jumps []models.SystemJump
+	for _, pair := range [][2]int64{
+		{1, 2}, {2, 3}, {3, 4}, {4, 1},
+		{4, 5}, {5, 6}, {6, 7},
+		{2, 8}, {2, 9}, {8, 9},
+		{11, 12},
+	} {
+		jumps = append(jumps, models.SystemJump{FromSolarSystemID: pair[0], ToSolarSystemID: pair[1]})
+	}
+	return New(systems, jumps)
+}
+
+func TestChokepoints(t *testing.T) {
+	c := chokepointGraph().Chokepoints()
+
+	if want := []int64{2, 4, 5, 6}; !reflect.DeepEqual(c.ArticulationPoints, want) {
+		t.Errorf("Expected articulation points %v, got %v", want, c.ArticulationPoints)
+	}
+
+	wantBridges := []Bridge{{4, 5}, {5, 6}, {6, 7}, {11, 12}}
+	if !reflect.DeepEqual(c.Bridges, wantBridges) {
+		t.Errorf("Expected bridges %v, got %v", wantBridges, c.Bridges)
+	}
+
+	// 1 and 3 have two neighbours each but are shorter than MinPipeLength
+	wantPipes := [][]int64{{5, 6}, {8, 9}}
+	if !reflect.DeepEqual(c.Pipes, wantPipes) {
+		t.Errorf("Expected pipes %v, got %v", wantPipes, c.Pipes)
+	}
+
+	wantPockets := []Pocket{
+		{Entrance: 2, Systems: []int64{8, 9}},
+		{Entrance: 4, Systems: []int64{5, 6, 7}},
+		{Entrance: 5, Systems: []int64{6, 7}},
+		{Entrance: 6, Systems: []int64{7}},
+	}
+	if !reflect.DeepEqual(c.DeadEnds, wantPockets) {
+		t.Errorf("Expected dead ends %v, got %v", wantPockets, c.DeadEnds)
+	}
+}
+
+func TestChokepoints_Shapes(t *testing.T) {
+	build := func(pairs ...[2]int64) *Graph {
+		var systems []models.SolarSystem
+		var jumps []models.SystemJump
+		seen := make(map[int64]bool)
+		for _, pair := range pairs {
+			for _, id := range pair {
+				if !seen[id] {
+					seen[id] = true
+					systems = append(systems, models.SolarSystem{SolarSystemID: id})
+				}
+			}
+			jumps = append(jumps, models.SystemJump{FromSolarSystemID: pair[0], ToSolarSystemID: pair[1]})
+		}
+		return New(systems, jumps)
+	}
+
+	tests := []struct {
+		name        string
+		graph       *Graph
+		wantPoints  []int64
+		wantBridges int
+		wantPipes   [][]int64
+		wantPockets int
+	}{
+		{
+			name:      "ring",
+			graph:     build([2]int64{1, 2}, [2]int64{2, 3}, [2]int64{3, 1}),
+			wantPipes: [][]int64{{1, 2, 3}},
+		},
+		{
+			// A pipe is listed from its lower end whichever end is found first
+			name:        "line",
+			graph:       build([2]int64{3, 1}, [2]int64{1, 2}, [2]int64{2, 4}),
+			wantPoints:  []int64{1, 2},
+			wantBridges: 3,
+			wantPipes:   [][]int64{{1, 2}},
+			wantPockets: 2,
+		},
+		{
+			// The middle system alone is shorter than MinPipeLength
+			name:        "short line",
+			graph:       build([2]int64{1, 2}, [2]int64{2, 3}),
+			wantPoints:  []int64{2},
+			wantBridges: 2,
+			wantPockets: 1,
+		},
+		{
+			name:        "star",
+			graph:       build([2]int64{1, 2}, [2]int64{1, 3}, [2]int64{1, 4}),
+			wantPoints:  []int64{1},
+			wantBridges: 3,
+			wantPockets: 2,
+		},
+		{
+			name:  "complete",
+			graph: build([2]int64{1, 2}, [2]int64{1, 3}, [2]int64{1, 4}, [2]int64{2, 3}, [2]int64{2, 4}, [2]int64{3, 4}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.graph.Chokepoints()
+			if len(c.ArticulationPoints) != len(tt.wantPoints) ||
+				(len(tt.wantPoints) > 0 && !reflect.DeepEqual(c.ArticulationPoints, tt.wantPoints)) {
+				t.Errorf("Expected articulation points %v, got %v", tt.wantPoints, c.ArticulationPoints)
+			}
+			if len(c.Bridges) != tt.wantBridges {
+				t.Errorf("Expected %d bridges, got %v", tt.wantBridges, c.Bridges)
+			}
+			if len(c.Pipes) != len(tt.wantPipes) ||
+				(len(tt.wantPipes) > 0 && !reflect.DeepEqual(c.Pipes, tt.wantPipes)) {
+				t.Errorf("Expected pipes %v, got %v", tt.wantPipes, c.Pipes)
+			}
+			if len(c.DeadEnds) != tt.wantPockets {
+				t.Errorf("Expected %d dead ends, got %v", tt.wantPockets, c.DeadEnds)
+			}
+		})
+	}
+}
+
+func TestChokepoints_IgnoresOverlay(t *testing.T) {
+	g := chokepointGraph()
+	overlay := &Overlay{Connections: []Connection{{From: "7", To: "3", Kind: KindWormhole}}}
+	withOverlay, err := g.WithOverlay(overlay, time.Now())
+	if err != nil {
+		t.Fatalf("WithOverlay failed: %v", err)
+	}
+
+	if got, want := withOverlay.Chokepoints(), g.Chokepoints(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected overlay connections to be ignored, got %+v", got)
+	}
+}
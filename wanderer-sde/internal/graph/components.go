@@ -0,0 +1,40 @@
+package graph
+
+import "sort"
+
+// Components returns the connected components of the stargate graph,
+// largest first and then by lowest system ID. Each lists its system IDs in
+// ascending order. Systems without stargates are components of their own;
+// overlay connections are ignored.
+func (g *Graph) Components() [][]int64 {
+	seen := make([]bool, len(g.systems))
+	var components [][]int64
+	for start := range g.systems {
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		queue := []int{start}
+		for head := 0; head < len(queue); head++ {
+			for _, e := range g.adj[queue[head]] {
+				if e.conn == nil && !seen[e.to] {
+					seen[e.to] = true
+					queue = append(queue, e.to)
+				}
+			}
+		}
+
+		ids := make([]int64, len(queue))
+		for i, n := range queue {
+			ids[i] = g.systems[n].ID
+		}
+		sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+		components = append(components, ids)
+	}
+
+	// Components were found in system order; keep it among equal sizes
+	sort.SliceStable(components, func(a, b int) bool {
+		return len(components[a]) > len(components[b])
+	})
+	return components
+}
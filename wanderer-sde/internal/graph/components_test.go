@@ -0,0 +1,33 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComponents(t *testing.T) {
+	got := chokepointGraph().Components()
+
+	want := [][]int64{
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{11, 12},
+		{10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected components %v, got %v", want, got)
+	}
+}
+
+func TestComponents_IgnoresOverlay(t *testing.T) {
+	g := chokepointGraph()
+	overlay := &Overlay{Connections: []Connection{{From: "10", To: "11", Kind: KindWormhole}}}
+	withOverlay, err := g.WithOverlay(overlay, time.Now())
+	if err != nil {
+		t.Fatalf("WithOverlay failed: %v", err)
+	}
+
+	if got, want := withOverlay.Components(), g.Components(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected components %v, got %v", want, got)
+	}
+}
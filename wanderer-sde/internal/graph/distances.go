@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// JumpCounts returns the number of jumps from the nearest of the source
+// systems to every system, in Systems order, by breadth-first search.
+// Unreachable systems are -1.
+func (g *Graph) JumpCounts(sources ...int64) ([]int, error) {
+	dist := make([]int, len(g.systems))
+	for i := range dist {
+		dist[i] = -1
+	}
+
+	queue := make([]int, 0, len(g.systems))
+	for _, id := range sources {
+		i, ok := g.index[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownSystem, id)
+		}
+		if dist[i] < 0 {
+			dist[i] = 0
+			queue = append(queue, i)
+		}
+	}
+	g.bfs(queue, dist)
+	return dist, nil
+}
+
+// NearestSources returns, in Systems order, the nearest of the source
+// systems to every system and the number of jumps to it, by breadth-first
+// search from all sources at once. Ties go to the lowest source ID.
+// Unreachable systems have source 0 and -1 jumps.
+func (g *Graph) NearestSources(sources ...int64) (nearest []int64, jumps []int, err error) {
+	nearest = make([]int64, len(g.systems))
+	jumps = make([]int, len(g.systems))
+	for i := range jumps {
+		jumps[i] = -1
+	}
+
+	queue := make([]int, 0, len(g.systems))
+	for _, id := range sources {
+		i, ok := g.index[id]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %d", ErrUnknownSystem, id)
+		}
+		if jumps[i] < 0 {
+			jumps[i] = 0
+			nearest[i] = id
+			queue = append(queue, i)
+		}
+	}
+
+	// A system's whole level is dequeued before the next, so its source is
+	// final by the time it is expanded
+	for head := 0; head < len(queue); head++ {
+		here := queue[head]
+		for _, e := range g.adj[here] {
+			switch {
+			case jumps[e.to] < 0:
+				jumps[e.to] = jumps[here] + 1
+				nearest[e.to] = nearest[here]
+				queue = append(queue, e.to)
+			case jumps[e.to] == jumps[here]+1 && nearest[here] < nearest[e.to]:
+				nearest[e.to] = nearest[here]
+			}
+		}
+	}
+	return nearest, jumps, nil
+}
+
+// bfs extends the distances in dist outward from the systems in queue,
+// which must already have their distance set.
+func (g *Graph) bfs(queue []int, dist []int) {
+	for head := 0; head < len(queue); head++ {
+		here := queue[head]
+		for _, e := range g.adj[here] {
+			if dist[e.to] < 0 {
+				dist[e.to] = dist[here] + 1
+				queue = append(queue, e.to)
+			}
+		}
+	}
+}
+
+// DistanceMatrix returns the number of jumps between every pair of systems
+// with at least one connection; systems without any are left out. Routes
+// longer than models.UnreachableJumps-1 jumps are stored as that.
+func (g *Graph) DistanceMatrix(ctx context.Context) (*models.DistanceMatrix, error) {
+	var nodes []int
+	for i := range g.systems {
+		if len(g.adj[i]) > 0 {
+			nodes = append(nodes, i)
+		}
+	}
+
+	m := &models.DistanceMatrix{
+		SystemIDs: make([]int64, len(nodes)),
+		Jumps:     make([]uint8, len(nodes)*len(nodes)),
+	}
+	for k, n := range nodes {
+		m.SystemIDs[k] = g.systems[n].ID
+	}
+
+	dist := make([]int, len(g.systems))
+	queue := make([]int, 0, len(g.systems))
+	for row, n := range nodes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for i := range dist {
+			dist[i] = -1
+		}
+		dist[n] = 0
+		g.bfs(append(queue[:0], n), dist)
+
+		cells := m.Jumps[row*len(nodes) : (row+1)*len(nodes)]
+		for col, to := range nodes {
+			switch d := dist[to]; {
+			case d < 0:
+				cells[col] = models.UnreachableJumps
+			case d >= models.UnreachableJumps:
+				cells[col] = models.UnreachableJumps - 1
+			default:
+				cells[col] = uint8(d)
+			}
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestJumpCounts(t *testing.T) {
+	g := testGraph()
+
+	tests := []struct {
+		name    string
+		sources []int64
+		want    []int
+	}{
+		{"single source", []int64{1}, []int{0, 1, 1, 2, 2, -1}},
+		{"nearest of two", []int64{1, 4}, []int{0, 1, 1, 0, 1, -1}},
+		{"duplicate source", []int64{2, 2}, []int{1, 0, 2, 1, 2, -1}},
+		{"unconnected", []int64{6}, []int{-1, -1, -1, -1, -1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := g.JumpCounts(tt.sources...)
+			if err != nil {
+				t.Fatalf("JumpCounts failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := g.JumpCounts(1, 99); !errors.Is(err, ErrUnknownSystem) {
+		t.Errorf("Expected ErrUnknownSystem, got %v", err)
+	}
+}
+
+func TestNearestSources(t *testing.T) {
+	g := testGraph()
+
+	// Echo is one jump from both Charlie and Delta; the lower ID wins
+	// whichever order the sources are given in
+	for _, sources := range [][]int64{{3, 4}, {4, 3}} {
+		nearest, jumps, err := g.NearestSources(sources...)
+		if err != nil {
+			t.Fatalf("NearestSources failed: %v", err)
+		}
+		if want := []int64{3, 4, 3, 4, 3, 0}; !reflect.DeepEqual(nearest, want) {
+			t.Errorf("Sources %v: expected nearest %v, got %v", sources, want, nearest)
+		}
+		if want := []int{1, 1, 0, 0, 1, -1}; !reflect.DeepEqual(jumps, want) {
+			t.Errorf("Sources %v: expected jumps %v, got %v", sources, want, jumps)
+		}
+	}
+
+	if _, _, err := g.NearestSources(99); !errors.Is(err, ErrUnknownSystem) {
+		t.Errorf("Expected ErrUnknownSystem, got %v", err)
+	}
+}
+
+func TestDistanceMatrix(t *testing.T) {
+	g := testGraph()
+
+	m, err := g.DistanceMatrix(context.Background())
+	if err != nil {
+		t.Fatalf("DistanceMatrix failed: %v", err)
+	}
+
+	// Foxtrot has no gates and is left out
+	if !reflect.DeepEqual(m.SystemIDs, []int64{1, 2, 3, 4, 5}) {
+		t.Fatalf("Expected systems [1 2 3 4 5], got %v", m.SystemIDs)
+	}
+	expected := []uint8{
+		0, 1, 1, 2, 2,
+		1, 0, 2, 1, 2,
+		1, 2, 0, 2, 1,
+		2, 1, 2, 0, 1,
+		2, 2, 1, 1, 0,
+	}
+	if !reflect.DeepEqual(m.Jumps, expected) {
+		t.Errorf("Expected %v, got %v", expected, m.Jumps)
+	}
+
+	if jumps, ok := m.JumpsBetween(3, 2); !ok || jumps != 2 {
+		t.Errorf("Expected 2 jumps from Charlie to Bravo, got %d (%v)", jumps, ok)
+	}
+	if _, ok := m.JumpsBetween(1, 6); ok {
+		t.Error("Expected no entry for a system without gates")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.DistanceMatrix(ctx); err == nil {
+		t.Error("Expected an error for a cancelled context")
+	}
+}
+
+func TestDistanceMatrix_Unreachable(t *testing.T) {
+	systems := []models.SolarSystem{
+		{SolarSystemID: 1, SolarSystemName: "Alpha"},
+		{SolarSystemID: 2, SolarSystemName: "Bravo"},
+		{SolarSystemID: 3, SolarSystemName: "Charlie"},
+		{SolarSystemID: 4, SolarSystemName: "Delta"},
+	}
+	jumps := []models.SystemJump{
+		{FromSolarSystemID: 1, ToSolarSystemID: 2},
+		{FromSolarSystemID: 3, ToSolarSystemID: 4},
+	}
+
+	m, err := New(systems, jumps).DistanceMatrix(context.Background())
+	if err != nil {
+		t.Fatalf("DistanceMatrix failed: %v", err)
+	}
+	if m.Jumps[3] != models.UnreachableJumps {
+		t.Errorf("Expected %d from Alpha to Delta, got %d", models.UnreachableJumps, m.Jumps[3])
+	}
+	if _, ok := m.JumpsBetween(1, 4); ok {
+		t.Error("Expected no route between separate clusters")
+	}
+	if jumps, ok := m.JumpsBetween(4, 3); !ok || jumps != 1 {
+		t.Errorf("Expected 1 jump from Delta to Charlie, got %d (%v)", jumps, ok)
+	}
+}
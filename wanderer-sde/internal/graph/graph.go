@@ -0,0 +1,189 @@
+// Package graph builds the stargate graph of New Eden from converted data
+// and runs graph operations such as route planning over it.
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+var (
+	// ErrUnknownSystem is returned when a system ID or name is not in the graph.
+	ErrUnknownSystem = errors.New("unknown solar system")
+
+	// ErrNoRoute is returned when no route connects two systems.
+	ErrNoRoute = errors.New("no route found")
+)
+
+// System is a node of the graph: a solar system and the fields routing
+// needs from it.
+type System struct {
+	ID              int64
+	Name            string
+	ConstellationID int64
+	RegionID        int64
+	Security        float64 // Raw SDE security status
+	X, Y, Z         float64 // Position in meters
+}
+
+// Graph is the undirected stargate graph between solar systems, optionally
+// with overlay connections added by WithOverlay. Systems are indexed
+// internally by position; the exported methods take and return solar
+// system IDs.
+type Graph struct {
+	systems []System
+	index   map[int64]int  // Solar system ID -> position
+	names   map[string]int // Lower-case name -> position
+	adj     [][]edge       // Outgoing edges, sorted by neighbour then kind
+}
+
+// edge is a one-way connection to the system at position to. conn is nil
+// for stargates.
+type edge struct {
+	to   int
+	conn *Connection
+}
+
+// kind returns the kind of connection the edge is.
+func (e edge) kind() ConnectionKind {
+	if e.conn == nil {
+		return KindGate
+	}
+	return e.conn.Kind
+}
+
+// New builds a graph from solar systems and the stargate jumps between
+// them. Jumps are treated as two-way and jumps referring to systems not in
+// the list are ignored, so a subset of the data builds a valid graph.
+func New(systems []models.SolarSystem, jumps []models.SystemJump) *Graph {
+	g := &Graph{
+		systems: make([]System, 0, len(systems)),
+		index:   make(map[int64]int, len(systems)),
+		names:   make(map[string]int, len(systems)),
+	}
+
+	sorted := make([]models.SolarSystem, len(systems))
+	copy(sorted, systems)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].SolarSystemID < sorted[j].SolarSystemID
+	})
+	for _, sys := range sorted {
+		if _, ok := g.index[sys.SolarSystemID]; ok {
+			continue
+		}
+		g.index[sys.SolarSystemID] = len(g.systems)
+		g.names[strings.ToLower(sys.SolarSystemName)] = len(g.systems)
+		g.systems = append(g.systems, System{
+			ID:              sys.SolarSystemID,
+			Name:            sys.SolarSystemName,
+			ConstellationID: sys.ConstellationID,
+			RegionID:        sys.RegionID,
+			Security:        sys.Security,
+			X:               sys.X,
+			Y:               sys.Y,
+			Z:               sys.Z,
+		})
+	}
+
+	neighbours := make([]map[int]bool, len(g.systems))
+	link := func(a, b int) {
+		if neighbours[a] == nil {
+			neighbours[a] = make(map[int]bool)
+		}
+		neighbours[a][b] = true
+	}
+	for _, j := range jumps {
+		from, okFrom := g.index[j.FromSolarSystemID]
+		to, okTo := g.index[j.ToSolarSystemID]
+		if !okFrom || !okTo || from == to {
+			continue
+		}
+		link(from, to)
+		link(to, from)
+	}
+
+	g.adj = make([][]edge, len(g.systems))
+	for i, set := range neighbours {
+		for n := range set {
+			g.adj[i] = append(g.adj[i], edge{to: n})
+		}
+		sortEdges(g.adj[i])
+	}
+
+	return g
+}
+
+// FromData builds a graph from the solar systems and jumps of converted data.
+func FromData(data *models.ConvertedData) *Graph {
+	var systems []models.SolarSystem
+	if data.Universe != nil {
+		systems = data.Universe.SolarSystems
+	}
+	return New(systems, data.SystemJumps)
+}
+
+// Len returns the number of systems in the graph.
+func (g *Graph) Len() int {
+	return len(g.systems)
+}
+
+// Systems returns the systems of the graph sorted by ID. The slice must not
+// be modified.
+func (g *Graph) Systems() []System {
+	return g.systems
+}
+
+// System returns the system with the given ID.
+func (g *Graph) System(id int64) (System, bool) {
+	i, ok := g.index[id]
+	if !ok {
+		return System{}, false
+	}
+	return g.systems[i], true
+}
+
+// Lookup finds a system by name (case-insensitive) or ID.
+func (g *Graph) Lookup(nameOrID string) (System, error) {
+	if i, ok := g.names[strings.ToLower(nameOrID)]; ok {
+		return g.systems[i], nil
+	}
+	if id, err := strconv.ParseInt(nameOrID, 10, 64); err == nil {
+		if sys, ok := g.System(id); ok {
+			return sys, nil
+		}
+	}
+	return System{}, fmt.Errorf("%w: %q", ErrUnknownSystem, nameOrID)
+}
+
+// Neighbors returns the IDs of the systems one jump from id through a
+// stargate or overlay connection, sorted by ID.
+func (g *Graph) Neighbors(id int64) []int64 {
+	i, ok := g.index[id]
+	if !ok {
+		return nil
+	}
+	ids := make([]int64, 0, len(g.adj[i]))
+	for k, e := range g.adj[i] {
+		if k > 0 && g.adj[i][k-1].to == e.to {
+			continue
+		}
+		ids = append(ids, g.systems[e.to].ID)
+	}
+	return ids
+}
+
+// sortEdges orders edges by neighbour position, with stargates before
+// overlay connections and overlay connections in kind order.
+func sortEdges(edges []edge) {
+	sort.SliceStable(edges, func(a, b int) bool {
+		if edges[a].to != edges[b].to {
+			return edges[a].to < edges[b].to
+		}
+		return kindOrder[edges[a].kind()] < kindOrder[edges[b].kind()]
+	})
+}
@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// testGraph is a diamond with a short route through low security space and
+// a longer one through high security space, plus an unconnected system:
+//
+//	Alpha(1.0) - Bravo(0.3) ------------- Delta(0.9)
+//	     \                                 /
+//	      Charlie(0.8) ---- Echo(0.7) ----
+//
+//	Foxtrot(0.5), no gates
+func testGraph() *Graph {
+	systems := []models.SolarSystem{
+		{SolarSystemID: 1, SolarSystemName: "Alpha", RegionID: 10, Security: 1.0},
+		{SolarSystemID: 2, SolarSystemName: "Bravo", RegionID: 10, Security: 0.3},
+		{SolarSystemID: 3, SolarSystemName: "Charlie", RegionID: 10, Security: 0.8},
+		{SolarSystemID: 4, SolarSystemName: "Delta", RegionID: 20, Security: 0.9},
+		{SolarSystemID: 5, SolarSystemName: "Echo", RegionID: 20, Security: 0.7},
+		{SolarSystemID: 6, SolarSystemName: "Foxtrot", RegionID: 20, Security: 0.5},
+	}
+	jumps := []models.SystemJump{
+		{FromSolarSystemID: 1, ToSolarSystemID: 2},
+		{FromSolarSystemID: 2, ToSolarSystemID: 1},
+		{FromSolarSystemID: 2, ToSolarSystemID: 4},
+		{FromSolarSystemID: 1, ToSolarSystemID: 3},
+		{FromSolarSystemID: 3, ToSolarSystemID: 5},
+		{FromSolarSystemID: 5, ToSolarSystemID: 4},
+		{FromSolarSystemID: 5, ToSolarSystemID: 99}, // Outside the graph
+	}
+	return New(systems, jumps)
+}
+
+func TestNew(t *testing.T) {
+	g := testGraph()
+
+	if g.Len() != 6 {
+		t.Errorf("Expected 6 systems, got %d", g.Len())
+	}
+
+	tests := []struct {
+		id   int64
+		want []int64
+	}{
+		{1, []int64{2, 3}},
+		{2, []int64{1, 4}},
+		{4, []int64{2, 5}},
+		{5, []int64{3, 4}},
+		{6, []int64{}},
+		{99, nil},
+	}
+	for _, tt := range tests {
+		got := g.Neighbors(tt.id)
+		if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("Neighbors(%d): expected %v, got %v", tt.id, tt.want, got)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	g := testGraph()
+
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"Alpha", 1, false},
+		{"dElTa", 4, false},
+		{"5", 5, false},
+		{"Jita", 0, true},
+		{"99", 0, true},
+	}
+	for _, tt := range tests {
+		sys, err := g.Lookup(tt.input)
+		if tt.wantErr {
+			if !errors.Is(err, ErrUnknownSystem) {
+				t.Errorf("Lookup(%q): expected ErrUnknownSystem, got %v", tt.input, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Lookup(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if sys.ID != tt.want {
+			t.Errorf("Lookup(%q): expected %d, got %d", tt.input, tt.want, sys.ID)
+		}
+	}
+}
+
+func TestFromData(t *testing.T) {
+	data := &models.ConvertedData{
+		Universe: &models.UniverseData{
+			SolarSystems: []models.SolarSystem{
+				{SolarSystemID: 1, SolarSystemName: "Alpha"},
+				{SolarSystemID: 2, SolarSystemName: "Bravo"},
+			},
+		},
+		SystemJumps: []models.SystemJump{{FromSolarSystemID: 1, ToSolarSystemID: 2}},
+	}
+
+	g := FromData(data)
+	if g.Len() != 2 {
+		t.Errorf("Expected 2 systems, got %d", g.Len())
+	}
+	if got := g.Neighbors(2); len(got) != 1 || got[0] != 1 {
+		t.Errorf("Expected jumps to be two-way, got %v", got)
+	}
+
+	if empty := FromData(&models.ConvertedData{}); empty.Len() != 0 {
+		t.Errorf("Expected an empty graph without universe data, got %d systems", empty.Len())
+	}
+}
@@ -0,0 +1,342 @@
+package graph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guarzo/wanderer-sde/internal/eve"
+)
+
+// MetersPerLightYear converts SDE coordinates to light years.
+const MetersPerLightYear = 9460730472580800
+
+// ErrJumpRestricted is returned when a jump drive cannot be used to leave
+// or enter a system.
+var ErrJumpRestricted = errors.New("jump drive restricted")
+
+// Jump fatigue limits, in minutes.
+const (
+	maxJumpFatigue  = 300 // Fatigue is capped at 5 hours
+	maxJumpCooldown = 30  // The activation cooldown is capped at 30 minutes
+	minJumpFatigue  = 10  // A jump always builds on at least 10 minutes
+)
+
+// jdcBonus is the range bonus per Jump Drive Calibration level.
+const jdcBonus = 0.2
+
+// MaxJDCLevel is the highest Jump Drive Calibration skill level.
+const MaxJDCLevel = 5
+
+// ShipClass is a class of jump-capable ship.
+type ShipClass struct {
+	Name             string
+	BaseRange        float64 // Light years with Jump Drive Calibration 0
+	FatigueReduction float64 // Fraction of jump distance ignored for fatigue
+}
+
+// Range returns the ship's jump range in light years with the given Jump
+// Drive Calibration level; each level adds 20% of the base range.
+func (c ShipClass) Range(jdc int) float64 {
+	return c.BaseRange * (1 + jdcBonus*float64(jdc))
+}
+
+// shipClasses are the jump-capable ship classes. Ranges are the in-game
+// maximums at Jump Drive Calibration V divided by two.
+var shipClasses = []ShipClass{
+	{Name: "black-ops", BaseRange: 4.0, FatigueReduction: 0.75},
+	{Name: "carrier", BaseRange: 3.5},
+	{Name: "dreadnought", BaseRange: 3.5},
+	{Name: "fax", BaseRange: 3.5},
+	{Name: "jump-freighter", BaseRange: 5.0, FatigueReduction: 0.9},
+	{Name: "rorqual", BaseRange: 5.0, FatigueReduction: 0.9},
+	{Name: "supercarrier", BaseRange: 3.0},
+	{Name: "titan", BaseRange: 3.0},
+}
+
+// ShipClasses returns the jump-capable ship classes sorted by name.
+func ShipClasses() []ShipClass {
+	return append([]ShipClass(nil), shipClasses...)
+}
+
+// ParseShipClass finds a ship class by name (case-insensitive).
+func ParseShipClass(name string) (ShipClass, error) {
+	names := make([]string, len(shipClasses))
+	for i, c := range shipClasses {
+		if strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+		names[i] = c.Name
+	}
+	return ShipClass{}, fmt.Errorf("invalid ship class %q: must be one of %s", name, strings.Join(names, ", "))
+}
+
+// MaxJumpRange returns the longest jump range of any ship class.
+func MaxJumpRange() float64 {
+	longest := 0.0
+	for _, c := range shipClasses {
+		longest = math.Max(longest, c.Range(MaxJDCLevel))
+	}
+	return longest
+}
+
+// Distance returns the distance between two systems in light years.
+func Distance(a, b System) float64 {
+	dx, dy, dz := a.X-b.X, a.Y-b.Y, a.Z-b.Z
+	return math.Sqrt(dx*dx+dy*dy+dz*dz) / MetersPerLightYear
+}
+
+// CanJumpFrom reports whether a jump drive can be activated in a system.
+// Only known space outside Pochven allows it; wormhole space, Pochven and
+// Abyssal space do not.
+func CanJumpFrom(sys System) bool {
+	return sys.ID >= eve.KSpaceSystemIDMin && sys.ID <= eve.KSpaceSystemIDMax && sys.RegionID != eve.PochvenRegionID
+}
+
+// CanJumpTo reports whether a jump drive can target a system: a system it
+// can be activated in that is not high security space.
+func CanJumpTo(sys System) bool {
+	return CanJumpFrom(sys) && !isHighSec(sys.Security)
+}
+
+// JumpTarget is a system within jump range and its distance.
+type JumpTarget struct {
+	System   System
+	Distance float64 // Light years
+}
+
+// JumpNeighbors returns the systems a jump drive with the given range can
+// reach from id in one jump, nearest first. It fails if id is unknown or
+// a jump drive cannot be activated there.
+func (g *Graph) JumpNeighbors(id int64, rangeLY float64) ([]JumpTarget, error) {
+	i, ok := g.index[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownSystem, id)
+	}
+	origin := g.systems[i]
+	if !CanJumpFrom(origin) {
+		return nil, fmt.Errorf("%w: cannot jump out of %s", ErrJumpRestricted, origin.Name)
+	}
+
+	var targets []JumpTarget
+	for _, n := range g.jumpTargets() {
+		if n == i {
+			continue
+		}
+		if d := Distance(origin, g.systems[n]); d <= rangeLY {
+			targets = append(targets, JumpTarget{System: g.systems[n], Distance: d})
+		}
+	}
+	sort.Slice(targets, func(a, b int) bool {
+		if targets[a].Distance != targets[b].Distance {
+			return targets[a].Distance < targets[b].Distance
+		}
+		return targets[a].System.ID < targets[b].System.ID
+	})
+	return targets, nil
+}
+
+// jumpTargets returns the positions of the systems jump drives can target.
+func (g *Graph) jumpTargets() []int {
+	var targets []int
+	for i, sys := range g.systems {
+		if CanJumpTo(sys) {
+			targets = append(targets, i)
+		}
+	}
+	return targets
+}
+
+// JumpPreference selects what a capital route minimises.
+type JumpPreference string
+
+// Jump route preferences.
+const (
+	PreferFewestJumps  JumpPreference = "fewest-jumps"  // Fewest jumps, then least fatigue
+	PreferLeastFatigue JumpPreference = "least-fatigue" // Least fatigue, then fewest jumps
+)
+
+// ParseJumpPreference parses a jump route preference name.
+func ParseJumpPreference(name string) (JumpPreference, error) {
+	switch p := JumpPreference(name); p {
+	case PreferFewestJumps, PreferLeastFatigue:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid jump preference %q: must be %q or %q",
+			name, PreferFewestJumps, PreferLeastFatigue)
+	}
+}
+
+// JumpOptions controls capital route planning.
+type JumpOptions struct {
+	Ship ShipClass
+	JDC  int // Jump Drive Calibration level, 0 to MaxJDCLevel
+
+	// Prefer selects what the route minimises; empty means
+	// PreferFewestJumps.
+	Prefer JumpPreference
+
+	// Avoid lists systems the route must not jump to.
+	Avoid []int64
+}
+
+// JumpRoute is a planned jump drive route between two systems.
+type JumpRoute struct {
+	Systems   []System  // Origin first, destination last
+	Distances []float64 // Light years of each jump; Distances[i] ends at Systems[i+1]
+	Fatigue   time.Duration
+	Wait      time.Duration // Activation cooldowns waited between jumps
+}
+
+// Jumps returns the number of jumps on the route.
+func (r *JumpRoute) Jumps() int {
+	return len(r.Systems) - 1
+}
+
+// Distance returns the total light years jumped.
+func (r *JumpRoute) Distance() float64 {
+	total := 0.0
+	for _, d := range r.Distances {
+		total += d
+	}
+	return total
+}
+
+// JumpRoute finds a jump drive route from one system to another within
+// the ship's range. Fatigue grows by a factor of (1 + effective light
+// years) per jump, so the least-fatigue preference minimises the sum of
+// log(1 + effective light years). The returned fatigue and wait assume
+// each jump is made as soon as the previous cooldown ends.
+func (g *Graph) JumpRoute(from, to int64, opts JumpOptions) (*JumpRoute, error) {
+	src, ok := g.index[from]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownSystem, from)
+	}
+	dst, ok := g.index[to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownSystem, to)
+	}
+	if opts.Ship.BaseRange <= 0 {
+		return nil, fmt.Errorf("a ship class must be set")
+	}
+	if opts.JDC < 0 || opts.JDC > MaxJDCLevel {
+		return nil, fmt.Errorf("jump drive calibration level %d is not between 0 and %d", opts.JDC, MaxJDCLevel)
+	}
+	prefer := opts.Prefer
+	if prefer == "" {
+		prefer = PreferFewestJumps
+	}
+	if _, err := ParseJumpPreference(string(prefer)); err != nil {
+		return nil, err
+	}
+	if !CanJumpFrom(g.systems[src]) {
+		return nil, fmt.Errorf("%w: cannot jump out of %s", ErrJumpRestricted, g.systems[src].Name)
+	}
+	if src != dst && !CanJumpTo(g.systems[dst]) {
+		return nil, fmt.Errorf("%w: cannot jump into %s", ErrJumpRestricted, g.systems[dst].Name)
+	}
+
+	rangeLY := opts.Ship.Range(opts.JDC)
+	avoid := make(map[int]bool, len(opts.Avoid))
+	for _, id := range opts.Avoid {
+		if i, ok := g.index[id]; ok {
+			avoid[i] = true
+		}
+	}
+	var targets []int
+	for _, n := range g.jumpTargets() {
+		if !avoid[n] {
+			targets = append(targets, n)
+		}
+	}
+
+	cost := make([]float64, len(g.systems))
+	tie := make([]float64, len(g.systems))
+	prev := make([]int, len(g.systems))
+	done := make([]bool, len(g.systems))
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	cost[src] = 0
+
+	queue := &routeQueue{{node: src}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(routeItem)
+		if done[item.node] {
+			continue
+		}
+		done[item.node] = true
+		if item.node == dst {
+			break
+		}
+
+		here := g.systems[item.node]
+		for _, n := range targets {
+			if done[n] {
+				continue
+			}
+			d := Distance(here, g.systems[n])
+			if d > rangeLY {
+				continue
+			}
+			fatigue := math.Log1p(d * (1 - opts.Ship.FatigueReduction))
+			c, t := cost[item.node]+1, tie[item.node]+fatigue
+			if prefer == PreferLeastFatigue {
+				c, t = cost[item.node]+fatigue, tie[item.node]+1
+			}
+			if c < cost[n] || (c == cost[n] && t < tie[n]) {
+				cost[n] = c
+				tie[n] = t
+				prev[n] = item.node
+				heap.Push(queue, routeItem{node: n, cost: c, tie: t})
+			}
+		}
+	}
+
+	if !done[dst] {
+		return nil, fmt.Errorf("%w from %s to %s with %.2f LY range", ErrNoRoute, g.systems[src].Name, g.systems[dst].Name, rangeLY)
+	}
+
+	var path []System
+	for n := dst; n >= 0; n = prev[n] {
+		path = append([]System{g.systems[n]}, path...)
+	}
+	route := &JumpRoute{Systems: path, Distances: make([]float64, len(path)-1)}
+	for i := 1; i < len(path); i++ {
+		route.Distances[i-1] = Distance(path[i-1], path[i])
+	}
+	route.Fatigue, route.Wait = jumpFatigue(route.Distances, opts.Ship.FatigueReduction)
+	return route, nil
+}
+
+// jumpFatigue returns the fatigue after a series of jumps and the total
+// activation cooldown waited between them, jumping as soon as each
+// cooldown ends. Per jump, with e the effective light years:
+//
+//	cooldown = max(fatigue / 10, 1 + e), at most 30 minutes
+//	fatigue  = max(fatigue, 10) * (1 + e), at most 5 hours
+//
+// Fatigue then decays one minute per minute while waiting.
+func jumpFatigue(distances []float64, reduction float64) (fatigue, wait time.Duration) {
+	var minutes, waited float64
+	for i, d := range distances {
+		effective := d * (1 - reduction)
+		cooldown := math.Min(math.Max(minutes/10, 1+effective), maxJumpCooldown)
+		minutes = math.Min(math.Max(minutes, minJumpFatigue)*(1+effective), maxJumpFatigue)
+		if i < len(distances)-1 {
+			waited += cooldown
+			minutes -= cooldown
+		}
+	}
+	return toDuration(minutes), toDuration(waited)
+}
+
+// toDuration converts minutes to a duration rounded to the second.
+func toDuration(minutes float64) time.Duration {
+	return (time.Duration(minutes * float64(time.Minute))).Round(time.Second)
+}
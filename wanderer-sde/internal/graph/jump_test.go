@@ -0,0 +1,230 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/guarzo/wanderer-sde/internal/eve"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// jumpGraph places systems along the x axis, positions given in light years.
+func jumpGraph() *Graph {
+	ly := func(x float64) float64 { return x * MetersPerLightYear }
+	systems := []models.SolarSystem{
+		{SolarSystemID: 30000001, SolarSystemName: "Origin", Security: 0.2, X: ly(0)},
+		{SolarSystemID: 30000002, SolarSystemName: "Near", Security: -0.3, X: ly(3)},
+		{SolarSystemID: 30000003, SolarSystemName: "Middle", Security: 0.1, X: ly(6)},
+		{SolarSystemID: 30000004, SolarSystemName: "Far", Security: -0.5, X: ly(9)},
+		{SolarSystemID: 30000005, SolarSystemName: "Highsec", Security: 0.8, X: ly(1)},
+		{SolarSystemID: 30000006, SolarSystemName: "Pochven", Security: -1.0, RegionID: eve.PochvenRegionID, X: ly(2)},
+		{SolarSystemID: 31000001, SolarSystemName: "J100001", Security: -1.0, X: ly(1.5)},
+		{SolarSystemID: 30000007, SolarSystemName: "Stepping", Security: 0.0, X: ly(4.5)},
+	}
+	return New(systems, nil)
+}
+
+func TestDistance(t *testing.T) {
+	a := System{X: 0, Y: 0, Z: 0}
+	b := System{X: 3 * MetersPerLightYear, Y: 4 * MetersPerLightYear, Z: 0}
+	if d := Distance(a, b); math.Abs(d-5) > 1e-9 {
+		t.Errorf("Expected 5 LY, got %v", d)
+	}
+}
+
+func TestShipClassRange(t *testing.T) {
+	carrier, err := ParseShipClass("Carrier")
+	if err != nil {
+		t.Fatalf("ParseShipClass failed: %v", err)
+	}
+	tests := []struct {
+		jdc  int
+		want float64
+	}{
+		{0, 3.5},
+		{4, 6.3},
+		{5, 7.0},
+	}
+	for _, tt := range tests {
+		if got := carrier.Range(tt.jdc); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Range(%d): expected %v, got %v", tt.jdc, tt.want, got)
+		}
+	}
+
+	if _, err := ParseShipClass("shuttle"); err == nil {
+		t.Error("Expected an error for an unknown ship class")
+	}
+	if got := MaxJumpRange(); got != 10 {
+		t.Errorf("Expected a longest range of 10 LY, got %v", got)
+	}
+}
+
+func TestJumpRestrictions(t *testing.T) {
+	g := jumpGraph()
+
+	tests := []struct {
+		id       int64
+		wantFrom bool
+		wantTo   bool
+	}{
+		{30000001, true, true},   // Low security
+		{30000005, true, false},  // High security: can leave, cannot enter
+		{30000006, false, false}, // Pochven
+		{31000001, false, false}, // Wormhole space
+	}
+	for _, tt := range tests {
+		sys, _ := g.System(tt.id)
+		if got := CanJumpFrom(sys); got != tt.wantFrom {
+			t.Errorf("CanJumpFrom(%s): expected %v, got %v", sys.Name, tt.wantFrom, got)
+		}
+		if got := CanJumpTo(sys); got != tt.wantTo {
+			t.Errorf("CanJumpTo(%s): expected %v, got %v", sys.Name, tt.wantTo, got)
+		}
+	}
+}
+
+func TestJumpNeighbors(t *testing.T) {
+	g := jumpGraph()
+
+	targets, err := g.JumpNeighbors(30000001, 5)
+	if err != nil {
+		t.Fatalf("JumpNeighbors failed: %v", err)
+	}
+	want := []int64{30000002, 30000007}
+	if len(targets) != len(want) {
+		t.Fatalf("Expected targets %v, got %+v", want, targets)
+	}
+	for i, target := range targets {
+		if target.System.ID != want[i] {
+			t.Errorf("Target %d: expected %d, got %d", i, want[i], target.System.ID)
+		}
+	}
+	if math.Abs(targets[0].Distance-3) > 1e-9 {
+		t.Errorf("Expected 3 LY to Near, got %v", targets[0].Distance)
+	}
+
+	if _, err := g.JumpNeighbors(31000001, 5); !errors.Is(err, ErrJumpRestricted) {
+		t.Errorf("Expected ErrJumpRestricted from wormhole space, got %v", err)
+	}
+	if _, err := g.JumpNeighbors(99, 5); !errors.Is(err, ErrUnknownSystem) {
+		t.Errorf("Expected ErrUnknownSystem, got %v", err)
+	}
+}
+
+// jumpRouteGraph has a straight line of three short jumps from Origin to
+// Far and a two-jump detour through Detour that is longer in total.
+func jumpRouteGraph() *Graph {
+	ly := func(x float64) float64 { return x * MetersPerLightYear }
+	systems := []models.SolarSystem{
+		{SolarSystemID: 30000001, SolarSystemName: "Origin", Security: 0.2},
+		{SolarSystemID: 30000002, SolarSystemName: "First", Security: -0.3, X: ly(10.0 / 3)},
+		{SolarSystemID: 30000003, SolarSystemName: "Second", Security: 0.1, X: ly(20.0 / 3)},
+		{SolarSystemID: 30000004, SolarSystemName: "Far", Security: -0.5, X: ly(10)},
+		{SolarSystemID: 30000005, SolarSystemName: "Detour", Security: 0.0, X: ly(5), Y: ly(3.3)},
+	}
+	return New(systems, nil)
+}
+
+func TestJumpRoute(t *testing.T) {
+	g := jumpRouteGraph()
+	carrier, _ := ParseShipClass("carrier")
+	jf, _ := ParseShipClass("jump-freighter")
+
+	tests := []struct {
+		name string
+		opts JumpOptions
+		want []int64
+	}{
+		// 6 LY range reaches the detour: two jumps of 5.99 LY
+		{"fewest jumps", JumpOptions{Ship: jf, JDC: 1}, []int64{30000001, 30000005, 30000004}},
+		// Three 3.33 LY jumps build less fatigue than two 5.99 LY jumps
+		{"least fatigue", JumpOptions{Ship: jf, JDC: 1, Prefer: PreferLeastFatigue}, []int64{30000001, 30000002, 30000003, 30000004}},
+		// 3.5 LY range only allows the straight line
+		{"short range", JumpOptions{Ship: carrier}, []int64{30000001, 30000002, 30000003, 30000004}},
+		{"avoid", JumpOptions{Ship: jf, JDC: 1, Avoid: []int64{30000005}}, []int64{30000001, 30000002, 30000003, 30000004}},
+		{"long range", JumpOptions{Ship: jf, JDC: 5}, []int64{30000001, 30000004}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := g.JumpRoute(30000001, 30000004, tt.opts)
+			if err != nil {
+				t.Fatalf("JumpRoute failed: %v", err)
+			}
+			if len(r.Systems) != len(tt.want) {
+				t.Fatalf("Expected %d systems, got %+v", len(tt.want), r.Systems)
+			}
+			for i, sys := range r.Systems {
+				if sys.ID != tt.want[i] {
+					t.Fatalf("System %d: expected %d, got %d", i, tt.want[i], sys.ID)
+				}
+			}
+			if r.Jumps() != len(r.Distances) {
+				t.Errorf("Expected one distance per jump, got %d for %d jumps", len(r.Distances), r.Jumps())
+			}
+			if r.Fatigue <= 0 {
+				t.Errorf("Expected fatigue to build up, got %v", r.Fatigue)
+			}
+		})
+	}
+}
+
+func TestJumpRoute_Errors(t *testing.T) {
+	g := jumpGraph()
+	carrier, _ := ParseShipClass("carrier")
+
+	tests := []struct {
+		name string
+		from int64
+		to   int64
+		opts JumpOptions
+		want error
+	}{
+		{"into highsec", 30000001, 30000005, JumpOptions{Ship: carrier}, ErrJumpRestricted},
+		{"out of wormhole space", 31000001, 30000001, JumpOptions{Ship: carrier}, ErrJumpRestricted},
+		{"into Pochven", 30000001, 30000006, JumpOptions{Ship: carrier}, ErrJumpRestricted},
+		{"out of range", 30000001, 30000004, JumpOptions{Ship: carrier, Avoid: []int64{30000002, 30000007}}, ErrNoRoute},
+		{"unknown", 30000001, 99, JumpOptions{Ship: carrier}, ErrUnknownSystem},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.JumpRoute(tt.from, tt.to, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := g.JumpRoute(30000001, 30000004, JumpOptions{}); err == nil {
+		t.Error("Expected an error without a ship class")
+	}
+	if _, err := g.JumpRoute(30000001, 30000004, JumpOptions{Ship: carrier, JDC: 6}); err == nil {
+		t.Error("Expected an error for an invalid skill level")
+	}
+}
+
+func TestJumpFatigue(t *testing.T) {
+	tests := []struct {
+		name      string
+		distances []float64
+		reduction float64
+		fatigue   time.Duration
+		wait      time.Duration
+	}{
+		// 10 * (1 + 5) = 60 minutes
+		{"single jump", []float64{5}, 0, 60 * time.Minute, 0},
+		// Cooldown 6 minutes; 54 * (1 + 5) = 324, capped at 300
+		{"capped", []float64{5, 5}, 0, 300 * time.Minute, 6 * time.Minute},
+		// 90% reduction: 10 * (1 + 0.5) = 15 minutes
+		{"reduced", []float64{5}, 0.9, 15 * time.Minute, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fatigue, wait := jumpFatigue(tt.distances, tt.reduction)
+			if fatigue != tt.fatigue || wait != tt.wait {
+				t.Errorf("Expected fatigue %v and wait %v, got %v and %v", tt.fatigue, tt.wait, fatigue, wait)
+			}
+		})
+	}
+}
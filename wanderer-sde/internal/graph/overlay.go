@@ -0,0 +1,200 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ConnectionKind is the kind of connection between two systems.
+type ConnectionKind string
+
+// Connection kinds. Stargates come from the SDE; the others come from an
+// overlay.
+const (
+	KindGate       ConnectionKind = "gate"
+	KindWormhole   ConnectionKind = "wormhole"
+	KindJumpBridge ConnectionKind = "jump_bridge"
+	KindThera      ConnectionKind = "thera"
+)
+
+// kindOrder ranks connection kinds so edges sort stably.
+var kindOrder = map[ConnectionKind]int{
+	KindGate:       0,
+	KindJumpBridge: 1,
+	KindWormhole:   2,
+	KindThera:      3,
+}
+
+// ShipSize is the largest ship class a connection lets through, using the
+// wormhole size classes.
+type ShipSize string
+
+// Ship sizes, smallest first.
+const (
+	ShipSmall   ShipSize = "small"   // Frigates and destroyers
+	ShipMedium  ShipSize = "medium"  // Up to battlecruisers
+	ShipLarge   ShipSize = "large"   // Up to battleships
+	ShipXLarge  ShipSize = "xlarge"  // Freighters and industrial command ships
+	ShipCapital ShipSize = "capital" // Capital ships
+)
+
+// shipSizeOrder ranks ship sizes, smallest first.
+var shipSizeOrder = map[ShipSize]int{
+	ShipSmall:   1,
+	ShipMedium:  2,
+	ShipLarge:   3,
+	ShipXLarge:  4,
+	ShipCapital: 5,
+}
+
+// ParseShipSize parses a ship size name. The empty string is an unset size.
+func ParseShipSize(name string) (ShipSize, error) {
+	size := ShipSize(name)
+	if _, ok := shipSizeOrder[size]; !ok && name != "" {
+		return "", fmt.Errorf("invalid ship size %q: must be %q, %q, %q, %q or %q",
+			name, ShipSmall, ShipMedium, ShipLarge, ShipXLarge, ShipCapital)
+	}
+	return size, nil
+}
+
+// allows reports whether a connection limited to size lets a ship of the
+// given size through. Unset sizes allow everything.
+func (size ShipSize) allows(ship ShipSize) bool {
+	if size == "" || ship == "" {
+		return true
+	}
+	return shipSizeOrder[ship] <= shipSizeOrder[size]
+}
+
+// SystemRef names a system in an overlay file by ID or name. In JSON it is
+// either a number or a string.
+type SystemRef string
+
+// UnmarshalJSON accepts a JSON number or string.
+func (r *SystemRef) UnmarshalJSON(data []byte) error {
+	var id int64
+	if err := json.Unmarshal(data, &id); err == nil {
+		*r = SystemRef(strconv.FormatInt(id, 10))
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("system must be an ID or a name: %s", data)
+	}
+	*r = SystemRef(name)
+	return nil
+}
+
+// Connection is a temporary or player-built connection between two systems,
+// usable in both directions.
+type Connection struct {
+	From SystemRef      `json:"from"`
+	To   SystemRef      `json:"to"`
+	Kind ConnectionKind `json:"type"`
+
+	// Optional attributes. Zero values mean unknown or unlimited.
+	ShipSize       ShipSize   `json:"shipSize,omitempty"`       // Largest ship class allowed through
+	TotalMass      int64      `json:"totalMass,omitempty"`      // Remaining mass in kg
+	MaxMassPerJump int64      `json:"maxMassPerJump,omitempty"` // Heaviest ship in kg
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`      // When the connection closes
+}
+
+// Overlay is a set of connections added to the stargate graph, read from
+// a JSON file of the form {"connections": [...]}.
+type Overlay struct {
+	Connections []Connection `json:"connections"`
+}
+
+// LoadOverlay reads an overlay from a JSON file.
+func LoadOverlay(path string) (*Overlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay file: %w", err)
+	}
+	overlay, err := ParseOverlay(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return overlay, nil
+}
+
+// ParseOverlay reads an overlay from JSON and checks its connection kinds
+// and ship sizes.
+func ParseOverlay(r io.Reader) (*Overlay, error) {
+	var overlay Overlay
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&overlay); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay: %w", err)
+	}
+
+	for i, c := range overlay.Connections {
+		if c.From == "" || c.To == "" {
+			return nil, fmt.Errorf("connection %d: from and to must be set", i)
+		}
+		switch c.Kind {
+		case KindWormhole, KindJumpBridge, KindThera:
+		default:
+			return nil, fmt.Errorf("connection %d: invalid type %q: must be %q, %q or %q",
+				i, c.Kind, KindWormhole, KindJumpBridge, KindThera)
+		}
+		if _, err := ParseShipSize(string(c.ShipSize)); err != nil {
+			return nil, fmt.Errorf("connection %d: %w", i, err)
+		}
+	}
+	return &overlay, nil
+}
+
+// WithOverlay returns a copy of the graph with the overlay's connections
+// added in both directions. Connections that expired at or before now are
+// left out. The receiver is not changed. It fails on systems that are not
+// in the graph.
+func (g *Graph) WithOverlay(overlay *Overlay, now time.Time) (*Graph, error) {
+	out := &Graph{
+		systems: g.systems,
+		index:   g.index,
+		names:   g.names,
+		adj:     make([][]edge, len(g.adj)),
+	}
+	copy(out.adj, g.adj)
+
+	touched := make(map[int]bool)
+	for i := range overlay.Connections {
+		c := &overlay.Connections[i]
+		if c.ExpiresAt != nil && !c.ExpiresAt.After(now) {
+			continue
+		}
+		from, err := g.Lookup(string(c.From))
+		if err != nil {
+			return nil, fmt.Errorf("overlay connection %d: %w", i, err)
+		}
+		to, err := g.Lookup(string(c.To))
+		if err != nil {
+			return nil, fmt.Errorf("overlay connection %d: %w", i, err)
+		}
+
+		a, b := g.index[from.ID], g.index[to.ID]
+		if a == b {
+			continue
+		}
+		for _, n := range []int{a, b} {
+			if !touched[n] {
+				// Copy before appending so the receiver's edges are not shared
+				out.adj[n] = append([]edge(nil), g.adj[n]...)
+				touched[n] = true
+			}
+		}
+		out.adj[a] = append(out.adj[a], edge{to: b, conn: c})
+		out.adj[b] = append(out.adj[b], edge{to: a, conn: c})
+	}
+
+	for n := range touched {
+		sortEdges(out.adj[n])
+	}
+	return out, nil
+}
@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOverlay(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"IDs and names", `{"connections": [{"from": 1, "to": "Foxtrot", "type": "wormhole", "shipSize": "large", "maxMassPerJump": 375000000, "expiresAt": "2026-01-01T00:00:00Z"}]}`, false},
+		{"jump bridge", `{"connections": [{"from": "Alpha", "to": "Delta", "type": "jump_bridge"}]}`, false},
+		{"empty", `{"connections": []}`, false},
+		{"invalid type", `{"connections": [{"from": 1, "to": 2, "type": "gate"}]}`, true},
+		{"invalid ship size", `{"connections": [{"from": 1, "to": 2, "type": "thera", "shipSize": "huge"}]}`, true},
+		{"missing system", `{"connections": [{"from": 1, "type": "wormhole"}]}`, true},
+		{"unknown field", `{"connections": [{"from": 1, "to": 2, "type": "wormhole", "colour": "blue"}]}`, true},
+		{"invalid system", `{"connections": [{"from": true, "to": 2, "type": "wormhole"}]}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOverlay(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadOverlay(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "overlay-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	path := filepath.Join(tmpDir, "overlay.json")
+	content := `{"connections": [{"from": 30000142, "to": "J123456", "type": "wormhole", "shipSize": "medium"}]}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write overlay: %v", err)
+	}
+
+	overlay, err := LoadOverlay(path)
+	if err != nil {
+		t.Fatalf("LoadOverlay failed: %v", err)
+	}
+	if len(overlay.Connections) != 1 {
+		t.Fatalf("Expected 1 connection, got %d", len(overlay.Connections))
+	}
+	c := overlay.Connections[0]
+	if c.From != "30000142" || c.To != "J123456" || c.Kind != KindWormhole || c.ShipSize != ShipMedium {
+		t.Errorf("Unexpected connection: %+v", c)
+	}
+
+	if _, err := LoadOverlay(filepath.Join(tmpDir, "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestWithOverlay(t *testing.T) {
+	g := testGraph()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	overlay := &Overlay{Connections: []Connection{
+		{From: "Alpha", To: "Foxtrot", Kind: KindWormhole, ShipSize: ShipMedium},
+		{From: "Foxtrot", To: "4", Kind: KindThera, ExpiresAt: &future},
+		{From: "Alpha", To: "Echo", Kind: KindJumpBridge, ExpiresAt: &past},
+	}}
+
+	withOverlay, err := g.WithOverlay(overlay, now)
+	if err != nil {
+		t.Fatalf("WithOverlay failed: %v", err)
+	}
+
+	if got := withOverlay.Neighbors(6); len(got) != 2 || got[0] != 1 || got[1] != 4 {
+		t.Errorf("Expected Foxtrot to connect to Alpha and Delta, got %v", got)
+	}
+	if got := withOverlay.Neighbors(1); len(got) != 3 {
+		t.Errorf("Expected the expired jump bridge to be left out, got %v", got)
+	}
+	if got := g.Neighbors(6); len(got) != 0 {
+		t.Errorf("Expected the original graph to be unchanged, got %v", got)
+	}
+
+	bad := &Overlay{Connections: []Connection{{From: "Alpha", To: "Jita", Kind: KindWormhole}}}
+	if _, err := g.WithOverlay(bad, now); err == nil {
+		t.Error("Expected an error for an unknown system")
+	}
+}
+
+func TestRoute_Overlay(t *testing.T) {
+	g := testGraph()
+	overlay := &Overlay{Connections: []Connection{
+		{From: "Charlie", To: "Delta", Kind: KindWormhole, ShipSize: ShipMedium, MaxMassPerJump: 62000000, TotalMass: 45000000},
+	}}
+	withOverlay, err := g.WithOverlay(overlay, time.Now())
+	if err != nil {
+		t.Fatalf("WithOverlay failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    RouteOptions
+		want    []int64
+		wantVia []ConnectionKind
+	}{
+		{"safer through wormhole", RouteOptions{Prefer: PreferSafer}, []int64{1, 3, 4}, []ConnectionKind{"", KindGate, KindWormhole}},
+		{"fits ship size", RouteOptions{Prefer: PreferSafer, ShipSize: ShipSmall}, []int64{1, 3, 4}, []ConnectionKind{"", KindGate, KindWormhole}},
+		{"too large", RouteOptions{Prefer: PreferSafer, ShipSize: ShipLarge}, []int64{1, 3, 5, 4}, []ConnectionKind{"", KindGate, KindGate, KindGate}},
+		{"too heavy", RouteOptions{Prefer: PreferSafer, ShipMass: 100000000}, []int64{1, 3, 5, 4}, []ConnectionKind{"", KindGate, KindGate, KindGate}},
+		{"too little mass left", RouteOptions{Prefer: PreferSafer, ShipMass: 50000000}, []int64{1, 3, 5, 4}, []ConnectionKind{"", KindGate, KindGate, KindGate}},
+		{"enough mass left", RouteOptions{Prefer: PreferSafer, ShipMass: 40000000}, []int64{1, 3, 4}, []ConnectionKind{"", KindGate, KindWormhole}},
+		{"shortest prefers gates on ties", RouteOptions{}, []int64{1, 2, 4}, []ConnectionKind{"", KindGate, KindGate}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := withOverlay.Route(1, 4, tt.opts)
+			if err != nil {
+				t.Fatalf("Route failed: %v", err)
+			}
+			got := routeIDs(r)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected route %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] || r.Via[i] != tt.wantVia[i] {
+					t.Fatalf("Expected route %v via %v, got %v via %v", tt.want, tt.wantVia, got, r.Via)
+				}
+			}
+		})
+	}
+}
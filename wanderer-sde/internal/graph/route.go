@@ -0,0 +1,228 @@
+package graph
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	"github.com/guarzo/wanderer-sde/internal/eve"
+)
+
+// Preference selects how a route weighs the security of the systems it
+// passes through, matching the in-game autopilot settings.
+type Preference string
+
+// Route preferences.
+const (
+	PreferShortest   Preference = "shortest"    // Fewest jumps
+	PreferSafer      Preference = "safer"       // Avoid low and null security space
+	PreferLessSecure Preference = "less-secure" // Avoid high security space
+)
+
+// DefaultSecurityPenalty is the in-game default of the autopilot's security
+// penalty setting.
+const DefaultSecurityPenalty = 50
+
+// ParsePreference parses a route preference name.
+func ParsePreference(name string) (Preference, error) {
+	switch p := Preference(name); p {
+	case PreferShortest, PreferSafer, PreferLessSecure:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid route preference %q: must be %q, %q or %q",
+			name, PreferShortest, PreferSafer, PreferLessSecure)
+	}
+}
+
+// RouteOptions controls route planning.
+type RouteOptions struct {
+	// Prefer selects the weighting; empty means PreferShortest.
+	Prefer Preference
+
+	// Avoid lists systems the route must not enter. The origin may be
+	// avoided; an avoided destination has no route.
+	Avoid []int64
+
+	// SecurityPenalty is the autopilot security penalty from 0 to 100 used
+	// by the safer and less-secure preferences. Zero means
+	// DefaultSecurityPenalty.
+	SecurityPenalty float64
+
+	// ShipSize and ShipMass describe the ship flying the route. Overlay
+	// connections too small for it, with a lower per-jump mass limit or
+	// with less total mass left than it weighs are not used; zero values
+	// skip the check. Stargates take any ship.
+	ShipSize ShipSize
+	ShipMass int64
+}
+
+// allows reports whether the ship described by opts can take an edge.
+func (opts RouteOptions) allows(e edge) bool {
+	if e.conn == nil {
+		return true
+	}
+	if !e.conn.ShipSize.allows(opts.ShipSize) {
+		return false
+	}
+	if opts.ShipMass == 0 {
+		return true
+	}
+	if e.conn.MaxMassPerJump > 0 && opts.ShipMass > e.conn.MaxMassPerJump {
+		return false
+	}
+	return e.conn.TotalMass == 0 || opts.ShipMass <= e.conn.TotalMass
+}
+
+// Route is a planned path between two systems.
+type Route struct {
+	Systems []System         // Origin first, destination last
+	Via     []ConnectionKind // How each system was entered; empty for the origin
+	Cost    float64          // Total weight of the route under its preference
+}
+
+// Jumps returns the number of jumps on the route, through stargates and
+// overlay connections alike. Via tells them apart.
+func (r *Route) Jumps() int {
+	return len(r.Systems) - 1
+}
+
+// Route finds the lowest-cost route from one system to another, through
+// stargates and any overlay connections the ship fits. Each jump costs 1,
+// except that under PreferSafer entering a low or null security system,
+// and under PreferLessSecure entering a high security system, costs
+// exp(0.15 * penalty) as in-game. Ties are broken by fewer jumps and
+// then by lower system IDs, so routes are stable between runs.
+func (g *Graph) Route(from, to int64, opts RouteOptions) (*Route, error) {
+	src, ok := g.index[from]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownSystem, from)
+	}
+	dst, ok := g.index[to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownSystem, to)
+	}
+
+	prefer := opts.Prefer
+	if prefer == "" {
+		prefer = PreferShortest
+	}
+	if _, err := ParsePreference(string(prefer)); err != nil {
+		return nil, err
+	}
+	penalty := opts.SecurityPenalty
+	if penalty == 0 {
+		penalty = DefaultSecurityPenalty
+	}
+	penaltyCost := math.Exp(0.15 * penalty)
+
+	avoid := make([]bool, len(g.systems))
+	for _, id := range opts.Avoid {
+		if i, ok := g.index[id]; ok {
+			avoid[i] = true
+		}
+	}
+
+	cost := func(i int) float64 {
+		highSec := isHighSec(g.systems[i].Security)
+		switch {
+		case prefer == PreferSafer && !highSec:
+			return penaltyCost
+		case prefer == PreferLessSecure && highSec:
+			return penaltyCost
+		default:
+			return 1
+		}
+	}
+
+	dist := make([]float64, len(g.systems))
+	jumps := make([]int, len(g.systems))
+	prev := make([]int, len(g.systems))
+	via := make([]ConnectionKind, len(g.systems))
+	done := make([]bool, len(g.systems))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[src] = 0
+
+	queue := &routeQueue{{node: src}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(routeItem)
+		if done[item.node] {
+			continue
+		}
+		done[item.node] = true
+		if item.node == dst {
+			break
+		}
+
+		for _, e := range g.adj[item.node] {
+			n := e.to
+			if done[n] || avoid[n] || !opts.allows(e) {
+				continue
+			}
+			d := dist[item.node] + cost(n)
+			j := jumps[item.node] + 1
+			if d < dist[n] || (d == dist[n] && j < jumps[n]) {
+				dist[n] = d
+				jumps[n] = j
+				prev[n] = item.node
+				via[n] = e.kind()
+				heap.Push(queue, routeItem{node: n, cost: d, tie: float64(j)})
+			}
+		}
+	}
+
+	if !done[dst] {
+		return nil, fmt.Errorf("%w from %s to %s", ErrNoRoute, g.systems[src].Name, g.systems[dst].Name)
+	}
+
+	path := make([]System, jumps[dst]+1)
+	kinds := make([]ConnectionKind, len(path))
+	for i, n := len(path)-1, dst; n >= 0; i, n = i-1, prev[n] {
+		path[i] = g.systems[n]
+		kinds[i] = via[n]
+	}
+	return &Route{Systems: path, Via: kinds, Cost: dist[dst]}, nil
+}
+
+// isHighSec reports whether a raw security status displays as 0.5 or
+// above.
+func isHighSec(security float64) bool {
+	return eve.TrueSecurity(security) >= 0.5
+}
+
+// routeItem is a tentative distance in a route search queue. tie breaks
+// equal costs, e.g. the number of jumps.
+type routeItem struct {
+	node int
+	cost float64
+	tie  float64
+}
+
+// routeQueue is a min-heap of routeItems ordered by cost, then tie, then
+// node position (and so system ID).
+type routeQueue []routeItem
+
+func (q routeQueue) Len() int { return len(q) }
+
+func (q routeQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	if q[i].tie != q[j].tie {
+		return q[i].tie < q[j].tie
+	}
+	return q[i].node < q[j].node
+}
+
+func (q routeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *routeQueue) Push(x interface{}) { *q = append(*q, x.(routeItem)) }
+
+func (q *routeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
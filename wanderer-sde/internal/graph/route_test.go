@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+func routeIDs(r *Route) []int64 {
+	ids := make([]int64, len(r.Systems))
+	for i, sys := range r.Systems {
+		ids[i] = sys.ID
+	}
+	return ids
+}
+
+func TestRoute(t *testing.T) {
+	g := testGraph()
+
+	tests := []struct {
+		name string
+		from int64
+		to   int64
+		opts RouteOptions
+		want []int64
+	}{
+		{"shortest", 1, 4, RouteOptions{}, []int64{1, 2, 4}},
+		{"safer avoids low security", 1, 4, RouteOptions{Prefer: PreferSafer}, []int64{1, 3, 5, 4}},
+		{"less secure prefers low security", 1, 4, RouteOptions{Prefer: PreferLessSecure}, []int64{1, 2, 4}},
+		{"avoid list", 1, 4, RouteOptions{Avoid: []int64{2}}, []int64{1, 3, 5, 4}},
+		{"avoided origin", 2, 4, RouteOptions{Avoid: []int64{2}}, []int64{2, 4}},
+		{"same system", 3, 3, RouteOptions{}, []int64{3}},
+		{"low penalty keeps short route", 1, 4, RouteOptions{Prefer: PreferSafer, SecurityPenalty: 1}, []int64{1, 2, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := g.Route(tt.from, tt.to, tt.opts)
+			if err != nil {
+				t.Fatalf("Route failed: %v", err)
+			}
+			got := routeIDs(r)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected route %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Expected route %v, got %v", tt.want, got)
+				}
+			}
+			if r.Jumps() != len(tt.want)-1 {
+				t.Errorf("Expected %d jumps, got %d", len(tt.want)-1, r.Jumps())
+			}
+		})
+	}
+}
+
+func TestRoute_Errors(t *testing.T) {
+	g := testGraph()
+
+	tests := []struct {
+		name string
+		from int64
+		to   int64
+		opts RouteOptions
+		want error
+	}{
+		{"unknown origin", 99, 1, RouteOptions{}, ErrUnknownSystem},
+		{"unknown destination", 1, 99, RouteOptions{}, ErrUnknownSystem},
+		{"unreachable", 1, 6, RouteOptions{}, ErrNoRoute},
+		{"avoided destination", 1, 4, RouteOptions{Avoid: []int64{4}}, ErrNoRoute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := g.Route(tt.from, tt.to, tt.opts)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := g.Route(1, 4, RouteOptions{Prefer: "fastest"}); err == nil {
+		t.Error("Expected an error for an invalid preference")
+	}
+}
+
+func TestIsHighSec(t *testing.T) {
+	tests := []struct {
+		security float64
+		want     bool
+	}{
+		{1.0, true},
+		{0.5, true},
+		{0.45, true},
+		{0.449, false},
+		{0.3, false},
+		{0.0, false},
+		{-1.0, false},
+	}
+	for _, tt := range tests {
+		if got := isHighSec(tt.security); got != tt.want {
+			t.Errorf("isHighSec(%v): expected %v, got %v", tt.security, tt.want, got)
+		}
+	}
+}
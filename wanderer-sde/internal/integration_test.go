@@ -1,13 +1,18 @@
 package internal
 
 import (
+	"context"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/downloader"
 	"github.com/guarzo/wanderer-sde/internal/models"
 	"github.com/guarzo/wanderer-sde/internal/parser"
 	"github.com/guarzo/wanderer-sde/internal/transformer"
@@ -357,8 +362,8 @@ func TestIntegration_FullPipeline(t *testing.T) {
 	}
 
 	// Step 1: Parse
-	p := parser.New(cfg, sdeDir)
-	parseResult, err := p.ParseAll()
+	p := parser.New(cfg, sdeDir, nil)
+	parseResult, err := p.ParseAll(context.Background())
 	if err != nil {
 		t.Fatalf("ParseAll failed: %v", err)
 	}
@@ -390,8 +395,8 @@ func TestIntegration_FullPipeline(t *testing.T) {
 	})
 
 	// Step 2: Transform
-	tr := transformer.New(cfg)
-	convertedData, err := tr.Transform(parseResult)
+	tr := transformer.New(cfg, nil)
+	convertedData, err := tr.Transform(context.Background(), parseResult)
 	if err != nil {
 		t.Fatalf("Transform failed: %v", err)
 	}
@@ -448,11 +453,11 @@ func TestIntegration_FullPipeline(t *testing.T) {
 	})
 
 	// Step 4: Write output
-	w, err := writer.NewWriter(cfg)
+	w, err := writer.NewWriter(cfg, nil)
 	if err != nil {
 		t.Fatalf("NewWriter failed: %v", err)
 	}
-	if err := w.WriteAll(convertedData); err != nil {
+	if err := w.WriteAll(context.Background(), convertedData); err != nil {
 		t.Fatalf("WriteAll failed: %v", err)
 	}
 
@@ -519,8 +524,8 @@ func TestIntegration_PassthroughFiles(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := writer.NewJSONWriter(cfg)
-	if err := w.CopyPassthroughFiles(srcDir); err != nil {
+	w := writer.NewJSONWriter(cfg, nil)
+	if err := w.CopyPassthroughFiles(context.Background(), srcDir); err != nil {
 		t.Fatalf("CopyPassthroughFiles failed: %v", err)
 	}
 
@@ -542,14 +547,14 @@ func TestIntegration_SortingConsistency(t *testing.T) {
 		Verbose: false,
 	}
 
-	p := parser.New(cfg, sdeDir)
-	parseResult, err := p.ParseAll()
+	p := parser.New(cfg, sdeDir, nil)
+	parseResult, err := p.ParseAll(context.Background())
 	if err != nil {
 		t.Fatalf("ParseAll failed: %v", err)
 	}
 
-	tr := transformer.New(cfg)
-	convertedData, err := tr.Transform(parseResult)
+	tr := transformer.New(cfg, nil)
+	convertedData, err := tr.Transform(context.Background(), parseResult)
 	if err != nil {
 		t.Fatalf("Transform failed: %v", err)
 	}
@@ -631,14 +636,14 @@ func TestIntegration_DataIntegrity(t *testing.T) {
 		Verbose: false,
 	}
 
-	p := parser.New(cfg, sdeDir)
-	parseResult, err := p.ParseAll()
+	p := parser.New(cfg, sdeDir, nil)
+	parseResult, err := p.ParseAll(context.Background())
 	if err != nil {
 		t.Fatalf("ParseAll failed: %v", err)
 	}
 
-	tr := transformer.New(cfg)
-	convertedData, err := tr.Transform(parseResult)
+	tr := transformer.New(cfg, nil)
+	convertedData, err := tr.Transform(context.Background(), parseResult)
 	if err != nil {
 		t.Fatalf("Transform failed: %v", err)
 	}
@@ -719,22 +724,22 @@ func TestIntegration_CSVOutput(t *testing.T) {
 	}
 
 	// Parse
-	p := parser.New(cfg, sdeDir)
-	parseResult, err := p.ParseAll()
+	p := parser.New(cfg, sdeDir, nil)
+	parseResult, err := p.ParseAll(context.Background())
 	if err != nil {
 		t.Fatalf("ParseAll failed: %v", err)
 	}
 
 	// Transform
-	tr := transformer.New(cfg)
-	convertedData, err := tr.Transform(parseResult)
+	tr := transformer.New(cfg, nil)
+	convertedData, err := tr.Transform(context.Background(), parseResult)
 	if err != nil {
 		t.Fatalf("Transform failed: %v", err)
 	}
 
 	// Write CSV
-	w := writer.NewCSVWriter(cfg)
-	if err := w.WriteAll(convertedData); err != nil {
+	w := writer.NewCSVWriter(cfg, nil)
+	if err := w.WriteAll(context.Background(), convertedData); err != nil {
 		t.Fatalf("WriteAll failed: %v", err)
 	}
 
@@ -772,21 +777,21 @@ func TestIntegration_CSVFuzzworkFormat(t *testing.T) {
 	}
 
 	// Parse and transform
-	p := parser.New(cfg, sdeDir)
-	parseResult, err := p.ParseAll()
+	p := parser.New(cfg, sdeDir, nil)
+	parseResult, err := p.ParseAll(context.Background())
 	if err != nil {
 		t.Fatalf("ParseAll failed: %v", err)
 	}
 
-	tr := transformer.New(cfg)
-	convertedData, err := tr.Transform(parseResult)
+	tr := transformer.New(cfg, nil)
+	convertedData, err := tr.Transform(context.Background(), parseResult)
 	if err != nil {
 		t.Fatalf("Transform failed: %v", err)
 	}
 
 	// Write CSV
-	w := writer.NewCSVWriter(cfg)
-	if err := w.WriteAll(convertedData); err != nil {
+	w := writer.NewCSVWriter(cfg, nil)
+	if err := w.WriteAll(context.Background(), convertedData); err != nil {
 		t.Fatalf("WriteAll failed: %v", err)
 	}
 
@@ -888,20 +893,20 @@ func TestIntegration_CSVNullHandling(t *testing.T) {
 		Verbose:      false,
 	}
 
-	p := parser.New(cfg, sdeDir)
-	parseResult, err := p.ParseAll()
+	p := parser.New(cfg, sdeDir, nil)
+	parseResult, err := p.ParseAll(context.Background())
 	if err != nil {
 		t.Fatalf("ParseAll failed: %v", err)
 	}
 
-	tr := transformer.New(cfg)
-	convertedData, err := tr.Transform(parseResult)
+	tr := transformer.New(cfg, nil)
+	convertedData, err := tr.Transform(context.Background(), parseResult)
 	if err != nil {
 		t.Fatalf("Transform failed: %v", err)
 	}
 
-	w := writer.NewCSVWriter(cfg)
-	if err := w.WriteAll(convertedData); err != nil {
+	w := writer.NewCSVWriter(cfg, nil)
+	if err := w.WriteAll(context.Background(), convertedData); err != nil {
 		t.Fatalf("WriteAll failed: %v", err)
 	}
 
@@ -957,20 +962,20 @@ func TestIntegration_CSVColumnOrdering(t *testing.T) {
 		Verbose:      false,
 	}
 
-	p := parser.New(cfg, sdeDir)
-	parseResult, err := p.ParseAll()
+	p := parser.New(cfg, sdeDir, nil)
+	parseResult, err := p.ParseAll(context.Background())
 	if err != nil {
 		t.Fatalf("ParseAll failed: %v", err)
 	}
 
-	tr := transformer.New(cfg)
-	convertedData, err := tr.Transform(parseResult)
+	tr := transformer.New(cfg, nil)
+	convertedData, err := tr.Transform(context.Background(), parseResult)
 	if err != nil {
 		t.Fatalf("Transform failed: %v", err)
 	}
 
-	w := writer.NewCSVWriter(cfg)
-	if err := w.WriteAll(convertedData); err != nil {
+	w := writer.NewCSVWriter(cfg, nil)
+	if err := w.WriteAll(context.Background(), convertedData); err != nil {
 		t.Fatalf("WriteAll failed: %v", err)
 	}
 
@@ -1062,3 +1067,262 @@ func validateCSVRowCount(t *testing.T, dir, filename string, expectedDataRows in
 		t.Errorf("file %s: expected %d rows (including header), got %d", filename, expectedTotal, len(records))
 	}
 }
+
+// runReproduciblePipeline converts the SDE at sdeDir into outputDir in
+// reproducible mode and writes the manifest.
+func runReproduciblePipeline(t *testing.T, sdeDir, outputDir string, format config.OutputFormat) {
+	t.Helper()
+
+	cfg := &config.Config{
+		SDEPath:         sdeDir,
+		OutputDir:       outputDir,
+		OutputFormat:    format,
+		PrettyPrint:     true,
+		SecurityColumns: true,
+		Reproducible:    true,
+	}
+
+	parseResult, err := parser.New(cfg, sdeDir, nil).ParseAll(context.Background())
+	if err != nil {
+		t.Fatalf("ParseAll failed: %v", err)
+	}
+	convertedData, err := transformer.New(cfg, nil).Transform(context.Background(), parseResult)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	w, err := writer.NewWriter(cfg, nil)
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	if err := w.WriteAll(context.Background(), convertedData); err != nil {
+		t.Fatalf("WriteAll failed: %v", err)
+	}
+
+	// As after a download, which records the SDE version
+	versionInfo := &downloader.VersionInfo{BuildNumber: "3000000", ReleaseDate: "2026-01-01T00:00:00Z"}
+	generatedAt, err := writer.MetadataTime(cfg, versionInfo)
+	if err != nil {
+		t.Fatalf("MetadataTime failed: %v", err)
+	}
+	if err := writer.WriteMetadata(outputDir, generatedAt, versionInfo, nil); err != nil {
+		t.Fatalf("WriteMetadata failed: %v", err)
+	}
+
+	files := append(w.Files(), writer.MetadataFileName)
+	if _, err := writer.WriteManifest(outputDir, files); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+}
+
+func TestIntegration_ReproducibleOutput(t *testing.T) {
+	sdeDir := createTestSDE(t)
+	defer func() { _ = os.RemoveAll(sdeDir) }()
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	for _, format := range []config.OutputFormat{config.FormatCSV, config.FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var outputDirs [2]string
+			for i := range outputDirs {
+				dir, err := os.MkdirTemp("", "integration_reproducible")
+				if err != nil {
+					t.Fatalf("failed to create output dir: %v", err)
+				}
+				defer func() { _ = os.RemoveAll(dir) }()
+				outputDirs[i] = dir
+				runReproduciblePipeline(t, sdeDir, dir, format)
+			}
+
+			entries, err := os.ReadDir(outputDirs[0])
+			if err != nil {
+				t.Fatalf("failed to read output dir: %v", err)
+			}
+			second, err := os.ReadDir(outputDirs[1])
+			if err != nil {
+				t.Fatalf("failed to read output dir: %v", err)
+			}
+			if len(entries) != len(second) {
+				t.Fatalf("Expected %d files in both runs, got %d", len(entries), len(second))
+			}
+
+			for _, entry := range entries {
+				first, err := os.ReadFile(filepath.Join(outputDirs[0], entry.Name()))
+				if err != nil {
+					t.Fatalf("failed to read %s: %v", entry.Name(), err)
+				}
+				again, err := os.ReadFile(filepath.Join(outputDirs[1], entry.Name()))
+				if err != nil {
+					t.Fatalf("failed to read %s from second run: %v", entry.Name(), err)
+				}
+				if string(first) != string(again) {
+					t.Errorf("Expected identical bytes for %s across runs", entry.Name())
+				}
+			}
+
+			// The metadata and the manifest are the files most at risk
+			for _, name := range []string{writer.MetadataFileName, writer.ManifestFileName} {
+				first, errFirst := os.ReadFile(filepath.Join(outputDirs[0], name))
+				again, errAgain := os.ReadFile(filepath.Join(outputDirs[1], name))
+				if errFirst != nil || errAgain != nil {
+					t.Fatalf("Expected %s in both runs: %v, %v", name, errFirst, errAgain)
+				}
+				if string(first) != string(again) {
+					t.Errorf("Expected identical %s across runs", name)
+				}
+			}
+
+			content, err := os.ReadFile(filepath.Join(outputDirs[0], writer.MetadataFileName))
+			if err != nil {
+				t.Fatalf("failed to read metadata: %v", err)
+			}
+			var metadata writer.SDEMetadata
+			if err := json.Unmarshal(content, &metadata); err != nil {
+				t.Fatalf("failed to parse metadata: %v", err)
+			}
+			if metadata.GeneratedAt != "2023-11-14T22:13:20Z" {
+				t.Errorf("Expected generated_at from SOURCE_DATE_EPOCH, got %s", metadata.GeneratedAt)
+			}
+
+			manifest, err := os.ReadFile(filepath.Join(outputDirs[0], writer.ManifestFileName))
+			if err != nil {
+				t.Fatalf("failed to read manifest: %v", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(string(manifest), "\n"), "\n")
+			if len(lines) != len(entries)-1 {
+				t.Errorf("Expected manifest to list %d files, got %d", len(entries)-1, len(lines))
+			}
+			if !strings.Contains(string(manifest), "  "+writer.MetadataFileName+"\n") {
+				t.Errorf("Expected manifest to list %s, got %q", writer.MetadataFileName, manifest)
+			}
+		})
+	}
+}
+
+// cancelAfter is a context that reports cancellation once Err has been
+// called n times, to cancel deterministically part-way through a stage.
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func TestIntegration_Cancellation(t *testing.T) {
+	sdeDir := createTestSDE(t)
+	defer func() { _ = os.RemoveAll(sdeDir) }()
+
+	outputDir, err := os.MkdirTemp("", "integration_cancel")
+	if err != nil {
+		t.Fatalf("failed to create output dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(outputDir) }()
+
+	cfg := &config.Config{
+		SDEPath:      sdeDir,
+		OutputDir:    outputDir,
+		OutputFormat: config.FormatCSV,
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("parse", func(t *testing.T) {
+		if _, err := parser.New(cfg, sdeDir, nil).ParseAll(cancelled); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled before the first file, got %v", err)
+		}
+		// Cancel after a few files have been read
+		if _, err := parser.New(cfg, sdeDir, nil).ParseAll(&cancelAfter{context.Background(), 10}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled part-way through parsing, got %v", err)
+		}
+	})
+
+	parseResult, err := parser.New(cfg, sdeDir, nil).ParseAll(context.Background())
+	if err != nil {
+		t.Fatalf("ParseAll failed: %v", err)
+	}
+
+	t.Run("transform", func(t *testing.T) {
+		if _, err := transformer.New(cfg, nil).Transform(cancelled, parseResult); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled before the first stage, got %v", err)
+		}
+
+		// A stage cancelling the run stops the stages after it
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tr := transformer.New(cfg, nil)
+		err := tr.Register(transformer.Stage{
+			Name:    "cancel",
+			Outputs: []string{"cancel"},
+			Run: func(context.Context, *transformer.Transformer, *transformer.StageState) error {
+				cancel()
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+		if _, err := tr.Transform(ctx, parseResult); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled after the cancelling stage, got %v", err)
+		}
+	})
+
+	convertedData, err := transformer.New(cfg, nil).Transform(context.Background(), parseResult)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	t.Run("write", func(t *testing.T) {
+		// A previous complete output
+		if err := writer.NewCSVWriter(cfg, nil).WriteAll(context.Background(), convertedData); err != nil {
+			t.Fatalf("WriteAll failed: %v", err)
+		}
+		previous, err := os.ReadFile(filepath.Join(outputDir, writer.CSVFileSolarSystems))
+		if err != nil {
+			t.Fatalf("failed to read solar systems: %v", err)
+		}
+
+		// Cancel while the first file's rows are being written
+		convertedData.Universe.SolarSystems[0].SolarSystemName = "Renamed"
+		err = writer.NewCSVWriter(cfg, nil).WriteAll(&cancelAfter{context.Background(), 2}, convertedData)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+
+		current, err := os.ReadFile(filepath.Join(outputDir, writer.CSVFileSolarSystems))
+		if err != nil {
+			t.Fatalf("Expected previous solar systems file to survive, got %v", err)
+		}
+		if string(current) != string(previous) {
+			t.Error("Expected cancelled write to leave the previous file untouched")
+		}
+
+		partial, _ := filepath.Glob(filepath.Join(outputDir, "*.partial"))
+		if len(partial) != 0 {
+			t.Errorf("Expected partial files to be cleaned up, found %v", partial)
+		}
+	})
+
+	t.Run("passthrough", func(t *testing.T) {
+		srcDir, err := os.MkdirTemp("", "integration_cancel_src")
+		if err != nil {
+			t.Fatalf("failed to create src dir: %v", err)
+		}
+		defer func() { _ = os.RemoveAll(srcDir) }()
+		if err := os.WriteFile(filepath.Join(srcDir, "effects.json"), []byte(`[]`), 0644); err != nil {
+			t.Fatalf("failed to write passthrough file: %v", err)
+		}
+
+		if err := writer.NewCSVWriter(cfg, nil).CopyPassthroughFiles(cancelled, srcDir); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, "effects.json")); !os.IsNotExist(err) {
+			t.Error("Expected no passthrough file to be copied after cancellation")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"debug", slog.LevelDebug, false},
+		{"info", slog.LevelInfo, false},
+		{"WARN", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"loud", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := ParseLevel(tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error for level %q", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLevel failed: %v", err)
+			}
+			if level != tt.want {
+				t.Errorf("Expected level %v, got %v", tt.want, level)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatJSON, slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	logger.Debug("hidden")
+	logger.Info("parsing complete", "regions", 3)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 record above the level, got %d: %q", len(lines), buf.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", lines[0], err)
+	}
+	if record["msg"] != "parsing complete" || record["regions"] != float64(3) {
+		t.Errorf("Unexpected record: %v", record)
+	}
+
+	buf.Reset()
+	logger, err = New(&buf, FormatText, slog.LevelDebug)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Debug("shown", "file", "mapRegions.csv")
+	if !strings.Contains(buf.String(), "msg=shown file=mapRegions.csv") {
+		t.Errorf("Expected a text record, got %q", buf.String())
+	}
+
+	if _, err := New(&buf, "xml", slog.LevelInfo); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
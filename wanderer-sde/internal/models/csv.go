@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -35,6 +36,22 @@ var CSVHeaders = map[string][]string{
 	},
 }
 
+// SolarSystemSpaceKindHeaders are the opt-in space classification columns
+// appended to mapSolarSystems.
+var SolarSystemSpaceKindHeaders = []string{"spaceKind"}
+
+// SolarSystemSecurityHeaders are the opt-in derived security columns
+// appended to mapSolarSystems.
+var SolarSystemSecurityHeaders = []string{"trueSecurity", "displaySecurity", "securityBand"}
+
+// SolarSystemConnectivityHeaders are the opt-in stargate connectivity
+// columns appended to mapSolarSystems.
+var SolarSystemConnectivityHeaders = []string{"gateReachable", "componentID"}
+
+// SystemJumpEnrichedHeaders are the opt-in region and constellation columns
+// appended to mapSolarSystemJumps.
+var SystemJumpEnrichedHeaders = []string{"fromRegionID", "fromConstellationID", "toConstellationID", "toRegionID"}
+
 // FormatNullableInt64 formats an optional int64 for CSV output.
 // Returns "None" if nil, otherwise the integer value.
 func FormatNullableInt64(v *int64) string {
@@ -71,6 +88,46 @@ func FormatSecurity(v float64) string {
 	return s
 }
 
+// FormatValue formats a dataset value for CSV output.
+func FormatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "None"
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case *int64:
+		return FormatNullableInt64(v)
+	case float64:
+		return FormatFloat(v)
+	case bool:
+		return FormatBool(v)
+	case []int64:
+		ids := make([]string, len(v))
+		for i, id := range v {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		return strings.Join(ids, ";")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// CSVRows converts a Dataset's rows to CSV rows.
+func (d *Dataset) CSVRows() [][]string {
+	rows := make([][]string, len(d.Rows))
+	for i, row := range d.Rows {
+		rows[i] = make([]string, len(row))
+		for j, v := range row {
+			rows[i][j] = FormatValue(v)
+		}
+	}
+	return rows
+}
+
 // Int64PtrNonZero returns a pointer to an int64 value, or nil if the value is 0.
 func Int64PtrNonZero(v int64) *int64 {
 	if v == 0 {
@@ -96,6 +153,26 @@ func (s *SolarSystem) ToCSVRow() []string {
 	}
 }
 
+// SecurityCSVColumns returns the derived security columns of a SolarSystem,
+// matching SolarSystemSecurityHeaders.
+func (s *SolarSystem) SecurityCSVColumns() []string {
+	trueSecurity := "None"
+	if s.TrueSecurity != nil {
+		trueSecurity = FormatSecurity(*s.TrueSecurity)
+	}
+	return []string{trueSecurity, s.DisplaySecurity, s.SecurityBand}
+}
+
+// ConnectivityCSVColumns returns the stargate connectivity columns of a
+// SolarSystem, matching SolarSystemConnectivityHeaders.
+func (s *SolarSystem) ConnectivityCSVColumns() []string {
+	reachable := "None"
+	if s.GateReachable != nil {
+		reachable = FormatBool(*s.GateReachable)
+	}
+	return []string{reachable, FormatNullableInt64(s.ComponentID)}
+}
+
 // ToCSVRow converts a Region to a CSV row with only fields Wanderer uses.
 func (r *Region) ToCSVRow() []string {
 	return []string{
@@ -149,6 +226,17 @@ func (j *SystemJump) ToCSVRow() []string {
 	}
 }
 
+// EnrichedCSVColumns returns the region and constellation columns of a
+// SystemJump, matching SystemJumpEnrichedHeaders.
+func (j *SystemJump) EnrichedCSVColumns() []string {
+	return []string{
+		strconv.FormatInt(j.FromRegionID, 10),
+		strconv.FormatInt(j.FromConstellationID, 10),
+		strconv.FormatInt(j.ToConstellationID, 10),
+		strconv.FormatInt(j.ToRegionID, 10),
+	}
+}
+
 // ToCSVRow converts an NPCStation to a CSV row.
 func (s *NPCStation) ToCSVRow() []string {
 	return []string{
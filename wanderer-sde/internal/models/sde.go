@@ -177,5 +177,56 @@ type SDENPCStation struct {
 type SDENPCCorporation struct {
 	Name      map[string]string `yaml:"name"`
 	StationID int64             `yaml:"stationID,omitempty"`
+	FactionID int64             `yaml:"factionID,omitempty"`
 	Deleted   bool              `yaml:"deleted,omitempty"`
 }
+
+// SDEStationOperation represents a station operation from
+// stationOperations.yaml. A station's operation decides its services.
+type SDEStationOperation struct {
+	OperationName map[string]string `yaml:"operationName,omitempty"`
+	Services      []int64           `yaml:"services,omitempty"`
+}
+
+// SDEStationService represents a station service from stationServices.yaml.
+type SDEStationService struct {
+	ServiceName map[string]string `yaml:"serviceName"`
+}
+
+// SDETypeDogma represents the dogma data for a type from typeDogma.yaml.
+type SDETypeDogma struct {
+	DogmaAttributes []SDEDogmaAttributeValue `yaml:"dogmaAttributes,omitempty"`
+	DogmaEffects    []SDEDogmaEffectRef      `yaml:"dogmaEffects,omitempty"`
+}
+
+// SDEDogmaAttributeValue is a single attribute value attached to a type.
+type SDEDogmaAttributeValue struct {
+	AttributeID int64   `yaml:"attributeID"`
+	Value       float64 `yaml:"value"`
+}
+
+// SDEDogmaEffectRef is a reference to a dogma effect attached to a type.
+type SDEDogmaEffectRef struct {
+	EffectID  int64 `yaml:"effectID"`
+	IsDefault bool  `yaml:"isDefault,omitempty"`
+}
+
+// SDEDogmaAttribute represents a dogma attribute definition from dogmaAttributes.yaml.
+type SDEDogmaAttribute struct {
+	Name         string            `yaml:"name"`
+	DisplayName  map[string]string `yaml:"displayName,omitempty"`
+	HighIsGood   bool              `yaml:"highIsGood,omitempty"`
+	Published    bool              `yaml:"published,omitempty"`
+	DefaultValue float64           `yaml:"defaultValue,omitempty"`
+	UnitID       int64             `yaml:"unitID,omitempty"`
+}
+
+// Attribute returns the value of the given dogma attribute and whether it is set.
+func (d SDETypeDogma) Attribute(attributeID int64) (float64, bool) {
+	for _, attr := range d.DogmaAttributes {
+		if attr.AttributeID == attributeID {
+			return attr.Value, true
+		}
+	}
+	return 0, false
+}
@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 // SolarSystem represents a solar system in Wanderer's format.
 // Fields match Fuzzwork CSV column order for mapSolarSystems.csv.
 type SolarSystem struct {
@@ -29,6 +31,14 @@ type SolarSystem struct {
 	Radius          float64 `json:"radius"`
 	SunTypeID       *int64  `json:"sunTypeID,omitempty"` // Pointer to allow "None" in CSV
 	SecurityClass   string  `json:"securityClass,omitempty"`
+	SpaceKind       string  `json:"spaceKind,omitempty"` // Derived space classification (highsec, c5, pochven, ...)
+	// Opt-in derived security fields; Security stays the raw SDE value
+	TrueSecurity    *float64 `json:"trueSecurity,omitempty"`    // Security with EVE's display rounding
+	DisplaySecurity string   `json:"displaySecurity,omitempty"` // TrueSecurity as shown in game, e.g. "0.9"
+	SecurityBand    string   `json:"securityBand,omitempty"`    // high, low, null or wormhole
+	// Opt-in stargate connectivity fields
+	GateReachable *bool  `json:"gateReachable,omitempty"` // In the main stargate cluster
+	ComponentID   *int64 `json:"componentID,omitempty"`   // Lowest system ID of its stargate component
 }
 
 // Region represents a region in Wanderer's format.
@@ -138,6 +148,86 @@ type NPCStation struct {
 	TypeID        int64  `json:"typeID"`
 }
 
+// Wormhole represents a wormhole type in Wanderer's wormholes.json format.
+// Mass, lifetime and destination are derived from the SDE; Src, Static and
+// Respawn are hand-curated and come from an optional overlay.
+type Wormhole struct {
+	ID             int64    `json:"id"`
+	Name           string   `json:"name"`
+	Src            []string `json:"src"`
+	Dest           *string  `json:"dest"`
+	Lifetime       string   `json:"lifetime"`
+	TotalMass      int64    `json:"total_mass"`
+	MaxMassPerJump int64    `json:"max_mass_per_jump"`
+	MassRegen      int64    `json:"mass_regen"`
+	Static         bool     `json:"static"`
+	Respawn        []string `json:"respawn,omitempty"`
+}
+
+// ParseWormholes decodes a wormholes.json file. The transformer's overlay
+// and the writer's passthrough check both use it, so they accept the same
+// files.
+func ParseWormholes(content []byte) ([]Wormhole, error) {
+	var wormholes []Wormhole
+	if err := json.Unmarshal(content, &wormholes); err != nil {
+		return nil, err
+	}
+	return wormholes, nil
+}
+
+// SunType represents a star type in Wanderer's sunTypes.json format.
+// SpectralClass is the most common spectral class among stars of this type.
+type SunType struct {
+	TypeID        int64  `json:"typeID"`
+	TypeName      string `json:"typeName"`
+	GroupID       int64  `json:"groupID"`
+	SpectralClass string `json:"spectralClass"`
+}
+
+// SystemEffect describes the wormhole environmental effect active in a solar
+// system. Modifiers come from the system's class-specific effect beacon, so
+// their values are already scaled for the system's wormhole class.
+type SystemEffect struct {
+	SolarSystemID   int64            `json:"solarSystemID"`
+	SolarSystemName string           `json:"solarSystemName"`
+	WormholeClassID int64            `json:"wormholeClassID"`
+	Effect          string           `json:"effect"`
+	BeaconTypeID    int64            `json:"beaconTypeID"`
+	Modifiers       []EffectModifier `json:"modifiers"`
+}
+
+// EffectModifier is a single attribute modifier applied by a system effect.
+type EffectModifier struct {
+	AttributeID int64   `json:"attributeID"`
+	Name        string  `json:"name"`
+	Value       float64 `json:"value"`
+}
+
+// TriglavianSystem represents a Pochven system in Wanderer's
+// triglavianSystems.json format.
+type TriglavianSystem struct {
+	SolarSystemID     int64  `json:"solarSystemID"`
+	SolarSystemName   string `json:"solarSystemName"`
+	ConstellationID   int64  `json:"constellationID"`
+	ConstellationName string `json:"constellationName"`
+}
+
+// ShatteredConstellation represents a constellation of shattered wormhole
+// systems in Wanderer's shatteredConstellations.json format.
+type ShatteredConstellation struct {
+	ConstellationID   int64   `json:"constellationID"`
+	ConstellationName string  `json:"constellationName"`
+	RegionID          int64   `json:"regionID"`
+	SolarSystemIDs    []int64 `json:"solarSystemIDs"`
+}
+
+// TypeSetData holds the types selected by a named type set rule, written to
+// its own invTypes-style file.
+type TypeSetData struct {
+	Name  string
+	Types []InvType
+}
+
 // UniverseData holds all parsed universe data.
 type UniverseData struct {
 	Regions        []Region
@@ -150,9 +240,102 @@ type ConvertedData struct {
 	Universe        *UniverseData
 	InvTypes        []InvType
 	InvGroups       []InvGroup
+	TypeSets        []TypeSetData // Type sets other than ships, one file each
 	WormholeClasses []WormholeClassLocation
 	SystemJumps     []SystemJump
 	NPCStations     []NPCStation
+	Wormholes       []Wormhole // Generated wormholes.json; empty when typeDogma is unavailable
+	SunTypes        []SunType  // Generated sunTypes.json
+	SystemEffects   []SystemEffect
+	// Generated triglavianSystems.json and shatteredConstellations.json
+	TriglavianSystems       []TriglavianSystem
+	ShatteredConstellations []ShatteredConstellation
+	// UnclassifiedSystems lists solar systems with no space kind, for the
+	// validation report. Not written to output.
+	UnclassifiedSystems []int64
+	// WormholeTypeNames lists every wormhole type name in the SDE (e.g. "A009"),
+	// for cross-referencing passthrough files. Not written to output.
+	WormholeTypeNames []string
+	// Datasets are extra tables added by transform stages, one file each.
+	Datasets []Dataset
+	// DistanceMatrix holds the gate jumps between every pair of systems,
+	// written as a binary file. Nil unless the distanceMatrix stage ran.
+	DistanceMatrix *DistanceMatrix
+}
+
+// UnreachableJumps marks a pair of systems with no gate route in a
+// DistanceMatrix. Longer routes are stored as UnreachableJumps - 1.
+const UnreachableJumps = 255
+
+// DistanceMatrix holds the number of gate jumps between every pair of a set
+// of systems, one byte per pair.
+type DistanceMatrix struct {
+	// SystemIDs gives the row and column order.
+	SystemIDs []int64
+	// Jumps is row-major: Jumps[i*len(SystemIDs)+j] is the number of jumps
+	// from SystemIDs[i] to SystemIDs[j].
+	Jumps []uint8
+
+	index map[int64]int
+}
+
+// JumpsBetween returns the number of gate jumps between two systems. It
+// returns false if either system is not in the matrix or no route
+// connects them.
+func (m *DistanceMatrix) JumpsBetween(from, to int64) (int, bool) {
+	if m.index == nil {
+		m.index = make(map[int64]int, len(m.SystemIDs))
+		for i, id := range m.SystemIDs {
+			m.index[id] = i
+		}
+	}
+	i, okFrom := m.index[from]
+	j, okTo := m.index[to]
+	if !okFrom || !okTo {
+		return 0, false
+	}
+	jumps := m.Jumps[i*len(m.SystemIDs)+j]
+	if jumps == UnreachableJumps {
+		return 0, false
+	}
+	return int(jumps), true
+}
+
+// Dataset is a named table added by a transform stage. Writers output it as
+// <Name>.csv or <Name>.json without knowing its contents. Each row holds one
+// value per column; values are strings, integers, floats, booleans, lists
+// of IDs ([]int64, written to CSV separated by semicolons) or nil.
+type Dataset struct {
+	Name      string
+	Columns   []string
+	Keys      []string                // Columns the rows are ordered by; none if ordered otherwise
+	Locations map[string]LocationKind // Columns a subset filters on; none keeps every row
+	Rows      [][]interface{}
+}
+
+// LocationKind is the kind of location ID a dataset column holds. A subset
+// drops rows whose system, constellation or region column is outside it,
+// and removes systems outside it from system lists.
+type LocationKind int
+
+// Location kinds of dataset columns.
+const (
+	LocationSystem LocationKind = iota + 1
+	LocationConstellation
+	LocationRegion
+	LocationSystemList // []int64 of solar system IDs
+)
+
+// AddDataset adds a dataset, replacing any existing dataset with the same
+// name.
+func (c *ConvertedData) AddDataset(dataset Dataset) {
+	for i := range c.Datasets {
+		if c.Datasets[i].Name == dataset.Name {
+			c.Datasets[i] = dataset
+			return
+		}
+	}
+	c.Datasets = append(c.Datasets, dataset)
 }
 
 // ShipTypes returns InvTypes for backward compatibility.
@@ -179,9 +362,49 @@ type ValidationResult struct {
 	NPCStations     int
 	Errors          []string
 	Warnings        []string
+	// Findings holds structured results of checks that report individual
+	// records, such as referential integrity. Their messages are also
+	// summarized in Errors and Warnings according to severity.
+	Findings []Finding
 }
 
 // IsValid returns true if validation found no errors.
 func (v *ValidationResult) IsValid() bool {
 	return len(v.Errors) == 0
 }
+
+// Severity is how serious a validation finding is. Error findings fail the
+// conversion; warnings and info are reported only.
+type Severity string
+
+// Finding severities.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// FindingCode identifies the kind of a validation finding.
+type FindingCode string
+
+// Referential integrity finding codes.
+const (
+	CodeJumpUnknownSystem            FindingCode = "JUMP_UNKNOWN_SYSTEM"
+	CodeJumpAsymmetric               FindingCode = "JUMP_ASYMMETRIC"
+	CodeSystemUnknownConstellation   FindingCode = "SYSTEM_UNKNOWN_CONSTELLATION"
+	CodeSystemRegionMismatch         FindingCode = "SYSTEM_REGION_MISMATCH"
+	CodeConstellationUnknownRegion   FindingCode = "CONSTELLATION_UNKNOWN_REGION"
+	CodeStationUnknownSystem         FindingCode = "STATION_UNKNOWN_SYSTEM"
+	CodeTypeUnknownGroup             FindingCode = "TYPE_UNKNOWN_GROUP"
+	CodeWormholeClassUnknownLocation FindingCode = "WORMHOLE_CLASS_UNKNOWN_LOCATION"
+	CodeDuplicateID                  FindingCode = "DUPLICATE_ID"
+)
+
+// Finding is a single validation result about one record.
+type Finding struct {
+	Code     FindingCode `json:"code"`
+	Severity Severity    `json:"severity"`
+	Table    string      `json:"table"` // Table of the offending record, e.g. "mapSolarSystemJumps"
+	ID       string      `json:"id"`    // Key of the offending record
+	Message  string      `json:"message"`
+}
@@ -0,0 +1,387 @@
+// Package overrides applies local patches to converted data, for fixing SDE
+// data that is wrong or lags behind the live game.
+package overrides
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/pkg/yaml"
+)
+
+// Patch operations.
+const (
+	OpSet    = "set"    // Update fields of an existing record
+	OpAdd    = "add"    // Add a new record
+	OpRemove = "remove" // Remove an existing record
+)
+
+// Patch is a single change to one record of a converted table.
+//
+// ID identifies the record by its table's key (e.g. solarSystemID for
+// solarSystems, locationID for wormholeClasses). System jumps are keyed by
+// "fromSolarSystemID:toSolarSystemID". Fields use the record's JSON field
+// names.
+type Patch struct {
+	Table  string                 `yaml:"table"`
+	ID     string                 `yaml:"id"`
+	Op     string                 `yaml:"op"`
+	Fields map[string]interface{} `yaml:"fields"`
+	Reason string                 `yaml:"reason"`
+	Source string                 `yaml:"-"` // File the patch was loaded from
+}
+
+// AppliedPatch records a patch that changed the converted data.
+type AppliedPatch struct {
+	Source string   `json:"source"`
+	Table  string   `json:"table"`
+	ID     string   `json:"id"`
+	Op     string   `json:"op"`
+	Fields []string `json:"fields,omitempty"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// Load reads every .json, .yaml and .yml file in dir, in name order. Each
+// file holds a list of patches.
+func Load(dir string) ([]Patch, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overrides directory %s: %w", dir, err)
+	}
+
+	var patches []Patch
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+
+		// YAML is a superset of JSON, so one decoder handles both
+		var filePatches []Patch
+		if err := yaml.ParseFile(filepath.Join(dir, entry.Name()), &filePatches); err != nil {
+			return nil, fmt.Errorf("failed to parse override file %s: %w", entry.Name(), err)
+		}
+
+		for i := range filePatches {
+			filePatches[i].Source = entry.Name()
+			if err := filePatches[i].validate(); err != nil {
+				return nil, fmt.Errorf("%s: patch %d: %w", entry.Name(), i, err)
+			}
+		}
+		patches = append(patches, filePatches...)
+	}
+
+	return patches, nil
+}
+
+// validate checks that a patch is well-formed. Whether its target exists is
+// only known when it is applied.
+func (p *Patch) validate() error {
+	if _, ok := tableKeys[p.Table]; !ok {
+		return fmt.Errorf("unknown table %q", p.Table)
+	}
+	if p.ID == "" {
+		return fmt.Errorf("missing id")
+	}
+	switch p.Op {
+	case OpSet, OpAdd:
+		if len(p.Fields) == 0 {
+			return fmt.Errorf("%s requires fields", p.Op)
+		}
+	case OpRemove:
+	default:
+		return fmt.Errorf("unknown op %q (must be set, add or remove)", p.Op)
+	}
+	return nil
+}
+
+// tableKeys maps each patchable table to the JSON fields forming its key.
+var tableKeys = map[string][]string{
+	"regions":         {"regionID"},
+	"constellations":  {"constellationID"},
+	"solarSystems":    {"solarSystemID"},
+	"wormholeClasses": {"locationID"},
+	"invTypes":        {"typeID"},
+	"invGroups":       {"groupID"},
+	"systemJumps":     {"fromSolarSystemID", "toSolarSystemID"},
+	"npcStations":     {"stationID"},
+	"wormholes":       {"id"},
+	"sunTypes":        {"typeID"},
+}
+
+// Apply applies patches to data in order and returns the patches that took
+// effect. Patches whose target does not exist (or, for add, already exists)
+// are skipped with a warning.
+func Apply(data *models.ConvertedData, patches []Patch) ([]AppliedPatch, []string) {
+	var applied []AppliedPatch
+	var warnings []string
+
+	if data.Universe == nil {
+		data.Universe = &models.UniverseData{}
+	}
+
+	// Each table is indexed by key once, when it is first patched
+	tables := make(map[string]patcher)
+	for _, p := range patches {
+		var err error
+		t, ok := tables[p.Table]
+		if !ok {
+			if t, err = openTable(data, p.Table); err == nil {
+				tables[p.Table] = t
+			}
+		}
+		if err == nil {
+			err = t.apply(p)
+		}
+
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %s %s %s: %v", p.Source, p.Op, p.Table, p.ID, err))
+			continue
+		}
+
+		applied = append(applied, AppliedPatch{
+			Source: p.Source,
+			Table:  p.Table,
+			ID:     p.ID,
+			Op:     p.Op,
+			Fields: sortedKeys(p.Fields),
+			Reason: p.Reason,
+		})
+	}
+
+	return applied, warnings
+}
+
+// patcher applies patches to one table.
+type patcher interface {
+	apply(p Patch) error
+}
+
+// openTable indexes the named table of data for patching.
+func openTable(data *models.ConvertedData, name string) (patcher, error) {
+	switch name {
+	case "regions":
+		return newTable(&data.Universe.Regions, name)
+	case "constellations":
+		return newTable(&data.Universe.Constellations, name)
+	case "solarSystems":
+		return newTable(&data.Universe.SolarSystems, name)
+	case "wormholeClasses":
+		return newTable(&data.WormholeClasses, name)
+	case "invTypes":
+		return newTable(&data.InvTypes, name)
+	case "invGroups":
+		return newTable(&data.InvGroups, name)
+	case "systemJumps":
+		return newTable(&data.SystemJumps, name)
+	case "npcStations":
+		return newTable(&data.NPCStations, name)
+	case "wormholes":
+		return newTable(&data.Wormholes, name)
+	case "sunTypes":
+		return newTable(&data.SunTypes, name)
+	default:
+		return nil, fmt.Errorf("unknown table %q", name)
+	}
+}
+
+// table is a slice of records with an index from each key to the position
+// of the first record with that key. Patches keep the index up to date.
+type table[T any] struct {
+	records   *[]T
+	keyFields []string
+	index     map[string]int
+}
+
+// newTable indexes records by the key fields of the named table.
+func newTable[T any](records *[]T, name string) (*table[T], error) {
+	t := &table[T]{
+		records:   records,
+		keyFields: tableKeys[name],
+		index:     make(map[string]int, len(*records)),
+	}
+	for i := range *records {
+		key, err := recordKey(&(*records)[i], t.keyFields)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := t.index[key]; !ok {
+			t.index[key] = i
+		}
+	}
+	return t, nil
+}
+
+// apply applies a single patch to the table.
+func (t *table[T]) apply(p Patch) error {
+	index, found := t.index[p.ID]
+
+	switch p.Op {
+	case OpRemove:
+		if !found {
+			return fmt.Errorf("target not found")
+		}
+		*t.records = append((*t.records)[:index], (*t.records)[index+1:]...)
+		delete(t.index, p.ID)
+		for key, i := range t.index {
+			if i > index {
+				t.index[key] = i - 1
+			}
+		}
+
+	case OpSet:
+		if !found {
+			return fmt.Errorf("target not found")
+		}
+		updated := (*t.records)[index]
+		if err := mergeFields(&updated, p.Fields); err != nil {
+			return err
+		}
+		key, err := recordKey(&updated, t.keyFields)
+		if err != nil {
+			return err
+		}
+		(*t.records)[index] = updated
+		// A set may change the key fields themselves
+		if key != p.ID {
+			delete(t.index, p.ID)
+			if _, ok := t.index[key]; !ok {
+				t.index[key] = index
+			}
+		}
+
+	case OpAdd:
+		if found {
+			return fmt.Errorf("target already exists")
+		}
+		var record T
+		fields, err := keyFieldValues(t.keyFields, p.ID)
+		if err != nil {
+			return err
+		}
+		for name, value := range p.Fields {
+			fields[name] = value
+		}
+		if err := mergeFields(&record, fields); err != nil {
+			return err
+		}
+		if key, err := recordKey(&record, t.keyFields); err != nil || key != p.ID {
+			return fmt.Errorf("fields do not match id")
+		}
+		*t.records = append(*t.records, record)
+		t.index[p.ID] = len(*t.records) - 1
+	}
+
+	return nil
+}
+
+// mergeFields overwrites the named JSON fields of record.
+func mergeFields[T any](record *T, fields map[string]interface{}) error {
+	known := jsonFieldNames(reflect.TypeOf(*record))
+	for name := range fields {
+		if !known[name] {
+			return fmt.Errorf("unknown field %q", name)
+		}
+	}
+
+	current, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+	// Decode numbers as json.Number so large IDs keep their precision
+	decoder := json.NewDecoder(bytes.NewReader(current))
+	decoder.UseNumber()
+	var merged map[string]interface{}
+	if err := decoder.Decode(&merged); err != nil {
+		return fmt.Errorf("failed to decode record: %w", err)
+	}
+	for name, value := range fields {
+		merged[name] = value
+	}
+
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to encode fields: %w", err)
+	}
+	var result T
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		return fmt.Errorf("invalid field value: %w", err)
+	}
+	*record = result
+	return nil
+}
+
+// recordKey returns the key of a record, joining multi-field keys with ":".
+func recordKey[T any](record *T, keyFields []string) (string, error) {
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode record: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return "", fmt.Errorf("failed to decode record: %w", err)
+	}
+
+	parts := make([]string, len(keyFields))
+	for i, name := range keyFields {
+		parts[i] = fmt.Sprint(fields[name])
+	}
+	return strings.Join(parts, ":"), nil
+}
+
+// keyFieldValues splits an ID into the values of its key fields.
+func keyFieldValues(keyFields []string, id string) (map[string]interface{}, error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != len(keyFields) {
+		return nil, fmt.Errorf("id must have the form %s", strings.Join(keyFields, ":"))
+	}
+
+	fields := make(map[string]interface{}, len(keyFields))
+	for i, name := range keyFields {
+		value, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", name, parts[i])
+		}
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+// jsonFieldNames returns the JSON field names of a struct type.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
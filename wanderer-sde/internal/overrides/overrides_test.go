@@ -0,0 +1,222 @@
+package overrides
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func testData() *models.ConvertedData {
+	return &models.ConvertedData{
+		Universe: &models.UniverseData{
+			SolarSystems: []models.SolarSystem{
+				{SolarSystemID: 30000142, SolarSystemName: "Jita", Security: 0.9},
+				{SolarSystemID: 31000005, SolarSystemName: "Thera", Security: -0.99},
+			},
+		},
+		WormholeClasses: []models.WormholeClassLocation{
+			{LocationID: 31000005, WormholeClassID: 3},
+		},
+		SystemJumps: []models.SystemJump{
+			{FromSolarSystemID: 30000142, ToSolarSystemID: 30000144},
+			{FromSolarSystemID: 30000144, ToSolarSystemID: 30000142},
+		},
+		NPCStations: []models.NPCStation{
+			{StationID: 60003760, SolarSystemID: 30000142},
+		},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "overrides_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	files := map[string]string{
+		"01-thera.yaml": `- table: wormholeClasses
+  id: 31000005
+  op: set
+  fields:
+    wormholeClassID: 12
+  reason: Thera is mislabeled as C3
+`,
+		"02-stations.json": `[{"table": "npcStations", "id": "60003760", "op": "remove"}]`,
+		"README.md":        "not a patch file",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	patches, err := Load(tmpDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(patches) != 2 {
+		t.Fatalf("Expected 2 patches, got %d", len(patches))
+	}
+	if patches[0].Source != "01-thera.yaml" || patches[0].ID != "31000005" || patches[0].Op != OpSet {
+		t.Errorf("Unexpected first patch: %+v", patches[0])
+	}
+	if patches[0].Reason != "Thera is mislabeled as C3" {
+		t.Errorf("Expected reason to be loaded, got %q", patches[0].Reason)
+	}
+	if patches[1].Source != "02-stations.json" || patches[1].Table != "npcStations" {
+		t.Errorf("Unexpected second patch: %+v", patches[1])
+	}
+}
+
+func TestLoad_InvalidPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{"unknown table", `[{"table": "stargates", "id": "1", "op": "remove"}]`, "unknown table"},
+		{"unknown op", `[{"table": "solarSystems", "id": "1", "op": "replace"}]`, "unknown op"},
+		{"missing id", `[{"table": "solarSystems", "op": "remove"}]`, "missing id"},
+		{"set without fields", `[{"table": "solarSystems", "id": "1", "op": "set"}]`, "requires fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "overrides_test")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %v", err)
+			}
+			defer func() { _ = os.RemoveAll(tmpDir) }()
+
+			if err := os.WriteFile(filepath.Join(tmpDir, "patch.json"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write patch: %v", err)
+			}
+
+			_, err = Load(tmpDir)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	data := testData()
+	patches := []Patch{
+		{Table: "wormholeClasses", ID: "31000005", Op: OpSet, Fields: map[string]interface{}{"wormholeClassID": 12}, Source: "a.yaml"},
+		{Table: "solarSystems", ID: "30000142", Op: OpSet, Fields: map[string]interface{}{"security": 1.0}, Source: "a.yaml"},
+		{Table: "npcStations", ID: "60000001", Op: OpAdd, Fields: map[string]interface{}{"solarSystemID": 30000142, "ownerName": "Test Corp"}, Source: "b.yaml"},
+		{Table: "systemJumps", ID: "30000142:30000144", Op: OpRemove, Source: "b.yaml"},
+	}
+
+	applied, warnings := Apply(data, patches)
+
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+	if len(applied) != 4 {
+		t.Fatalf("Expected 4 applied patches, got %d", len(applied))
+	}
+
+	if data.WormholeClasses[0].WormholeClassID != 12 {
+		t.Errorf("Expected wormhole class 12, got %d", data.WormholeClasses[0].WormholeClassID)
+	}
+
+	jita := data.Universe.SolarSystems[0]
+	if jita.Security != 1.0 {
+		t.Errorf("Expected security 1.0, got %f", jita.Security)
+	}
+	if jita.SolarSystemName != "Jita" {
+		t.Errorf("Expected unpatched fields to be kept, got name %q", jita.SolarSystemName)
+	}
+
+	if len(data.NPCStations) != 2 {
+		t.Fatalf("Expected 2 stations, got %d", len(data.NPCStations))
+	}
+	added := data.NPCStations[1]
+	if added.StationID != 60000001 || added.SolarSystemID != 30000142 || added.OwnerName != "Test Corp" {
+		t.Errorf("Unexpected added station: %+v", added)
+	}
+
+	if len(data.SystemJumps) != 1 || data.SystemJumps[0].FromSolarSystemID != 30000144 {
+		t.Errorf("Expected only the reverse jump to remain, got %+v", data.SystemJumps)
+	}
+
+	if applied[0].Source != "a.yaml" || applied[0].Fields[0] != "wormholeClassID" {
+		t.Errorf("Unexpected applied patch record: %+v", applied[0])
+	}
+}
+
+func TestApply_Warnings(t *testing.T) {
+	data := testData()
+	patches := []Patch{
+		{Table: "solarSystems", ID: "30000001", Op: OpSet, Fields: map[string]interface{}{"security": 0.5}, Source: "stale.yaml"},
+		{Table: "npcStations", ID: "60000001", Op: OpRemove, Source: "stale.yaml"},
+		{Table: "npcStations", ID: "60003760", Op: OpAdd, Fields: map[string]interface{}{"solarSystemID": 1}, Source: "dup.yaml"},
+		{Table: "solarSystems", ID: "30000142", Op: OpSet, Fields: map[string]interface{}{"sec": 0.5}, Source: "typo.yaml"},
+		{Table: "solarSystems", ID: "30000142", Op: OpSet, Fields: map[string]interface{}{"security": "high"}, Source: "typo.yaml"},
+	}
+
+	applied, warnings := Apply(data, patches)
+
+	if len(applied) != 0 {
+		t.Errorf("Expected no applied patches, got %+v", applied)
+	}
+
+	expected := []string{
+		"stale.yaml: set solarSystems 30000001: target not found",
+		"stale.yaml: remove npcStations 60000001: target not found",
+		"dup.yaml: add npcStations 60003760: target already exists",
+		`typo.yaml: set solarSystems 30000142: unknown field "sec"`,
+		"typo.yaml: set solarSystems 30000142: invalid field value",
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("Expected %d warnings, got %d: %v", len(expected), len(warnings), warnings)
+	}
+	for i, want := range expected {
+		if !strings.HasPrefix(warnings[i], want) {
+			t.Errorf("Warning %d: expected prefix %q, got %q", i, want, warnings[i])
+		}
+	}
+
+	if data.Universe.SolarSystems[0].Security != 0.9 {
+		t.Errorf("Expected failed patches to leave data unchanged, got security %f",
+			data.Universe.SolarSystems[0].Security)
+	}
+}
+
+// TestApply_Sequence checks that later patches to a table find records
+// moved, renamed or added by earlier ones.
+func TestApply_Sequence(t *testing.T) {
+	data := testData()
+	patches := []Patch{
+		{Table: "solarSystems", ID: "30000142", Op: OpRemove},
+		{Table: "solarSystems", ID: "31000005", Op: OpSet, Fields: map[string]interface{}{"solarSystemID": 31000006}},
+		{Table: "solarSystems", ID: "31000005", Op: OpSet, Fields: map[string]interface{}{"security": -1.0}},
+		{Table: "solarSystems", ID: "31000006", Op: OpSet, Fields: map[string]interface{}{"solarSystemName": "Thera II"}},
+		{Table: "solarSystems", ID: "30000142", Op: OpAdd, Fields: map[string]interface{}{"solarSystemName": "Jita"}},
+		{Table: "solarSystems", ID: "30000142", Op: OpSet, Fields: map[string]interface{}{"security": 0.9}},
+	}
+
+	applied, warnings := Apply(data, patches)
+
+	if len(applied) != 5 || len(warnings) != 1 || !strings.Contains(warnings[0], "set solarSystems 31000005: target not found") {
+		t.Fatalf("Expected only the patch to the old ID to fail, got %d applied and warnings %v", len(applied), warnings)
+	}
+
+	systems := data.Universe.SolarSystems
+	if len(systems) != 2 {
+		t.Fatalf("Expected 2 systems, got %+v", systems)
+	}
+	if systems[0].SolarSystemID != 31000006 || systems[0].SolarSystemName != "Thera II" || systems[0].Security != -0.99 {
+		t.Errorf("Unexpected renamed system: %+v", systems[0])
+	}
+	if systems[1].SolarSystemID != 30000142 || systems[1].Security != 0.9 {
+		t.Errorf("Unexpected re-added system: %+v", systems[1])
+	}
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/guarzo/wanderer-sde/internal/models"
@@ -8,10 +9,10 @@ import (
 )
 
 // ParseCategories parses the categories.yaml file.
-func (p *Parser) ParseCategories() (map[int64]models.SDECategory, error) {
+func (p *Parser) ParseCategories(ctx context.Context) (map[int64]models.SDECategory, error) {
 	path := p.filePath("categories.yaml")
 
-	categories, err := yaml.ParseFileMap[int64, models.SDECategory](path)
+	categories, err := yaml.ParseFileMapContext[int64, models.SDECategory](ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse categories file: %w", err)
 	}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/pkg/yaml"
+)
+
+// ParseTypeDogma parses the typeDogma.yaml file.
+func (p *Parser) ParseTypeDogma(ctx context.Context) (map[int64]models.SDETypeDogma, error) {
+	path := p.filePath("typeDogma.yaml")
+
+	dogma, err := yaml.ParseFileMapContext[int64, models.SDETypeDogma](ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse type dogma file: %w", err)
+	}
+
+	return dogma, nil
+}
+
+// ParseDogmaAttributes parses the dogmaAttributes.yaml file.
+func (p *Parser) ParseDogmaAttributes(ctx context.Context) (map[int64]models.SDEDogmaAttribute, error) {
+	path := p.filePath("dogmaAttributes.yaml")
+
+	attributes, err := yaml.ParseFileMapContext[int64, models.SDEDogmaAttribute](ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dogma attributes file: %w", err)
+	}
+
+	return attributes, nil
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+)
+
+func TestParser_ParseTypeDogma(t *testing.T) {
+	tmpDir := createTestSDE(t)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	dogmaYAML := `30583:
+  dogmaAttributes:
+  - attributeID: 1381
+    value: 13.0
+  - attributeID: 1383
+    value: 500000000.0
+  dogmaEffects:
+  - effectID: 6036
+    isDefault: false
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "typeDogma.yaml"), []byte(dogmaYAML), 0644); err != nil {
+		t.Fatalf("failed to create typeDogma.yaml: %v", err)
+	}
+
+	cfg := &config.Config{Verbose: false}
+	p := New(cfg, tmpDir, nil)
+
+	dogma, err := p.ParseTypeDogma(context.Background())
+	if err != nil {
+		t.Fatalf("ParseTypeDogma failed: %v", err)
+	}
+
+	entry, ok := dogma[30583]
+	if !ok {
+		t.Fatal("Expected dogma for type 30583")
+	}
+	if v, ok := entry.Attribute(1383); !ok || v != 500000000 {
+		t.Errorf("Expected attribute 1383 = 500000000, got %v (set=%v)", v, ok)
+	}
+	if _, ok := entry.Attribute(9999); ok {
+		t.Error("Expected missing attribute to report not set")
+	}
+	if len(entry.DogmaEffects) != 1 || entry.DogmaEffects[0].EffectID != 6036 {
+		t.Errorf("Unexpected dogma effects: %+v", entry.DogmaEffects)
+	}
+
+	// ParseAll picks up the optional file
+	result, err := p.ParseAll(context.Background())
+	if err != nil {
+		t.Fatalf("ParseAll failed: %v", err)
+	}
+	if len(result.TypeDogma) != 1 {
+		t.Errorf("Expected 1 type dogma entry from ParseAll, got %d", len(result.TypeDogma))
+	}
+}
+
+func TestParser_ParseAllWithoutTypeDogma(t *testing.T) {
+	tmpDir := createTestSDE(t)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	cfg := &config.Config{Verbose: false}
+	p := New(cfg, tmpDir, nil)
+
+	result, err := p.ParseAll(context.Background())
+	if err != nil {
+		t.Fatalf("ParseAll failed without optional typeDogma.yaml: %v", err)
+	}
+	if result.TypeDogma != nil {
+		t.Errorf("Expected nil TypeDogma when file is missing, got %d entries", len(result.TypeDogma))
+	}
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/guarzo/wanderer-sde/internal/models"
@@ -8,10 +9,10 @@ import (
 )
 
 // ParseGroups parses the groups.yaml file.
-func (p *Parser) ParseGroups() (map[int64]models.SDEGroup, error) {
+func (p *Parser) ParseGroups(ctx context.Context) (map[int64]models.SDEGroup, error) {
 	path := p.filePath("groups.yaml")
 
-	groups, err := yaml.ParseFileMap[int64, models.SDEGroup](path)
+	groups, err := yaml.ParseFileMapContext[int64, models.SDEGroup](ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse groups file: %w", err)
 	}
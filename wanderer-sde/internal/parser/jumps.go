@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -24,10 +25,10 @@ type SDEMapStargate struct {
 // ParseStargates parses the mapStargates.yaml file and extracts system jumps.
 // Both directions of each stargate connection are included (A→B and B→A)
 // to match Fuzzwork CSV format.
-func (p *Parser) ParseStargates() ([]models.SystemJump, error) {
+func (p *Parser) ParseStargates(ctx context.Context) ([]models.SystemJump, error) {
 	path := p.filePath("mapStargates.yaml")
 
-	rawStargates, err := yaml.ParseFileMap[int64, SDEMapStargate](path)
+	rawStargates, err := yaml.ParseFileMapContext[int64, SDEMapStargate](ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse stargates file: %w", err)
 	}
@@ -2,23 +2,30 @@
 package parser
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"os"
 	"path/filepath"
 
 	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/logging"
 	"github.com/guarzo/wanderer-sde/internal/models"
 )
 
 // Parser orchestrates parsing of all SDE files.
 type Parser struct {
 	config  *config.Config
+	logger  *slog.Logger
 	sdePath string
 }
 
-// New creates a new Parser with the given configuration.
-func New(cfg *config.Config, sdePath string) *Parser {
+// New creates a new Parser with the given configuration. A nil logger
+// discards log output.
+func New(cfg *config.Config, sdePath string, logger *slog.Logger) *Parser {
 	return &Parser{
 		config:  cfg,
+		logger:  logging.OrDiscard(logger),
 		sdePath: sdePath,
 	}
 }
@@ -33,144 +40,191 @@ type ParseResult struct {
 	Categories      map[int64]models.SDECategory
 	WormholeClasses []models.WormholeClassLocation
 	SystemJumps     []models.SystemJump
-	NPCStations     map[int64]models.SDENPCStation
+	NPCStations     map[int64]models.SDENPCStation // Blue loot buyer stations only
+	AllNPCStations  map[int64]models.SDENPCStation
 	NPCCorporations map[int64]models.SDENPCCorporation
+	Operations      map[int64]models.SDEStationOperation
+	Services        map[int64]models.SDEStationService
+	TypeDogma       map[int64]models.SDETypeDogma
+	Stars           map[int64]SDEMapStar
+	SecondarySuns   map[int64]SDEMapSecondarySun
+	DogmaAttributes map[int64]models.SDEDogmaAttribute
 }
 
-// ParseAll parses all SDE files and returns the combined result.
-func (p *Parser) ParseAll() (*ParseResult, error) {
+// ParseAll parses all SDE files and returns the combined result. It stops
+// with the context's error when ctx is cancelled, including part-way
+// through a file.
+func (p *Parser) ParseAll(ctx context.Context) (*ParseResult, error) {
 	result := &ParseResult{}
 
-	if p.config.Verbose {
-		fmt.Println("Parsing SDE files...")
-	}
+	p.logger.Debug("parsing SDE files", "path", p.sdePath)
 
 	// Parse categories first (needed for filtering ships)
-	if p.config.Verbose {
-		fmt.Println("  Parsing categories...")
-	}
-	categories, err := p.ParseCategories()
+	p.logger.Debug("parsing", "table", "categories")
+	categories, err := p.ParseCategories(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse categories: %w", err)
 	}
 	result.Categories = categories
 
 	// Parse groups (needed for filtering ships)
-	if p.config.Verbose {
-		fmt.Println("  Parsing groups...")
-	}
-	groups, err := p.ParseGroups()
+	p.logger.Debug("parsing", "table", "groups")
+	groups, err := p.ParseGroups(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse groups: %w", err)
 	}
 	result.Groups = groups
 
 	// Parse types
-	if p.config.Verbose {
-		fmt.Println("  Parsing types...")
-	}
-	types, err := p.ParseTypes()
+	p.logger.Debug("parsing", "table", "types")
+	types, err := p.ParseTypes(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse types: %w", err)
 	}
 	result.Types = types
 
-	// Parse regions
-	if p.config.Verbose {
-		fmt.Println("  Parsing regions...")
+	// Parse type dogma (optional, needed for generated wormhole data)
+	if p.hasFile("typeDogma.yaml") {
+		p.logger.Debug("parsing", "table", "type dogma")
+		typeDogma, err := p.ParseTypeDogma(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse type dogma: %w", err)
+		}
+		result.TypeDogma = typeDogma
+	} else {
+		p.logger.Debug("skipping optional file", "file", "typeDogma.yaml")
+	}
+
+	// Parse dogma attribute definitions (optional, names for effect modifiers)
+	if p.hasFile("dogmaAttributes.yaml") {
+		p.logger.Debug("parsing", "table", "dogma attributes")
+		dogmaAttributes, err := p.ParseDogmaAttributes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dogma attributes: %w", err)
+		}
+		result.DogmaAttributes = dogmaAttributes
 	}
-	regions, err := p.ParseRegions()
+
+	// Parse regions
+	p.logger.Debug("parsing", "table", "regions")
+	regions, err := p.ParseRegions(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse regions: %w", err)
 	}
 	result.Regions = regions
 
 	// Parse constellations
-	if p.config.Verbose {
-		fmt.Println("  Parsing constellations...")
-	}
-	constellations, err := p.ParseConstellations()
+	p.logger.Debug("parsing", "table", "constellations")
+	constellations, err := p.ParseConstellations(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse constellations: %w", err)
 	}
 	result.Constellations = constellations
 
 	// Parse stars (needed for solar system sun type resolution)
-	if p.config.Verbose {
-		fmt.Println("  Parsing stars...")
-	}
-	starTypeMap, err := p.ParseStars()
+	p.logger.Debug("parsing", "table", "stars")
+	stars, err := p.ParseStarDetails(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse stars: %w", err)
 	}
+	result.Stars = stars
+	starTypeMap := StarTypeMap(stars)
 
 	// Parse solar systems with star type lookup
-	if p.config.Verbose {
-		fmt.Println("  Parsing solar systems...")
-	}
-	systems, err := p.ParseSolarSystems(starTypeMap)
+	p.logger.Debug("parsing", "table", "solar systems")
+	systems, err := p.ParseSolarSystems(ctx, starTypeMap)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse solar systems: %w", err)
 	}
 	result.SolarSystems = systems
 
-	// Parse stargates (system jumps)
-	if p.config.Verbose {
-		fmt.Println("  Parsing stargates...")
+	// Parse secondary suns (optional, wormhole environmental effects)
+	if p.hasFile("mapSecondarySuns.yaml") {
+		p.logger.Debug("parsing", "table", "secondary suns")
+		secondarySuns, err := p.ParseSecondarySuns(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse secondary suns: %w", err)
+		}
+		result.SecondarySuns = secondarySuns
+	} else {
+		p.logger.Debug("skipping optional file", "file", "mapSecondarySuns.yaml")
 	}
-	jumps, err := p.ParseStargates()
+
+	// Parse stargates (system jumps)
+	p.logger.Debug("parsing", "table", "stargates")
+	jumps, err := p.ParseStargates(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse stargates: %w", err)
 	}
 	result.SystemJumps = jumps
 
 	// Extract wormhole classes from regions, constellations, and systems
-	if p.config.Verbose {
-		fmt.Println("  Extracting wormhole classes...")
-	}
-	wormholeClasses, err := p.ExtractAllWormholeClasses()
+	p.logger.Debug("parsing", "table", "wormhole classes")
+	wormholeClasses, err := p.ExtractAllWormholeClasses(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract wormhole classes: %w", err)
 	}
 	result.WormholeClasses = wormholeClasses
 
 	// Parse NPC stations (for blue loot buyer locations)
-	if p.config.Verbose {
-		fmt.Println("  Parsing NPC stations...")
-	}
-	npcStations, err := p.ParseNPCStations()
+	p.logger.Debug("parsing", "table", "NPC stations")
+	npcStations, err := p.ParseNPCStations(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse NPC stations: %w", err)
 	}
-	// Filter to only blue loot buyer stations
+	// Keep all stations for nearest station lookups; npcStations only
+	// lists blue loot buyers
+	result.AllNPCStations = npcStations
 	result.NPCStations = FilterBlueLootStations(npcStations)
 
-	// Parse NPC corporations (for name lookup)
-	if p.config.Verbose {
-		fmt.Println("  Parsing NPC corporations...")
+	// Parse station operations and services (optional, for nearest station services)
+	if p.hasFile("stationOperations.yaml") {
+		p.logger.Debug("parsing", "table", "station operations")
+		operations, err := p.ParseStationOperations(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse station operations: %w", err)
+		}
+		result.Operations = operations
+	} else {
+		p.logger.Debug("skipping optional file", "file", "stationOperations.yaml")
+	}
+	if p.hasFile("stationServices.yaml") {
+		p.logger.Debug("parsing", "table", "station services")
+		services, err := p.ParseStationServices(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse station services: %w", err)
+		}
+		result.Services = services
 	}
-	npcCorps, err := p.ParseNPCCorporations()
+
+	// Parse NPC corporations (for name lookup)
+	p.logger.Debug("parsing", "table", "NPC corporations")
+	npcCorps, err := p.ParseNPCCorporations(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse NPC corporations: %w", err)
 	}
 	result.NPCCorporations = npcCorps
 
-	if p.config.Verbose {
-		fmt.Printf("Parsing complete:\n")
-		fmt.Printf("  Regions:        %d\n", len(result.Regions))
-		fmt.Printf("  Constellations: %d\n", len(result.Constellations))
-		fmt.Printf("  Solar Systems:  %d\n", len(result.SolarSystems))
-		fmt.Printf("  Types:          %d\n", len(result.Types))
-		fmt.Printf("  Groups:         %d\n", len(result.Groups))
-		fmt.Printf("  Categories:     %d\n", len(result.Categories))
-		fmt.Printf("  Wormhole Classes: %d\n", len(result.WormholeClasses))
-		fmt.Printf("  System Jumps:   %d\n", len(result.SystemJumps))
-		fmt.Printf("  NPC Stations:   %d\n", len(result.NPCStations))
-	}
+	p.logger.Debug("parsing complete",
+		"regions", len(result.Regions),
+		"constellations", len(result.Constellations),
+		"solarSystems", len(result.SolarSystems),
+		"types", len(result.Types),
+		"groups", len(result.Groups),
+		"categories", len(result.Categories),
+		"wormholeClasses", len(result.WormholeClasses),
+		"systemJumps", len(result.SystemJumps),
+		"npcStations", len(result.NPCStations))
 
 	return result, nil
 }
 
+// hasFile reports whether an optional SDE file is present.
+func (p *Parser) hasFile(filename string) bool {
+	_, err := os.Stat(p.filePath(filename))
+	return err == nil
+}
+
 // filePath returns the full path to an SDE file.
 func (p *Parser) filePath(filename string) string {
 	return filepath.Join(p.sdePath, filename)
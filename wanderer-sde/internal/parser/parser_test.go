@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -303,9 +304,9 @@ func TestParser_ParseRegions(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	regions, err := p.ParseRegions()
+	regions, err := p.ParseRegions(context.Background())
 	if err != nil {
 		t.Fatalf("ParseRegions failed: %v", err)
 	}
@@ -335,9 +336,9 @@ func TestParser_ParseConstellations(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	constellations, err := p.ParseConstellations()
+	constellations, err := p.ParseConstellations(context.Background())
 	if err != nil {
 		t.Fatalf("ParseConstellations failed: %v", err)
 	}
@@ -366,15 +367,15 @@ func TestParser_ParseSolarSystems(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
 	// Parse stars first to get the type map
-	starTypeMap, err := p.ParseStars()
+	starTypeMap, err := p.ParseStars(context.Background())
 	if err != nil {
 		t.Fatalf("ParseStars failed: %v", err)
 	}
 
-	systems, err := p.ParseSolarSystems(starTypeMap)
+	systems, err := p.ParseSolarSystems(context.Background(), starTypeMap)
 	if err != nil {
 		t.Fatalf("ParseSolarSystems failed: %v", err)
 	}
@@ -435,9 +436,9 @@ func TestParser_ParseStars(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	starTypeMap, err := p.ParseStars()
+	starTypeMap, err := p.ParseStars(context.Background())
 	if err != nil {
 		t.Fatalf("ParseStars failed: %v", err)
 	}
@@ -469,15 +470,45 @@ func TestParser_ParseStars(t *testing.T) {
 	}
 }
 
+func TestParser_ParseStarDetails(t *testing.T) {
+	tmpDir := createTestSDE(t)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	cfg := &config.Config{Verbose: false}
+	p := New(cfg, tmpDir, nil)
+
+	stars, err := p.ParseStarDetails(context.Background())
+	if err != nil {
+		t.Fatalf("ParseStarDetails failed: %v", err)
+	}
+
+	if len(stars) != 3 {
+		t.Errorf("Expected 3 stars, got %d", len(stars))
+	}
+
+	jitaStar, ok := stars[40000006]
+	if !ok {
+		t.Fatal("Star 40000006 not found")
+	}
+	if jitaStar.SolarSystemID != 30000142 || jitaStar.TypeID != 3796 {
+		t.Errorf("Unexpected star 40000006: %+v", jitaStar)
+	}
+
+	typeMap := StarTypeMap(stars)
+	if typeMap[40000006] != 3796 {
+		t.Errorf("Expected StarTypeMap to map 40000006 -> 3796, got %d", typeMap[40000006])
+	}
+}
+
 func TestParser_ParseSolarSystemsWithNilStarMap(t *testing.T) {
 	tmpDir := createTestSDE(t)
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
 	// Parse with nil star map - should still work but sunTypeID will be nil
-	systems, err := p.ParseSolarSystems(nil)
+	systems, err := p.ParseSolarSystems(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("ParseSolarSystems failed: %v", err)
 	}
@@ -503,9 +534,9 @@ func TestParser_ParseStargates(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	jumps, err := p.ParseStargates()
+	jumps, err := p.ParseStargates(context.Background())
 	if err != nil {
 		t.Fatalf("ParseStargates failed: %v", err)
 	}
@@ -541,9 +572,9 @@ func TestParser_ParseTypes(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	types, err := p.ParseTypes()
+	types, err := p.ParseTypes(context.Background())
 	if err != nil {
 		t.Fatalf("ParseTypes failed: %v", err)
 	}
@@ -576,9 +607,9 @@ func TestParser_ParseGroups(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	groups, err := p.ParseGroups()
+	groups, err := p.ParseGroups(context.Background())
 	if err != nil {
 		t.Fatalf("ParseGroups failed: %v", err)
 	}
@@ -607,9 +638,9 @@ func TestParser_ParseCategories(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	categories, err := p.ParseCategories()
+	categories, err := p.ParseCategories(context.Background())
 	if err != nil {
 		t.Fatalf("ParseCategories failed: %v", err)
 	}
@@ -634,9 +665,9 @@ func TestParser_ParseAll(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	result, err := p.ParseAll()
+	result, err := p.ParseAll(context.Background())
 	if err != nil {
 		t.Fatalf("ParseAll failed: %v", err)
 	}
@@ -673,10 +704,10 @@ func TestParser_MissingFile(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
 	// Try to parse non-existent file
-	_, err = p.ParseRegions()
+	_, err = p.ParseRegions(context.Background())
 	if err == nil {
 		t.Error("Expected error when parsing missing file")
 	}
@@ -696,9 +727,9 @@ func TestParser_MalformedYAML(t *testing.T) {
 	}
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	_, err = p.ParseRegions()
+	_, err = p.ParseRegions(context.Background())
 	if err == nil {
 		t.Error("Expected error when parsing malformed YAML")
 	}
@@ -721,9 +752,9 @@ func TestParser_EmptyName(t *testing.T) {
 	}
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	regions, err := p.ParseRegions()
+	regions, err := p.ParseRegions(context.Background())
 	if err != nil {
 		t.Fatalf("ParseRegions failed: %v", err)
 	}
@@ -744,9 +775,9 @@ func TestParser_RegionCoordinates(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	regions, err := p.ParseRegions()
+	regions, err := p.ParseRegions(context.Background())
 	if err != nil {
 		t.Fatalf("ParseRegions failed: %v", err)
 	}
@@ -804,9 +835,9 @@ func TestParser_ConstellationCoordinates(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	constellations, err := p.ParseConstellations()
+	constellations, err := p.ParseConstellations(context.Background())
 	if err != nil {
 		t.Fatalf("ParseConstellations failed: %v", err)
 	}
@@ -864,15 +895,15 @@ func TestParser_SolarSystemCSVFields(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
 	// Parse stars first
-	starTypeMap, err := p.ParseStars()
+	starTypeMap, err := p.ParseStars(context.Background())
 	if err != nil {
 		t.Fatalf("ParseStars failed: %v", err)
 	}
 
-	systems, err := p.ParseSolarSystems(starTypeMap)
+	systems, err := p.ParseSolarSystems(context.Background(), starTypeMap)
 	if err != nil {
 		t.Fatalf("ParseSolarSystems failed: %v", err)
 	}
@@ -1022,9 +1053,9 @@ func TestParser_TypeCSVFields(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	types, err := p.ParseTypes()
+	types, err := p.ParseTypes(context.Background())
 	if err != nil {
 		t.Fatalf("ParseTypes failed: %v", err)
 	}
@@ -1085,9 +1116,9 @@ func TestParser_GroupCSVFields(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	groups, err := p.ParseGroups()
+	groups, err := p.ParseGroups(context.Background())
 	if err != nil {
 		t.Fatalf("ParseGroups failed: %v", err)
 	}
@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/pkg/yaml"
+)
+
+// SDEMapSecondarySun represents a secondary sun in the flat SDE format.
+// Wormhole systems with an environmental effect (Pulsar, Magnetar, etc.)
+// have one, and its effect beacon type carries the effect's modifiers.
+type SDEMapSecondarySun struct {
+	SolarSystemID      int64               `yaml:"solarSystemID"`
+	TypeID             int64               `yaml:"typeID"`
+	EffectBeaconTypeID int64               `yaml:"effectBeaconTypeID"`
+	Position           *models.SDEPosition `yaml:"position,omitempty"`
+}
+
+// ParseSecondarySuns parses the mapSecondarySuns.yaml file.
+func (p *Parser) ParseSecondarySuns(ctx context.Context) (map[int64]SDEMapSecondarySun, error) {
+	path := p.filePath("mapSecondarySuns.yaml")
+
+	suns, err := yaml.ParseFileMapContext[int64, SDEMapSecondarySun](ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse secondary suns file: %w", err)
+	}
+
+	return suns, nil
+}
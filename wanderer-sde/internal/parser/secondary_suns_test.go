@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+)
+
+func TestParser_ParseSecondarySuns(t *testing.T) {
+	tmpDir := createTestSDE(t)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	secondarySunsYAML := `40000101:
+  solarSystemID: 31000001
+  typeID: 45031
+  effectBeaconTypeID: 30844
+  position:
+    x: 1.0
+    y: 2.0
+    z: 3.0
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "mapSecondarySuns.yaml"), []byte(secondarySunsYAML), 0644); err != nil {
+		t.Fatalf("failed to create mapSecondarySuns.yaml: %v", err)
+	}
+
+	cfg := &config.Config{Verbose: false}
+	p := New(cfg, tmpDir, nil)
+
+	suns, err := p.ParseSecondarySuns(context.Background())
+	if err != nil {
+		t.Fatalf("ParseSecondarySuns failed: %v", err)
+	}
+
+	sun, ok := suns[40000101]
+	if !ok {
+		t.Fatal("Secondary sun 40000101 not found")
+	}
+	if sun.SolarSystemID != 31000001 || sun.EffectBeaconTypeID != 30844 || sun.TypeID != 45031 {
+		t.Errorf("Unexpected secondary sun: %+v", sun)
+	}
+	if sun.Position == nil || sun.Position.Z != 3.0 {
+		t.Errorf("Expected position to be parsed, got %+v", sun.Position)
+	}
+
+	result, err := p.ParseAll(context.Background())
+	if err != nil {
+		t.Fatalf("ParseAll failed: %v", err)
+	}
+	if len(result.SecondarySuns) != 1 {
+		t.Errorf("Expected 1 secondary sun from ParseAll, got %d", len(result.SecondarySuns))
+	}
+}
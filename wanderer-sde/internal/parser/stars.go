@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/guarzo/wanderer-sde/pkg/yaml"
@@ -23,21 +24,36 @@ type SDEStarStats struct {
 	Temperature   float64 `yaml:"temperature,omitempty"`
 }
 
-// ParseStars parses mapStars.yaml and returns a map of starID -> typeID.
-func (p *Parser) ParseStars() (map[int64]int64, error) {
+// ParseStarDetails parses mapStars.yaml and returns all star data keyed by starID.
+func (p *Parser) ParseStarDetails(ctx context.Context) (map[int64]SDEMapStar, error) {
 	path := p.filePath("mapStars.yaml")
 
-	rawStars, err := yaml.ParseFileMap[int64, SDEMapStar](path)
+	rawStars, err := yaml.ParseFileMapContext[int64, SDEMapStar](ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse stars file: %w", err)
 	}
 
-	starTypeMap := make(map[int64]int64, len(rawStars))
-	for starID, data := range rawStars {
+	return rawStars, nil
+}
+
+// ParseStars parses mapStars.yaml and returns a map of starID -> typeID.
+func (p *Parser) ParseStars(ctx context.Context) (map[int64]int64, error) {
+	rawStars, err := p.ParseStarDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return StarTypeMap(rawStars), nil
+}
+
+// StarTypeMap builds a starID -> typeID map from parsed star data.
+func StarTypeMap(stars map[int64]SDEMapStar) map[int64]int64 {
+	starTypeMap := make(map[int64]int64, len(stars))
+	for starID, data := range stars {
 		if data.TypeID != 0 {
 			starTypeMap[starID] = data.TypeID
 		}
 	}
 
-	return starTypeMap, nil
+	return starTypeMap
 }
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/guarzo/wanderer-sde/internal/models"
@@ -19,10 +20,10 @@ func IsBlueLootBuyer(corpID int64) bool {
 }
 
 // ParseNPCStations parses the npcStations.yaml file.
-func (p *Parser) ParseNPCStations() (map[int64]models.SDENPCStation, error) {
+func (p *Parser) ParseNPCStations(ctx context.Context) (map[int64]models.SDENPCStation, error) {
 	path := p.filePath("npcStations.yaml")
 
-	stations, err := yaml.ParseFileMap[int64, models.SDENPCStation](path)
+	stations, err := yaml.ParseFileMapContext[int64, models.SDENPCStation](ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse NPC stations file: %w", err)
 	}
@@ -31,10 +32,10 @@ func (p *Parser) ParseNPCStations() (map[int64]models.SDENPCStation, error) {
 }
 
 // ParseNPCCorporations parses the npcCorporations.yaml file.
-func (p *Parser) ParseNPCCorporations() (map[int64]models.SDENPCCorporation, error) {
+func (p *Parser) ParseNPCCorporations(ctx context.Context) (map[int64]models.SDENPCCorporation, error) {
 	path := p.filePath("npcCorporations.yaml")
 
-	corps, err := yaml.ParseFileMap[int64, models.SDENPCCorporation](path)
+	corps, err := yaml.ParseFileMapContext[int64, models.SDENPCCorporation](ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse NPC corporations file: %w", err)
 	}
@@ -42,6 +43,30 @@ func (p *Parser) ParseNPCCorporations() (map[int64]models.SDENPCCorporation, err
 	return corps, nil
 }
 
+// ParseStationOperations parses the stationOperations.yaml file.
+func (p *Parser) ParseStationOperations(ctx context.Context) (map[int64]models.SDEStationOperation, error) {
+	path := p.filePath("stationOperations.yaml")
+
+	operations, err := yaml.ParseFileMapContext[int64, models.SDEStationOperation](ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse station operations file: %w", err)
+	}
+
+	return operations, nil
+}
+
+// ParseStationServices parses the stationServices.yaml file.
+func (p *Parser) ParseStationServices(ctx context.Context) (map[int64]models.SDEStationService, error) {
+	path := p.filePath("stationServices.yaml")
+
+	services, err := yaml.ParseFileMapContext[int64, models.SDEStationService](ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse station services file: %w", err)
+	}
+
+	return services, nil
+}
+
 // FilterBlueLootStations filters stations to only those owned by blue loot buyers.
 func FilterBlueLootStations(stations map[int64]models.SDENPCStation) map[int64]models.SDENPCStation {
 	filtered := make(map[int64]models.SDENPCStation)
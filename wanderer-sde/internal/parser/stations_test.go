@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+)
+
+func TestParser_ParseStationOperations(t *testing.T) {
+	tmpDir := createTestSDE(t)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	files := map[string]string{
+		"stationOperations.yaml": `14:
+  operationName:
+    en: "Law School"
+  services: [5, 16]
+26:
+  operationName:
+    en: "Warehouse"
+  services: [16]
+`,
+		"stationServices.yaml": `5:
+  serviceName:
+    en: "Bounty Missions"
+16:
+  serviceName:
+    en: "Cloning"
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	cfg := &config.Config{Verbose: false}
+	p := New(cfg, tmpDir, nil)
+
+	operations, err := p.ParseStationOperations(context.Background())
+	if err != nil {
+		t.Fatalf("ParseStationOperations failed: %v", err)
+	}
+	if op := operations[14]; op.OperationName["en"] != "Law School" || len(op.Services) != 2 || op.Services[0] != 5 {
+		t.Errorf("Unexpected station operation 14: %+v", op)
+	}
+
+	result, err := p.ParseAll(context.Background())
+	if err != nil {
+		t.Fatalf("ParseAll failed: %v", err)
+	}
+	if len(result.Operations) != 2 || len(result.Services) != 2 {
+		t.Errorf("Expected 2 operations and 2 services from ParseAll, got %d and %d", len(result.Operations), len(result.Services))
+	}
+	if result.Services[16].ServiceName["en"] != "Cloning" {
+		t.Errorf("Expected service 16 to be Cloning, got %+v", result.Services[16])
+	}
+
+	// npcStations keeps only blue loot buyers; every station is kept for lookups
+	if len(result.NPCStations) != 2 || len(result.AllNPCStations) != 3 {
+		t.Errorf("Expected 2 blue loot and 3 total stations, got %d and %d", len(result.NPCStations), len(result.AllNPCStations))
+	}
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/guarzo/wanderer-sde/internal/models"
@@ -8,10 +9,10 @@ import (
 )
 
 // ParseTypes parses the types.yaml file.
-func (p *Parser) ParseTypes() (map[int64]models.SDEType, error) {
+func (p *Parser) ParseTypes(ctx context.Context) (map[int64]models.SDEType, error) {
 	path := p.filePath("types.yaml")
 
-	types, err := yaml.ParseFileMap[int64, models.SDEType](path)
+	types, err := yaml.ParseFileMapContext[int64, models.SDEType](ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse types file: %w", err)
 	}
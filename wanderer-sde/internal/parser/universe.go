@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -55,10 +56,10 @@ type SDEMapSolarSystem struct {
 }
 
 // ParseRegions parses the mapRegions.yaml file.
-func (p *Parser) ParseRegions() ([]models.Region, error) {
+func (p *Parser) ParseRegions(ctx context.Context) ([]models.Region, error) {
 	path := p.filePath("mapRegions.yaml")
 
-	rawRegions, err := yaml.ParseFileMap[int64, SDEMapRegion](path)
+	rawRegions, err := yaml.ParseFileMapContext[int64, SDEMapRegion](ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse regions file: %w", err)
 	}
@@ -98,10 +99,10 @@ func (p *Parser) ParseRegions() ([]models.Region, error) {
 }
 
 // ParseConstellations parses the mapConstellations.yaml file.
-func (p *Parser) ParseConstellations() ([]models.Constellation, error) {
+func (p *Parser) ParseConstellations(ctx context.Context) ([]models.Constellation, error) {
 	path := p.filePath("mapConstellations.yaml")
 
-	rawConstellations, err := yaml.ParseFileMap[int64, SDEMapConstellation](path)
+	rawConstellations, err := yaml.ParseFileMapContext[int64, SDEMapConstellation](ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse constellations file: %w", err)
 	}
@@ -141,10 +142,10 @@ func (p *Parser) ParseConstellations() ([]models.Constellation, error) {
 
 // ParseSolarSystems parses the mapSolarSystems.yaml file.
 // starTypeMap provides starID -> typeID mapping for resolving sun types.
-func (p *Parser) ParseSolarSystems(starTypeMap map[int64]int64) ([]models.SolarSystem, error) {
+func (p *Parser) ParseSolarSystems(ctx context.Context, starTypeMap map[int64]int64) ([]models.SolarSystem, error) {
 	path := p.filePath("mapSolarSystems.yaml")
 
-	rawSystems, err := yaml.ParseFileMap[int64, SDEMapSolarSystem](path)
+	rawSystems, err := yaml.ParseFileMapContext[int64, SDEMapSolarSystem](ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse solar systems file: %w", err)
 	}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -11,12 +12,12 @@ import (
 // ExtractAllWormholeClasses extracts wormhole class information from
 // regions, constellations, and solar systems.
 // This matches Fuzzwork's mapLocationWormholeClasses.csv which includes all three.
-func (p *Parser) ExtractAllWormholeClasses() ([]models.WormholeClassLocation, error) {
+func (p *Parser) ExtractAllWormholeClasses(ctx context.Context) ([]models.WormholeClassLocation, error) {
 	var wormholeClasses []models.WormholeClassLocation
 
 	// 1. Extract from regions
 	regionsPath := p.filePath("mapRegions.yaml")
-	rawRegions, err := yaml.ParseFileMap[int64, SDEMapRegion](regionsPath)
+	rawRegions, err := yaml.ParseFileMapContext[int64, SDEMapRegion](ctx, regionsPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse regions for wormhole classes: %w", err)
 	}
@@ -31,7 +32,7 @@ func (p *Parser) ExtractAllWormholeClasses() ([]models.WormholeClassLocation, er
 
 	// 2. Extract from constellations
 	constellationsPath := p.filePath("mapConstellations.yaml")
-	rawConstellations, err := yaml.ParseFileMap[int64, SDEMapConstellation](constellationsPath)
+	rawConstellations, err := yaml.ParseFileMapContext[int64, SDEMapConstellation](ctx, constellationsPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse constellations for wormhole classes: %w", err)
 	}
@@ -46,7 +47,7 @@ func (p *Parser) ExtractAllWormholeClasses() ([]models.WormholeClassLocation, er
 
 	// 3. Extract from solar systems
 	systemsPath := p.filePath("mapSolarSystems.yaml")
-	rawSystems, err := yaml.ParseFileMap[int64, SDEMapSolarSystem](systemsPath)
+	rawSystems, err := yaml.ParseFileMapContext[int64, SDEMapSolarSystem](ctx, systemsPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse solar systems for wormhole classes: %w", err)
 	}
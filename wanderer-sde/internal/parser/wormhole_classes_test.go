@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -13,9 +14,9 @@ func TestExtractAllWormholeClasses(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	wormholeClasses, err := p.ExtractAllWormholeClasses()
+	wormholeClasses, err := p.ExtractAllWormholeClasses(context.Background())
 	if err != nil {
 		t.Fatalf("ExtractAllWormholeClasses failed: %v", err)
 	}
@@ -71,9 +72,9 @@ func TestExtractAllWormholeClasses_NoWormholes(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	cfg := &config.Config{Verbose: false}
-	p := New(cfg, tmpDir)
+	p := New(cfg, tmpDir, nil)
 
-	wormholeClasses, err := p.ExtractAllWormholeClasses()
+	wormholeClasses, err := p.ExtractAllWormholeClasses(context.Background())
 	if err != nil {
 		t.Fatalf("ExtractAllWormholeClasses failed: %v", err)
 	}
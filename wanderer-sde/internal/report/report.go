@@ -0,0 +1,151 @@
+// Package report records a machine-readable summary of a conversion run.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// Timing is how long a named step of the run took.
+type Timing struct {
+	Name       string  `json:"name"`
+	DurationMs float64 `json:"durationMs"`
+}
+
+// Report summarizes a conversion run: how long each step took, how many
+// records each table holds, the validation results and the files written.
+// It is written whether or not the run succeeds.
+type Report struct {
+	Version     string           `json:"version"`
+	SDEVersion  string           `json:"sdeVersion,omitempty"`
+	StartedAt   string           `json:"startedAt"`
+	DurationMs  float64          `json:"durationMs"`
+	Success     bool             `json:"success"`
+	Error       string           `json:"error,omitempty"`
+	Phases      []Timing         `json:"phases"` // Pipeline steps: download, parse, transform, ...
+	Stages      []Timing         `json:"stages"` // Transform stages, in the order they ran
+	Counts      map[string]int   `json:"counts"` // Records per output table
+	Warnings    []string         `json:"warnings"`
+	Errors      []string         `json:"errors"`
+	Findings    []models.Finding `json:"findings,omitempty"`
+	OutputDir   string           `json:"outputDir"`
+	OutputFiles []string         `json:"outputFiles"`
+
+	start time.Time
+}
+
+// New creates an empty report for a run started at start.
+func New(version, outputDir string, start time.Time) *Report {
+	return &Report{
+		Version:     version,
+		StartedAt:   start.UTC().Format(time.RFC3339),
+		Phases:      []Timing{},
+		Stages:      []Timing{},
+		Counts:      map[string]int{},
+		Warnings:    []string{},
+		Errors:      []string{},
+		OutputDir:   outputDir,
+		OutputFiles: []string{},
+		start:       start,
+	}
+}
+
+// AddPhase records a pipeline step that ran from start until now.
+func (r *Report) AddPhase(name string, start time.Time) {
+	r.Phases = append(r.Phases, timing(name, time.Since(start)))
+}
+
+// AddStage records how long a transform stage took.
+func (r *Report) AddStage(name string, d time.Duration) {
+	r.Stages = append(r.Stages, timing(name, d))
+}
+
+// SetCounts records the number of records in each output table of data,
+// keyed by file name without extension.
+func (r *Report) SetCounts(data *models.ConvertedData) {
+	counts := map[string]int{
+		"mapLocationWormholeClasses": len(data.WormholeClasses),
+		"invTypes":                   len(data.InvTypes),
+		"invGroups":                  len(data.InvGroups),
+		"mapSolarSystemJumps":        len(data.SystemJumps),
+		"npcStations":                len(data.NPCStations),
+	}
+	if data.Universe != nil {
+		counts["mapSolarSystems"] = len(data.Universe.SolarSystems)
+		counts["mapRegions"] = len(data.Universe.Regions)
+		counts["mapConstellations"] = len(data.Universe.Constellations)
+	}
+	for _, set := range data.TypeSets {
+		counts["invTypes_"+set.Name] = len(set.Types)
+	}
+	for _, dataset := range data.Datasets {
+		counts[dataset.Name] = len(dataset.Rows)
+	}
+
+	// Generated files are only written when they have entries
+	generated := map[string]int{
+		"wormholes":               len(data.Wormholes),
+		"sunTypes":                len(data.SunTypes),
+		"triglavianSystems":       len(data.TriglavianSystems),
+		"shatteredConstellations": len(data.ShatteredConstellations),
+		"systemEffects":           len(data.SystemEffects),
+	}
+	for name, n := range generated {
+		if n > 0 {
+			counts[name] = n
+		}
+	}
+	if data.DistanceMatrix != nil {
+		counts["systemDistanceMatrix"] = len(data.DistanceMatrix.SystemIDs)
+	}
+
+	r.Counts = counts
+}
+
+// SetValidation records the warnings, errors and findings of a validation.
+func (r *Report) SetValidation(result *models.ValidationResult) {
+	r.Warnings = append([]string{}, result.Warnings...)
+	r.Errors = append([]string{}, result.Errors...)
+	r.Findings = result.Findings
+}
+
+// AddOutputFiles records files written to the output directory.
+func (r *Report) AddOutputFiles(names ...string) {
+	r.OutputFiles = append(r.OutputFiles, names...)
+}
+
+// Finish records the run's total duration and outcome. err is the error
+// the run failed with, or nil.
+func (r *Report) Finish(err error) {
+	r.DurationMs = milliseconds(time.Since(r.start))
+	r.Success = err == nil
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// Write writes the report to path as indented JSON.
+func (r *Report) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write report %s: %w", path, err)
+	}
+	return nil
+}
+
+func timing(name string, d time.Duration) Timing {
+	return Timing{Name: name, DurationMs: milliseconds(d)}
+}
+
+// milliseconds converts d to fractional milliseconds, rounded to the
+// microsecond.
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Round(time.Microsecond)) / float64(time.Millisecond)
+}
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestReport_Write(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "report_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	rep := New("1.2.3", "./output", time.Now())
+	rep.AddPhase("parse", time.Now().Add(-time.Second))
+	rep.AddStage("solarSystems", 1500*time.Microsecond)
+	rep.SetCounts(&models.ConvertedData{
+		Universe: &models.UniverseData{
+			SolarSystems: []models.SolarSystem{{SolarSystemID: 30000142}, {SolarSystemID: 30000144}},
+		},
+		TypeSets: []models.TypeSetData{{Name: "structures", Types: []models.InvType{{TypeID: 35832}}}},
+		Datasets: []models.Dataset{{Name: "nearest", Rows: [][]interface{}{{1}, {2}, {3}}}},
+	})
+	rep.SetValidation(&models.ValidationResult{
+		Warnings: []string{"[JUMP_ASYMMETRIC] Jump 1 -> 2 has no reverse jump"},
+		Findings: []models.Finding{{Code: models.CodeJumpAsymmetric, Severity: models.SeverityWarning}},
+	})
+	rep.AddOutputFiles("mapSolarSystems.csv", "mapRegions.csv")
+	rep.Finish(errors.New("validation failed with 1 errors"))
+
+	path := filepath.Join(tmpDir, "report.json")
+	if err := rep.Write(path); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	var got Report
+	if err := json.Unmarshal(content, &got); err != nil {
+		t.Fatalf("failed to parse report: %v", err)
+	}
+
+	if got.Success || got.Error != "validation failed with 1 errors" {
+		t.Errorf("Expected a failed run with its error, got success=%v error=%q", got.Success, got.Error)
+	}
+	if len(got.Phases) != 1 || got.Phases[0].Name != "parse" || got.Phases[0].DurationMs < 1000 {
+		t.Errorf("Unexpected phases: %+v", got.Phases)
+	}
+	if len(got.Stages) != 1 || got.Stages[0].DurationMs != 1.5 {
+		t.Errorf("Expected a 1.5ms solarSystems stage, got %+v", got.Stages)
+	}
+
+	wantCounts := map[string]int{
+		"mapSolarSystems":     2,
+		"mapRegions":          0,
+		"invTypes_structures": 1,
+		"nearest":             3,
+	}
+	for name, want := range wantCounts {
+		if count, ok := got.Counts[name]; !ok || count != want {
+			t.Errorf("Expected %s count %d, got %d (present %v)", name, want, count, ok)
+		}
+	}
+	if _, ok := got.Counts["wormholes"]; ok {
+		t.Error("Expected no count for an empty generated file")
+	}
+
+	if len(got.Warnings) != 1 || len(got.Errors) != 0 || len(got.Findings) != 1 {
+		t.Errorf("Unexpected validation results: %d warnings, %d errors, %d findings",
+			len(got.Warnings), len(got.Errors), len(got.Findings))
+	}
+	if len(got.OutputFiles) != 2 || got.OutputFiles[0] != "mapSolarSystems.csv" {
+		t.Errorf("Unexpected output files: %v", got.OutputFiles)
+	}
+}
@@ -0,0 +1,181 @@
+package subset
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/logging"
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+	"github.com/guarzo/wanderer-sde/pkg/yaml"
+)
+
+// locatedEntry holds the fields of an SDE entry that tie it to systems.
+type locatedEntry struct {
+	SolarSystemID int64 `yaml:"solarSystemID"`
+	Destination   struct {
+		SolarSystemID int64 `yaml:"solarSystemID"`
+	} `yaml:"destination"`
+}
+
+// sdeFilters lists the location-bound SDE files the converter reads and
+// which of their entries a selection keeps.
+var sdeFilters = map[string]func(set *Set, id int64, e locatedEntry) bool{
+	"mapRegions.yaml":        func(set *Set, id int64, _ locatedEntry) bool { return set.Regions[id] },
+	"mapConstellations.yaml": func(set *Set, id int64, _ locatedEntry) bool { return set.Constellations[id] },
+	"mapSolarSystems.yaml":   func(set *Set, id int64, _ locatedEntry) bool { return set.Systems[id] },
+	"mapStargates.yaml": func(set *Set, _ int64, e locatedEntry) bool {
+		return set.Systems[e.SolarSystemID] && set.Systems[e.Destination.SolarSystemID]
+	},
+	"mapStars.yaml":         func(set *Set, _ int64, e locatedEntry) bool { return set.Systems[e.SolarSystemID] },
+	"mapSecondarySuns.yaml": func(set *Set, _ int64, e locatedEntry) bool { return set.Systems[e.SolarSystemID] },
+	"npcStations.yaml":      func(set *Set, _ int64, e locatedEntry) bool { return set.Systems[e.SolarSystemID] },
+}
+
+// sdeCopies lists the SDE files the converter reads that are not tied to a
+// location. They are copied whole.
+var sdeCopies = []string{
+	"categories.yaml",
+	"groups.yaml",
+	"types.yaml",
+	"typeDogma.yaml",
+	"dogmaAttributes.yaml",
+	"npcCorporations.yaml",
+	"stationOperations.yaml",
+	"stationServices.yaml",
+}
+
+// ExtractSDE writes the part of the SDE at sdePath that a selection keeps
+// to outputDir, as an SDE the converter can read. Location-bound files are
+// filtered to the selection, other files the converter reads are copied,
+// and optional files missing from the source are skipped. A nil logger
+// discards log output.
+func ExtractSDE(ctx context.Context, cfg *config.Config, logger *slog.Logger, sdePath, outputDir string, sel Selection) (*Set, error) {
+	logger = logging.OrDiscard(logger)
+
+	if sel.IsEmpty() {
+		return nil, fmt.Errorf("no regions, constellations or systems selected")
+	}
+	if same, err := sameDir(sdePath, outputDir); err != nil {
+		return nil, err
+	} else if same {
+		return nil, fmt.Errorf("extract output directory must differ from the SDE directory")
+	}
+
+	p := parser.New(cfg, sdePath, logger)
+	universe := &models.UniverseData{}
+	var err error
+	if universe.Regions, err = p.ParseRegions(ctx); err != nil {
+		return nil, err
+	}
+	if universe.Constellations, err = p.ParseConstellations(ctx); err != nil {
+		return nil, err
+	}
+	if universe.SolarSystems, err = p.ParseSolarSystems(ctx, nil); err != nil {
+		return nil, err
+	}
+	jumps, err := p.ParseStargates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := Resolve(sel, universe, jumps)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	for _, filename := range sortedKeys(sdeFilters) {
+		src := filepath.Join(sdePath, filename)
+		if !fileExists(src) {
+			logger.Debug("skipping optional file", "file", filename)
+			continue
+		}
+		filter := sdeFilters[filename]
+		kept, err := yaml.FilterFileMap(ctx, src, filepath.Join(outputDir, filename), func(id int64, entry yaml.Entry) (bool, error) {
+			var located locatedEntry
+			if err := entry.Decode(&located); err != nil {
+				return false, err
+			}
+			return filter(set, id, located), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug("wrote file", "file", filename, "entries", kept)
+	}
+
+	for _, filename := range sdeCopies {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		src := filepath.Join(sdePath, filename)
+		if !fileExists(src) {
+			logger.Debug("skipping optional file", "file", filename)
+			continue
+		}
+		if err := copyFile(src, filepath.Join(outputDir, filename)); err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", filename, err)
+		}
+		logger.Debug("wrote file", "file", filename)
+	}
+
+	return set, nil
+}
+
+// sameDir reports whether a and b are the same directory path.
+func sameDir(a, b string) (bool, error) {
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+	absB, err := filepath.Abs(b)
+	if err != nil {
+		return false, err
+	}
+	return absA == absB, nil
+}
+
+// sortedKeys returns the file names of filters in a stable order.
+func sortedKeys(filters map[string]func(*Set, int64, locatedEntry) bool) []string {
+	names := make([]string, 0, len(filters))
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fileExists reports whether path exists and is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// copyFile copies a single file from src to dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
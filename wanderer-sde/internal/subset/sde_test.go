@@ -0,0 +1,201 @@
+package subset
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+func writeTestSDE(t *testing.T, dir string) {
+	t.Helper()
+
+	files := map[string]string{
+		"mapRegions.yaml": `10000002:
+  name:
+    en: "The Forge"
+10000001:
+  name:
+    en: "Derelik"
+`,
+		"mapConstellations.yaml": `20000020:
+  regionID: 10000002
+  name:
+    en: "Kimotoro"
+20000001:
+  regionID: 10000001
+  name:
+    en: "Joas"
+`,
+		"mapSolarSystems.yaml": `30000142:
+  constellationID: 20000020
+  regionID: 10000002
+  name:
+    en: "Jita"
+  securityStatus: 0.9459
+30000001:
+  constellationID: 20000001
+  regionID: 10000001
+  name:
+    en: "Tanoo"
+  securityStatus: 0.8576
+`,
+		"mapStargates.yaml": `50000001:
+  solarSystemID: 30000142
+  destination:
+    solarSystemID: 30000001
+    stargateID: 50000002
+50000002:
+  solarSystemID: 30000001
+  destination:
+    solarSystemID: 30000142
+    stargateID: 50000001
+`,
+		"npcStations.yaml": `60003760:
+  solarSystemID: 30000142
+  ownerID: 1000035
+  typeID: 1531
+60000001:
+  solarSystemID: 30000001
+  ownerID: 1000035
+  typeID: 1531
+`,
+		"groups.yaml": `25:
+  categoryID: 6
+  name:
+    en: "Frigate"
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+}
+
+func TestExtractSDE(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "subset-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	sdeDir := filepath.Join(tmpDir, "sde")
+	outDir := filepath.Join(tmpDir, "out")
+	if err := os.MkdirAll(sdeDir, 0755); err != nil {
+		t.Fatalf("failed to create SDE dir: %v", err)
+	}
+	writeTestSDE(t, sdeDir)
+
+	cfg := &config.Config{}
+	ctx := context.Background()
+	set, err := ExtractSDE(ctx, cfg, nil, sdeDir, outDir, Selection{Systems: []string{"Jita"}})
+	if err != nil {
+		t.Fatalf("ExtractSDE failed: %v", err)
+	}
+	if len(set.Systems) != 1 || !set.Systems[30000142] {
+		t.Errorf("Expected only Jita to be selected, got %v", set.Systems)
+	}
+
+	// The extracted SDE parses and holds only the selection
+	p := parser.New(cfg, outDir, nil)
+	systems, err := p.ParseSolarSystems(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to parse extracted systems: %v", err)
+	}
+	if len(systems) != 1 || systems[0].SolarSystemName != "Jita" {
+		t.Errorf("Expected only Jita in extracted systems, got %+v", systems)
+	}
+
+	regions, err := p.ParseRegions(ctx)
+	if err != nil {
+		t.Fatalf("failed to parse extracted regions: %v", err)
+	}
+	if len(regions) != 1 || regions[0].RegionID != 10000002 {
+		t.Errorf("Expected only The Forge in extracted regions, got %+v", regions)
+	}
+
+	jumps, err := p.ParseStargates(ctx)
+	if err != nil {
+		t.Fatalf("failed to parse extracted stargates: %v", err)
+	}
+	if len(jumps) != 0 {
+		t.Errorf("Expected no jumps leaving the selection, got %+v", jumps)
+	}
+
+	stations, err := p.ParseNPCStations(ctx)
+	if err != nil {
+		t.Fatalf("failed to parse extracted stations: %v", err)
+	}
+	if _, ok := stations[60003760]; len(stations) != 1 || !ok {
+		t.Errorf("Expected only the Jita station, got %+v", stations)
+	}
+
+	if !fileExists(filepath.Join(outDir, "groups.yaml")) {
+		t.Error("Expected groups.yaml to be copied")
+	}
+	if fileExists(filepath.Join(outDir, "types.yaml")) {
+		t.Error("Expected missing optional files to be skipped")
+	}
+}
+
+func TestExtractSDE_Boundary(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "subset-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	sdeDir := filepath.Join(tmpDir, "sde")
+	outDir := filepath.Join(tmpDir, "out")
+	if err := os.MkdirAll(sdeDir, 0755); err != nil {
+		t.Fatalf("failed to create SDE dir: %v", err)
+	}
+	writeTestSDE(t, sdeDir)
+
+	cfg := &config.Config{}
+	ctx := context.Background()
+	if _, err := ExtractSDE(ctx, cfg, nil, sdeDir, outDir, Selection{Systems: []string{"Jita"}, Boundary: true}); err != nil {
+		t.Fatalf("ExtractSDE failed: %v", err)
+	}
+
+	jumps, err := parser.New(cfg, outDir, nil).ParseStargates(ctx)
+	if err != nil {
+		t.Fatalf("failed to parse extracted stargates: %v", err)
+	}
+	if len(jumps) != 2 {
+		t.Errorf("Expected both stargates to the boundary system, got %d", len(jumps))
+	}
+}
+
+func TestExtractSDE_Errors(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "subset-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	writeTestSDE(t, tmpDir)
+
+	cfg := &config.Config{}
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		output string
+		sel    Selection
+	}{
+		{"empty selection", filepath.Join(tmpDir, "out"), Selection{}},
+		{"output is SDE dir", tmpDir, Selection{Systems: []string{"Jita"}}},
+		{"unknown system", filepath.Join(tmpDir, "out"), Selection{Systems: []string{"Amarr"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExtractSDE(ctx, cfg, nil, tmpDir, tt.output, tt.sel); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// Package subset selects part of New Eden by region, constellation or
+// solar system and cuts converted data or an SDE down to it. The result is
+// referentially closed: every jump, station and wormhole class kept refers
+// only to kept systems, constellations and regions.
+package subset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// Selection names the regions, constellations and solar systems to keep,
+// by name (case-insensitive) or ID. The kept systems are the union of the
+// systems in each.
+type Selection struct {
+	Regions        []string
+	Constellations []string
+	Systems        []string
+
+	// Boundary also keeps systems one stargate jump outside the selection,
+	// so routes leaving it can be followed to the first system beyond.
+	Boundary bool
+}
+
+// IsEmpty reports whether the selection names nothing, i.e. keeps
+// everything.
+func (s Selection) IsEmpty() bool {
+	return len(s.Regions) == 0 && len(s.Constellations) == 0 && len(s.Systems) == 0
+}
+
+// Set is a resolved selection: the IDs of everything kept.
+type Set struct {
+	Systems        map[int64]bool // Selected and boundary systems
+	Boundary       map[int64]bool // Boundary systems only
+	Constellations map[int64]bool // Constellations of the kept systems
+	Regions        map[int64]bool // Regions of the kept systems
+}
+
+// Resolve finds the systems a selection keeps, adding boundary systems
+// reachable through jumps if requested, and the constellations and regions
+// they belong to. It fails on names or IDs that match nothing.
+func Resolve(sel Selection, universe *models.UniverseData, jumps []models.SystemJump) (*Set, error) {
+	regions := make(map[int64]bool)
+	for _, name := range sel.Regions {
+		id, err := match(name, "region", universe.Regions, func(r models.Region) (int64, string) {
+			return r.RegionID, r.RegionName
+		})
+		if err != nil {
+			return nil, err
+		}
+		regions[id] = true
+	}
+
+	constellations := make(map[int64]bool)
+	for _, name := range sel.Constellations {
+		id, err := match(name, "constellation", universe.Constellations, func(c models.Constellation) (int64, string) {
+			return c.ConstellationID, c.ConstellationName
+		})
+		if err != nil {
+			return nil, err
+		}
+		constellations[id] = true
+	}
+
+	systems := make(map[int64]bool)
+	for _, name := range sel.Systems {
+		id, err := match(name, "solar system", universe.SolarSystems, func(s models.SolarSystem) (int64, string) {
+			return s.SolarSystemID, s.SolarSystemName
+		})
+		if err != nil {
+			return nil, err
+		}
+		systems[id] = true
+	}
+
+	set := &Set{
+		Systems:        make(map[int64]bool),
+		Boundary:       make(map[int64]bool),
+		Constellations: make(map[int64]bool),
+		Regions:        make(map[int64]bool),
+	}
+	for _, sys := range universe.SolarSystems {
+		if systems[sys.SolarSystemID] || constellations[sys.ConstellationID] || regions[sys.RegionID] {
+			set.Systems[sys.SolarSystemID] = true
+		}
+	}
+
+	if sel.Boundary {
+		for _, j := range jumps {
+			if set.Systems[j.FromSolarSystemID] && !set.Systems[j.ToSolarSystemID] {
+				set.Boundary[j.ToSolarSystemID] = true
+			}
+		}
+		for id := range set.Boundary {
+			set.Systems[id] = true
+		}
+	}
+
+	for _, sys := range universe.SolarSystems {
+		if set.Systems[sys.SolarSystemID] {
+			set.Constellations[sys.ConstellationID] = true
+			set.Regions[sys.RegionID] = true
+		}
+	}
+
+	return set, nil
+}
+
+// match returns the ID of the record whose ID or name is value.
+func match[T any](value, kind string, records []T, key func(T) (int64, string)) (int64, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	isID := err == nil
+	for _, record := range records {
+		recordID, name := key(record)
+		if (isID && recordID == id) || strings.EqualFold(name, value) {
+			return recordID, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %s %q", kind, value)
+}
+
+// Filter cuts converted data down to a resolved selection. Jumps are kept
+// only between kept systems, and wormhole classes only for kept locations.
+// Datasets keep only rows whose declared location columns are all kept,
+// and the distance matrix only kept systems. Types and groups are not
+// location-bound and are left as they are.
+func Filter(data *models.ConvertedData, set *Set) {
+	if data.Universe != nil {
+		data.Universe.Regions = keep(data.Universe.Regions, func(r models.Region) bool {
+			return set.Regions[r.RegionID]
+		})
+		data.Universe.Constellations = keep(data.Universe.Constellations, func(c models.Constellation) bool {
+			return set.Constellations[c.ConstellationID]
+		})
+		data.Universe.SolarSystems = keep(data.Universe.SolarSystems, func(s models.SolarSystem) bool {
+			return set.Systems[s.SolarSystemID]
+		})
+	}
+
+	data.SystemJumps = keep(data.SystemJumps, func(j models.SystemJump) bool {
+		return set.Systems[j.FromSolarSystemID] && set.Systems[j.ToSolarSystemID]
+	})
+	data.NPCStations = keep(data.NPCStations, func(st models.NPCStation) bool {
+		return set.Systems[st.SolarSystemID]
+	})
+	data.WormholeClasses = keep(data.WormholeClasses, func(wc models.WormholeClassLocation) bool {
+		return set.Regions[wc.LocationID] || set.Constellations[wc.LocationID] || set.Systems[wc.LocationID]
+	})
+	data.SystemEffects = keep(data.SystemEffects, func(e models.SystemEffect) bool {
+		return set.Systems[e.SolarSystemID]
+	})
+	data.TriglavianSystems = keep(data.TriglavianSystems, func(s models.TriglavianSystem) bool {
+		return set.Systems[s.SolarSystemID]
+	})
+	data.UnclassifiedSystems = keep(data.UnclassifiedSystems, func(id int64) bool {
+		return set.Systems[id]
+	})
+
+	shattered := data.ShatteredConstellations[:0]
+	for _, c := range data.ShatteredConstellations {
+		if !set.Constellations[c.ConstellationID] {
+			continue
+		}
+		c.SolarSystemIDs = keep(c.SolarSystemIDs, func(id int64) bool { return set.Systems[id] })
+		shattered = append(shattered, c)
+	}
+	data.ShatteredConstellations = shattered
+
+	for i := range data.Datasets {
+		filterDataset(&data.Datasets[i], set)
+	}
+
+	if data.DistanceMatrix != nil {
+		data.DistanceMatrix = filterMatrix(data.DistanceMatrix, set)
+	}
+}
+
+// filterMatrix keeps the rows and columns of a distance matrix for kept
+// systems. Jump counts are those of the full map, which routes outside the
+// selection may shorten.
+func filterMatrix(m *models.DistanceMatrix, set *Set) *models.DistanceMatrix {
+	var kept []int
+	for i, id := range m.SystemIDs {
+		if set.Systems[id] {
+			kept = append(kept, i)
+		}
+	}
+
+	n := len(m.SystemIDs)
+	out := &models.DistanceMatrix{
+		SystemIDs: make([]int64, len(kept)),
+		Jumps:     make([]uint8, 0, len(kept)*len(kept)),
+	}
+	for k, i := range kept {
+		out.SystemIDs[k] = m.SystemIDs[i]
+		for _, j := range kept {
+			out.Jumps = append(out.Jumps, m.Jumps[i*n+j])
+		}
+	}
+	return out
+}
+
+// filterDataset drops rows of a dataset naming a system, constellation or
+// region outside the selection in any of its location columns, and removes
+// systems outside the selection from its system list columns.
+func filterDataset(dataset *models.Dataset, set *Set) {
+	checks := make(map[int]map[int64]bool)
+	var lists []int
+	for i, name := range dataset.Columns {
+		switch dataset.Locations[name] {
+		case models.LocationSystem:
+			checks[i] = set.Systems
+		case models.LocationConstellation:
+			checks[i] = set.Constellations
+		case models.LocationRegion:
+			checks[i] = set.Regions
+		case models.LocationSystemList:
+			lists = append(lists, i)
+		}
+	}
+	if len(checks) == 0 && len(lists) == 0 {
+		return
+	}
+
+	dataset.Rows = keep(dataset.Rows, func(row []interface{}) bool {
+		for column, kept := range checks {
+			if column >= len(row) {
+				return false
+			}
+			switch id := row[column].(type) {
+			case int64:
+				if !kept[id] {
+					return false
+				}
+			case int:
+				if !kept[int64(id)] {
+					return false
+				}
+			}
+		}
+		for _, column := range lists {
+			if column < len(row) {
+				if ids, ok := row[column].([]int64); ok {
+					row[column] = keep(append([]int64(nil), ids...), func(id int64) bool {
+						return set.Systems[id]
+					})
+				}
+			}
+		}
+		return true
+	})
+}
+
+// keep filters records in place, returning those for which ok is true.
+func keep[T any](records []T, ok func(T) bool) []T {
+	result := records[:0]
+	for _, record := range records {
+		if ok(record) {
+			result = append(result, record)
+		}
+	}
+	return result
+}
@@ -0,0 +1,218 @@
+package subset
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// testUniverse is a line of systems A - B - C - D across two constellations
+// of one region, plus an unconnected system E in a second region.
+func testUniverse() *models.ConvertedData {
+	jump := func(from, to int64) []models.SystemJump {
+		return []models.SystemJump{
+			{FromSolarSystemID: from, ToSolarSystemID: to},
+			{FromSolarSystemID: to, ToSolarSystemID: from},
+		}
+	}
+	var jumps []models.SystemJump
+	jumps = append(jumps, jump(1, 2)...)
+	jumps = append(jumps, jump(2, 3)...)
+	jumps = append(jumps, jump(3, 4)...)
+
+	return &models.ConvertedData{
+		Universe: &models.UniverseData{
+			Regions: []models.Region{
+				{RegionID: 100, RegionName: "The Forge"},
+				{RegionID: 200, RegionName: "Domain"},
+			},
+			Constellations: []models.Constellation{
+				{ConstellationID: 10, RegionID: 100, ConstellationName: "Kimotoro"},
+				{ConstellationID: 11, RegionID: 100, ConstellationName: "Otsabaira"},
+				{ConstellationID: 20, RegionID: 200, ConstellationName: "Throne Worlds"},
+			},
+			SolarSystems: []models.SolarSystem{
+				{SolarSystemID: 1, ConstellationID: 10, RegionID: 100, SolarSystemName: "Alpha"},
+				{SolarSystemID: 2, ConstellationID: 10, RegionID: 100, SolarSystemName: "Bravo"},
+				{SolarSystemID: 3, ConstellationID: 11, RegionID: 100, SolarSystemName: "Charlie"},
+				{SolarSystemID: 4, ConstellationID: 11, RegionID: 100, SolarSystemName: "Delta"},
+				{SolarSystemID: 5, ConstellationID: 20, RegionID: 200, SolarSystemName: "Echo"},
+			},
+		},
+		SystemJumps: jumps,
+		NPCStations: []models.NPCStation{
+			{StationID: 60000001, SolarSystemID: 1},
+			{StationID: 60000003, SolarSystemID: 3},
+		},
+		WormholeClasses: []models.WormholeClassLocation{
+			{LocationID: 100, WormholeClassID: 7},
+			{LocationID: 200, WormholeClassID: 9},
+			{LocationID: 3, WormholeClassID: 8},
+		},
+		ShatteredConstellations: []models.ShatteredConstellation{
+			{ConstellationID: 11, SolarSystemIDs: []int64{3, 4}},
+		},
+		Datasets: []models.Dataset{
+			{
+				Name:      "perSystem",
+				Columns:   []string{"solarSystemID", "value"},
+				Locations: map[string]models.LocationKind{"solarSystemID": models.LocationSystem},
+				Rows:      [][]interface{}{{int64(1), 1}, {int64(5), 5}},
+			},
+			// Columns are only filtered when the dataset declares them
+			{Name: "undeclared", Columns: []string{"solarSystemID"}, Rows: [][]interface{}{{int64(5)}}},
+			{
+				Name:    "constellationPairs",
+				Columns: []string{"fromConstellationID", "toConstellationID", "fromBorderSystemIDs", "toBorderSystemIDs"},
+				Locations: map[string]models.LocationKind{
+					"fromConstellationID": models.LocationConstellation,
+					"toConstellationID":   models.LocationConstellation,
+					"fromBorderSystemIDs": models.LocationSystemList,
+					"toBorderSystemIDs":   models.LocationSystemList,
+				},
+				Rows: [][]interface{}{
+					{int64(10), int64(11), []int64{1, 2}, []int64{3}},
+					{int64(11), int64(20), []int64{4}, []int64{5}},
+				},
+			},
+		},
+		DistanceMatrix: &models.DistanceMatrix{
+			SystemIDs: []int64{1, 2, 3, 4},
+			Jumps: []uint8{
+				0, 1, 2, 3,
+				1, 0, 1, 2,
+				2, 1, 0, 1,
+				3, 2, 1, 0,
+			},
+		},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name        string
+		sel         Selection
+		wantSystems []int64
+		wantRegions []int64
+		wantErr     bool
+	}{
+		{"system by name", Selection{Systems: []string{"alpha"}}, []int64{1}, []int64{100}, false},
+		{"system by ID", Selection{Systems: []string{"2"}}, []int64{2}, []int64{100}, false},
+		{"constellation", Selection{Constellations: []string{"Kimotoro"}}, []int64{1, 2}, []int64{100}, false},
+		{"region", Selection{Regions: []string{"The Forge"}}, []int64{1, 2, 3, 4}, []int64{100}, false},
+		{"union", Selection{Regions: []string{"Domain"}, Systems: []string{"Delta"}}, []int64{4, 5}, []int64{100, 200}, false},
+		{"boundary", Selection{Systems: []string{"Bravo"}, Boundary: true}, []int64{1, 2, 3}, []int64{100}, false},
+		{"unknown", Selection{Systems: []string{"Jita"}}, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testUniverse()
+			set, err := Resolve(tt.sel, data.Universe, data.SystemJumps)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error for an unknown name")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+
+			if len(set.Systems) != len(tt.wantSystems) {
+				t.Errorf("Expected systems %v, got %v", tt.wantSystems, set.Systems)
+			}
+			for _, id := range tt.wantSystems {
+				if !set.Systems[id] {
+					t.Errorf("Expected system %d to be kept", id)
+				}
+			}
+			if len(set.Regions) != len(tt.wantRegions) {
+				t.Errorf("Expected regions %v, got %v", tt.wantRegions, set.Regions)
+			}
+			for _, id := range tt.wantRegions {
+				if !set.Regions[id] {
+					t.Errorf("Expected region %d to be kept", id)
+				}
+			}
+		})
+	}
+}
+
+func TestResolve_BoundarySystems(t *testing.T) {
+	data := testUniverse()
+	set, err := Resolve(Selection{Systems: []string{"Bravo"}, Boundary: true}, data.Universe, data.SystemJumps)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	if len(set.Boundary) != 2 || !set.Boundary[1] || !set.Boundary[3] {
+		t.Errorf("Expected boundary systems 1 and 3, got %v", set.Boundary)
+	}
+	// Charlie's constellation is kept so the boundary system stays closed
+	if !set.Constellations[11] {
+		t.Error("Expected the boundary system's constellation to be kept")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	data := testUniverse()
+	set, err := Resolve(Selection{Constellations: []string{"Otsabaira"}, Boundary: true}, data.Universe, data.SystemJumps)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	Filter(data, set)
+
+	if len(data.Universe.SolarSystems) != 3 {
+		t.Errorf("Expected 3 systems (2 selected, 1 boundary), got %d", len(data.Universe.SolarSystems))
+	}
+	if len(data.Universe.Constellations) != 2 || len(data.Universe.Regions) != 1 {
+		t.Errorf("Expected 2 constellations and 1 region, got %d and %d",
+			len(data.Universe.Constellations), len(data.Universe.Regions))
+	}
+
+	// Every remaining jump stays inside the kept systems
+	for _, j := range data.SystemJumps {
+		if !set.Systems[j.FromSolarSystemID] || !set.Systems[j.ToSolarSystemID] {
+			t.Errorf("Jump %d -> %d leaves the selection", j.FromSolarSystemID, j.ToSolarSystemID)
+		}
+	}
+	if len(data.SystemJumps) != 4 {
+		t.Errorf("Expected 4 jumps (2-3, 3-4 both ways), got %d", len(data.SystemJumps))
+	}
+
+	if len(data.NPCStations) != 1 || data.NPCStations[0].StationID != 60000003 {
+		t.Errorf("Expected only station 60000003, got %+v", data.NPCStations)
+	}
+	if len(data.WormholeClasses) != 2 {
+		t.Errorf("Expected the region and system wormhole classes, got %+v", data.WormholeClasses)
+	}
+	if len(data.ShatteredConstellations) != 1 || len(data.ShatteredConstellations[0].SolarSystemIDs) != 2 {
+		t.Errorf("Unexpected shattered constellations: %+v", data.ShatteredConstellations)
+	}
+
+	if rows := data.Datasets[0].Rows; len(rows) != 0 {
+		t.Errorf("Expected per-system dataset rows outside the selection to be dropped, got %v", rows)
+	}
+	if rows := data.Datasets[1].Rows; len(rows) != 1 {
+		t.Errorf("Expected datasets without declared location columns to be kept, got %v", rows)
+	}
+
+	// Rows need every location column kept; ID lists lose dropped systems
+	pairs := data.Datasets[2].Rows
+	if len(pairs) != 1 {
+		t.Fatalf("Expected 1 constellation pair inside the selection, got %v", pairs)
+	}
+	if !reflect.DeepEqual(pairs[0][2], []int64{2}) || !reflect.DeepEqual(pairs[0][3], []int64{3}) {
+		t.Errorf("Expected border systems [2] and [3], got %v", pairs[0])
+	}
+
+	m := data.DistanceMatrix
+	if !reflect.DeepEqual(m.SystemIDs, []int64{2, 3, 4}) {
+		t.Fatalf("Expected matrix systems [2 3 4], got %v", m.SystemIDs)
+	}
+	if !reflect.DeepEqual(m.Jumps, []uint8{0, 1, 2, 1, 0, 1, 2, 1, 0}) {
+		t.Errorf("Unexpected filtered matrix: %v", m.Jumps)
+	}
+}
@@ -0,0 +1,33 @@
+package transformer
+
+import (
+	"github.com/guarzo/wanderer-sde/internal/baseline"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// validateBaseline compares the converted data with the configured baseline
+// output. A baseline or allow-list that cannot be read fails validation.
+func (t *Transformer) validateBaseline(data *models.ConvertedData, result *models.ValidationResult) {
+	previous, err := baseline.Load(t.config.BaselineDir)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return
+	}
+
+	var allow baseline.Allowlist
+	if t.config.BaselineAllowFile != "" {
+		allow, err = baseline.LoadAllowlist(t.config.BaselineAllowFile)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			return
+		}
+	}
+
+	thresholds := baseline.Thresholds{
+		MaxRemovedPercent: t.config.BaselineMaxRemoved,
+		MaxChangedPercent: t.config.BaselineMaxChanged,
+	}
+	current := baseline.FromConvertedData(data)
+	addFindings(result, baseline.Compare(previous, current, thresholds, allow))
+	addFindings(result, baseline.CompareMainCluster(previous, current))
+}
@@ -0,0 +1,158 @@
+package transformer
+
+import (
+	"sort"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// Chokepoint table names.
+const (
+	ChokepointsDataset       = "mapChokepoints"
+	RegionChokepointsDataset = "mapRegionChokepoints"
+)
+
+// systemChokepoint is what the chokepoint analysis found for one system.
+type systemChokepoint struct {
+	articulation bool
+	bridgeGates  int64
+	pipeLength   int64
+	pocket       *graph.Pocket // Largest dead end the system is in
+}
+
+// GenerateChokepoints analyses the stargate graph and returns a table of
+// the systems involved in a chokepoint and a table of counts per region,
+// both ordered by ID. Systems that are none of an articulation point, the
+// end of a bridge gate, part of a pipe or inside a dead end have no row.
+//
+// Per system, deadEndEntranceID and deadEndSize describe the largest dead
+// end the system is in. Per region, bridge gates and pipes crossing a
+// region border count in both regions, and dead ends count in the region
+// of their entrance.
+func GenerateChokepoints(g *graph.Graph) (systems, regions models.Dataset) {
+	c := g.Chokepoints()
+
+	found := make(map[int64]*systemChokepoint)
+	get := func(id int64) *systemChokepoint {
+		if found[id] == nil {
+			found[id] = &systemChokepoint{}
+		}
+		return found[id]
+	}
+	regionOf := func(id int64) int64 {
+		sys, _ := g.System(id)
+		return sys.RegionID
+	}
+
+	type regionCounts struct {
+		articulationPoints, bridgeGates, pipes, deadEnds, largestDeadEnd int64
+	}
+	perRegion := make(map[int64]*regionCounts)
+	region := func(id int64) *regionCounts {
+		if perRegion[id] == nil {
+			perRegion[id] = &regionCounts{}
+		}
+		return perRegion[id]
+	}
+
+	for _, id := range c.ArticulationPoints {
+		get(id).articulation = true
+		region(regionOf(id)).articulationPoints++
+	}
+	for _, b := range c.Bridges {
+		get(b.From).bridgeGates++
+		get(b.To).bridgeGates++
+		for _, r := range uniqueRegions(regionOf(b.From), regionOf(b.To)) {
+			region(r).bridgeGates++
+		}
+	}
+	for _, pipe := range c.Pipes {
+		var pipeRegions []int64
+		for _, id := range pipe {
+			get(id).pipeLength = int64(len(pipe))
+			pipeRegions = append(pipeRegions, regionOf(id))
+		}
+		for _, r := range uniqueRegions(pipeRegions...) {
+			region(r).pipes++
+		}
+	}
+	for i := range c.DeadEnds {
+		pocket := &c.DeadEnds[i]
+		for _, id := range pocket.Systems {
+			sys := get(id)
+			if sys.pocket == nil || len(pocket.Systems) > len(sys.pocket.Systems) {
+				sys.pocket = pocket
+			}
+		}
+		counts := region(regionOf(pocket.Entrance))
+		counts.deadEnds++
+		counts.largestDeadEnd = max(counts.largestDeadEnd, int64(len(pocket.Systems)))
+	}
+
+	systems = models.Dataset{
+		Name: ChokepointsDataset,
+		Columns: []string{"solarSystemID", "regionID", "articulationPoint", "bridgeGates",
+			"pipeLength", "deadEndEntranceID", "deadEndSize"},
+		Keys: []string{"solarSystemID"},
+		Locations: map[string]models.LocationKind{
+			"solarSystemID": models.LocationSystem,
+			"regionID":      models.LocationRegion,
+		},
+		Rows: make([][]interface{}, 0, len(found)),
+	}
+	for _, sys := range g.Systems() {
+		f, ok := found[sys.ID]
+		if !ok {
+			continue
+		}
+		var entrance interface{}
+		var size int64
+		if f.pocket != nil {
+			entrance = f.pocket.Entrance
+			size = int64(len(f.pocket.Systems))
+		}
+		systems.Rows = append(systems.Rows, []interface{}{
+			sys.ID, sys.RegionID, f.articulation, f.bridgeGates, f.pipeLength, entrance, size,
+		})
+	}
+
+	regionIDs := make([]int64, 0, len(perRegion))
+	for id := range perRegion {
+		regionIDs = append(regionIDs, id)
+	}
+	sort.Slice(regionIDs, func(i, j int) bool { return regionIDs[i] < regionIDs[j] })
+
+	regions = models.Dataset{
+		Name: RegionChokepointsDataset,
+		Columns: []string{"regionID", "articulationPoints", "bridgeGates", "pipes",
+			"deadEnds", "largestDeadEnd"},
+		Keys: []string{"regionID"},
+		Locations: map[string]models.LocationKind{
+			"regionID": models.LocationRegion,
+		},
+		Rows: make([][]interface{}, 0, len(regionIDs)),
+	}
+	for _, id := range regionIDs {
+		r := perRegion[id]
+		regions.Rows = append(regions.Rows, []interface{}{
+			id, r.articulationPoints, r.bridgeGates, r.pipes, r.deadEnds, r.largestDeadEnd,
+		})
+	}
+	return systems, regions
+}
+
+// uniqueRegions returns the distinct region IDs in ids, in first-seen order.
+func uniqueRegions(ids ...int64) []int64 {
+	var unique []int64
+	for _, id := range ids {
+		seen := false
+		for _, u := range unique {
+			seen = seen || u == id
+		}
+		if !seen {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
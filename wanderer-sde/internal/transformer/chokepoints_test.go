@@ -0,0 +1,66 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestGenerateChokepoints(t *testing.T) {
+	// A ring in region 100 with a tail of two systems into region 200:
+	//
+	//	1 - 2
+	//	|   |      region 100: 1, 2, 3, 4
+	//	4 - 3 - 5 - 6      region 200: 5, 6
+	//
+	// 5 alone is shorter than graph.MinPipeLength, so the only pipe is 4-1-2.
+	systems := []models.SolarSystem{
+		{SolarSystemID: 1, RegionID: 100},
+		{SolarSystemID: 2, RegionID: 100},
+		{SolarSystemID: 3, RegionID: 100},
+		{SolarSystemID: 4, RegionID: 100},
+		{SolarSystemID: 5, RegionID: 200},
+		{SolarSystemID: 6, RegionID: 200},
+	}
+	var jumps []models.SystemJump
+	for _, pair := range [][2]int64{{1, 2}, {2, 3}, {3, 4}, {4, 1}, {3, 5}, {5, 6}} {
+		jumps = append(jumps, models.SystemJump{FromSolarSystemID: pair[0], ToSolarSystemID: pair[1]})
+	}
+
+	perSystem, perRegion := GenerateChokepoints(graph.New(systems, jumps))
+	if perSystem.Name != ChokepointsDataset || perRegion.Name != RegionChokepointsDataset {
+		t.Errorf("Unexpected dataset names %q and %q", perSystem.Name, perRegion.Name)
+	}
+
+	expectedSystems := [][]interface{}{
+		{int64(1), int64(100), false, int64(0), int64(3), nil, int64(0)},
+		{int64(2), int64(100), false, int64(0), int64(3), nil, int64(0)},
+		{int64(3), int64(100), true, int64(1), int64(0), nil, int64(0)},
+		{int64(4), int64(100), false, int64(0), int64(3), nil, int64(0)},
+		{int64(5), int64(200), true, int64(2), int64(0), int64(3), int64(2)},
+		{int64(6), int64(200), false, int64(1), int64(0), int64(3), int64(2)},
+	}
+	assertRows(t, perSystem.Rows, expectedSystems)
+
+	expectedRegions := [][]interface{}{
+		{int64(100), int64(1), int64(1), int64(1), int64(1), int64(2)},
+		{int64(200), int64(1), int64(2), int64(0), int64(1), int64(1)},
+	}
+	assertRows(t, perRegion.Rows, expectedRegions)
+}
+
+func assertRows(t *testing.T, rows, expected [][]interface{}) {
+	t.Helper()
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %v", len(expected), rows)
+	}
+	for i, row := range rows {
+		for j := range row {
+			if row[j] != expected[i][j] {
+				t.Errorf("Row %d: expected %v, got %v", i, expected[i], row)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package transformer
+
+import (
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// GateComponentsDataset is the table of stargate components.
+const GateComponentsDataset = "mapGateComponents"
+
+// Stargate component labels.
+const (
+	ComponentMain     = "main"     // The largest component: known space
+	ComponentPochven  = "pochven"  // Pochven, cut off from known space
+	ComponentIsolated = "isolated" // A single system without stargates, e.g. wormhole space
+	ComponentDetached = "detached" // Any other group of connected systems
+)
+
+// AddConnectivityColumns sets gateReachable and componentID on each solar
+// system and returns the stargate components as a table ordered by size.
+// A component's ID is its lowest system ID, which stays stable between
+// builds as long as that system keeps its gates. Systems are gate
+// reachable when they are in the main component.
+func AddConnectivityColumns(systems []models.SolarSystem, g *graph.Graph) models.Dataset {
+	components := g.Components()
+
+	dataset := models.Dataset{
+		Name:    GateComponentsDataset,
+		Columns: []string{"componentID", "label", "systemCount", "solarSystemIDs"},
+		Locations: map[string]models.LocationKind{
+			"solarSystemIDs": models.LocationSystemList,
+		},
+		Rows: make([][]interface{}, 0, len(components)),
+	}
+	componentOf := make(map[int64]int64, g.Len())
+	mainID := int64(-1)
+	for i, ids := range components {
+		id := ids[0]
+		label := componentLabel(g, ids, i == 0)
+		if label == ComponentMain {
+			mainID = id
+		}
+		for _, sys := range ids {
+			componentOf[sys] = id
+		}
+		dataset.Rows = append(dataset.Rows, []interface{}{id, label, int64(len(ids)), ids})
+	}
+
+	for i := range systems {
+		id, ok := componentOf[systems[i].SolarSystemID]
+		if !ok {
+			continue
+		}
+		reachable := id == mainID
+		systems[i].ComponentID = models.Int64PtrAlways(id)
+		systems[i].GateReachable = &reachable
+	}
+	return dataset
+}
+
+// componentLabel labels a stargate component. Only the largest component
+// can be main, and only if it has more than one system.
+func componentLabel(g *graph.Graph, ids []int64, largest bool) string {
+	switch {
+	case len(ids) == 1:
+		return ComponentIsolated
+	case largest:
+		return ComponentMain
+	}
+	for _, id := range ids {
+		if sys, _ := g.System(id); sys.RegionID != PochvenRegionID {
+			return ComponentDetached
+		}
+	}
+	return ComponentPochven
+}
@@ -0,0 +1,68 @@
+package transformer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestAddConnectivityColumns(t *testing.T) {
+	// Known space 1-2-3, Pochven 10-11, a detached pair 20-21 and a
+	// wormhole system 30 without gates
+	systems := []models.SolarSystem{
+		{SolarSystemID: 1, RegionID: 100},
+		{SolarSystemID: 2, RegionID: 100},
+		{SolarSystemID: 3, RegionID: 100},
+		{SolarSystemID: 10, RegionID: PochvenRegionID},
+		{SolarSystemID: 11, RegionID: PochvenRegionID},
+		{SolarSystemID: 20, RegionID: 200},
+		{SolarSystemID: 21, RegionID: 200},
+		{SolarSystemID: 30, RegionID: 11000001},
+	}
+	var jumps []models.SystemJump
+	for _, pair := range [][2]int64{{1, 2}, {2, 3}, {10, 11}, {21, 20}} {
+		jumps = append(jumps, models.SystemJump{FromSolarSystemID: pair[0], ToSolarSystemID: pair[1]})
+	}
+
+	dataset := AddConnectivityColumns(systems, graph.New(systems, jumps))
+	if dataset.Name != GateComponentsDataset {
+		t.Errorf("Expected dataset %q, got %q", GateComponentsDataset, dataset.Name)
+	}
+
+	expectedRows := [][]interface{}{
+		{int64(1), ComponentMain, int64(3), []int64{1, 2, 3}},
+		{int64(10), ComponentPochven, int64(2), []int64{10, 11}},
+		{int64(20), ComponentDetached, int64(2), []int64{20, 21}},
+		{int64(30), ComponentIsolated, int64(1), []int64{30}},
+	}
+	if !reflect.DeepEqual(dataset.Rows, expectedRows) {
+		t.Errorf("Expected rows %v, got %v", expectedRows, dataset.Rows)
+	}
+
+	tests := []struct {
+		id            int64
+		wantComponent int64
+		wantReachable bool
+	}{
+		{1, 1, true},
+		{3, 1, true},
+		{11, 10, false},
+		{21, 20, false},
+		{30, 30, false},
+	}
+	byID := make(map[int64]models.SolarSystem)
+	for _, sys := range systems {
+		byID[sys.SolarSystemID] = sys
+	}
+	for _, tt := range tests {
+		sys := byID[tt.id]
+		if sys.ComponentID == nil || *sys.ComponentID != tt.wantComponent {
+			t.Errorf("System %d: expected componentID %d, got %v", tt.id, tt.wantComponent, sys.ComponentID)
+		}
+		if sys.GateReachable == nil || *sys.GateReachable != tt.wantReachable {
+			t.Errorf("System %d: expected gateReachable %v, got %v", tt.id, tt.wantReachable, sys.GateReachable)
+		}
+	}
+}
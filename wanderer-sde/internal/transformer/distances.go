@@ -0,0 +1,75 @@
+package transformer
+
+import (
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// SystemDistancesDataset is the name of the anchor jump count table.
+const SystemDistancesDataset = "systemDistances"
+
+// DefaultDistanceAnchors are the trade hubs systemDistances measures from
+// when no anchors are configured.
+var DefaultDistanceAnchors = []string{"Jita", "Amarr", "Dodixie", "Rens", "Hek"}
+
+// GenerateSystemDistances lists the number of gate jumps from every system
+// to each anchor, ordered by system and then by anchor as given. Systems
+// with no gate route to an anchor have no row for it.
+func GenerateSystemDistances(g *graph.Graph, anchors []int64) (models.Dataset, error) {
+	dataset := models.Dataset{
+		Name:    SystemDistancesDataset,
+		Columns: []string{"solarSystemID", "anchorSystemID", "jumps"},
+		Keys:    []string{"solarSystemID"},
+		Locations: map[string]models.LocationKind{
+			"solarSystemID": models.LocationSystem,
+		},
+		Rows: make([][]interface{}, 0),
+	}
+
+	counts := make([][]int, len(anchors))
+	for i, anchor := range anchors {
+		dist, err := g.JumpCounts(anchor)
+		if err != nil {
+			return models.Dataset{}, err
+		}
+		counts[i] = dist
+	}
+
+	for n, sys := range g.Systems() {
+		for i, anchor := range anchors {
+			if jumps := counts[i][n]; jumps >= 0 {
+				dataset.Rows = append(dataset.Rows, []interface{}{sys.ID, anchor, int64(jumps)})
+			}
+		}
+	}
+	return dataset, nil
+}
+
+// distanceAnchors resolves the configured anchors, or the default trade hubs
+// present in the graph when none are configured. Configured anchors must
+// exist.
+func (t *Transformer) distanceAnchors(g *graph.Graph) ([]int64, error) {
+	names := t.config.DistanceAnchors
+	strict := len(names) > 0
+	if !strict {
+		names = DefaultDistanceAnchors
+	}
+
+	anchors := make([]int64, 0, len(names))
+	seen := make(map[int64]bool, len(names))
+	for _, name := range names {
+		sys, err := g.Lookup(name)
+		if err != nil {
+			if strict {
+				return nil, err
+			}
+			t.logger.Warn("skipping default distance anchor", "system", name)
+			continue
+		}
+		if !seen[sys.ID] {
+			seen[sys.ID] = true
+			anchors = append(anchors, sys.ID)
+		}
+	}
+	return anchors, nil
+}
@@ -0,0 +1,95 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// distanceGraph is Jita - Perimeter - Urlen with an unconnected Amarr.
+func distanceGraph() *graph.Graph {
+	systems := []models.SolarSystem{
+		{SolarSystemID: 30000142, SolarSystemName: "Jita"},
+		{SolarSystemID: 30000144, SolarSystemName: "Perimeter"},
+		{SolarSystemID: 30000145, SolarSystemName: "Urlen"},
+		{SolarSystemID: 30002187, SolarSystemName: "Amarr"},
+	}
+	jumps := []models.SystemJump{
+		{FromSolarSystemID: 30000142, ToSolarSystemID: 30000144},
+		{FromSolarSystemID: 30000144, ToSolarSystemID: 30000145},
+	}
+	return graph.New(systems, jumps)
+}
+
+func TestGenerateSystemDistances(t *testing.T) {
+	dataset, err := GenerateSystemDistances(distanceGraph(), []int64{30000142, 30002187})
+	if err != nil {
+		t.Fatalf("GenerateSystemDistances failed: %v", err)
+	}
+	if dataset.Name != SystemDistancesDataset {
+		t.Errorf("Expected dataset %q, got %q", SystemDistancesDataset, dataset.Name)
+	}
+
+	expected := [][]interface{}{
+		{int64(30000142), int64(30000142), int64(0)},
+		{int64(30000144), int64(30000142), int64(1)},
+		{int64(30000145), int64(30000142), int64(2)},
+		{int64(30002187), int64(30002187), int64(0)},
+	}
+	if len(dataset.Rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %v", len(expected), dataset.Rows)
+	}
+	for i, row := range dataset.Rows {
+		for j := range row {
+			if row[j] != expected[i][j] {
+				t.Errorf("Row %d: expected %v, got %v", i, expected[i], row)
+				break
+			}
+		}
+	}
+
+	if _, err := GenerateSystemDistances(distanceGraph(), []int64{99}); err == nil {
+		t.Error("Expected an error for an unknown anchor")
+	}
+}
+
+func TestDistanceAnchors(t *testing.T) {
+	g := distanceGraph()
+
+	tests := []struct {
+		name    string
+		anchors []string
+		want    []int64
+		wantErr bool
+	}{
+		{"defaults present in the graph", nil, []int64{30000142, 30002187}, false},
+		{"configured by name and ID", []string{"urlen", "30000142", "Urlen"}, []int64{30000145, 30000142}, false},
+		{"unknown configured anchor", []string{"Jita", "Hek"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := New(&config.Config{DistanceAnchors: tt.anchors}, nil)
+			got, err := tr.distanceAnchors(g)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("distanceAnchors failed: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Expected %v, got %v", tt.want, got)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package transformer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+// SystemEffectNames lists the wormhole environmental effects, using the
+// names from Wanderer's effects.json.
+var SystemEffectNames = []string{
+	"Black Hole",
+	"Cataclysmic Variable",
+	"Magnetar",
+	"Pulsar",
+	"Red Giant",
+	"Wolf-Rayet",
+}
+
+// normalizeEffectName lowercases a name and strips spaces and hyphens so
+// "Wolf Rayet Effect Beacon Class 3" matches "Wolf-Rayet".
+func normalizeEffectName(name string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToLower(name))
+}
+
+// EffectNameFromBeacon returns the effect name for an effect beacon type
+// name such as "Pulsar Effect Beacon Class 3".
+func EffectNameFromBeacon(beaconName string) (string, bool) {
+	normalized := normalizeEffectName(beaconName)
+	for _, name := range SystemEffectNames {
+		if strings.HasPrefix(normalized, normalizeEffectName(name)) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// GenerateSystemEffects derives each wormhole system's environmental effect
+// from its secondary sun's effect beacon type. Beacon types are specific to
+// the wormhole class, so their dogma attributes are the class-scaled
+// modifiers. Secondary suns whose beacon is not a known effect are skipped.
+func GenerateSystemEffects(
+	systems []models.SolarSystem,
+	systemClasses map[int64]int64,
+	secondarySuns map[int64]parser.SDEMapSecondarySun,
+	types map[int64]models.SDEType,
+	dogma map[int64]models.SDETypeDogma,
+	attributes map[int64]models.SDEDogmaAttribute,
+) []models.SystemEffect {
+	systemNames := make(map[int64]string, len(systems))
+	for _, sys := range systems {
+		systemNames[sys.SolarSystemID] = sys.SolarSystemName
+	}
+
+	result := make([]models.SystemEffect, 0)
+	for _, sun := range secondarySuns {
+		if sun.EffectBeaconTypeID == 0 {
+			continue
+		}
+		name, ok := systemNames[sun.SolarSystemID]
+		if !ok {
+			continue
+		}
+		effect, ok := EffectNameFromBeacon(types[sun.EffectBeaconTypeID].Name["en"])
+		if !ok {
+			continue
+		}
+
+		attrs := dogma[sun.EffectBeaconTypeID].DogmaAttributes
+		modifiers := make([]models.EffectModifier, 0, len(attrs))
+		for _, attr := range attrs {
+			modifiers = append(modifiers, models.EffectModifier{
+				AttributeID: attr.AttributeID,
+				Name:        attributeName(attributes, attr.AttributeID),
+				Value:       attr.Value,
+			})
+		}
+		sort.Slice(modifiers, func(i, j int) bool {
+			return modifiers[i].AttributeID < modifiers[j].AttributeID
+		})
+
+		result = append(result, models.SystemEffect{
+			SolarSystemID:   sun.SolarSystemID,
+			SolarSystemName: name,
+			WormholeClassID: systemClasses[sun.SolarSystemID],
+			Effect:          effect,
+			BeaconTypeID:    sun.EffectBeaconTypeID,
+			Modifiers:       modifiers,
+		})
+	}
+
+	// Sort by system ID for consistent output
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SolarSystemID < result[j].SolarSystemID
+	})
+
+	return result
+}
+
+// attributeName returns the display name of a dogma attribute, falling back
+// to its internal name and then its ID.
+func attributeName(attributes map[int64]models.SDEDogmaAttribute, attributeID int64) string {
+	attr, ok := attributes[attributeID]
+	if !ok {
+		return fmt.Sprintf("attribute %d", attributeID)
+	}
+	if name := attr.DisplayName["en"]; name != "" {
+		return name
+	}
+	if attr.Name != "" {
+		return attr.Name
+	}
+	return fmt.Sprintf("attribute %d", attributeID)
+}
+
+// CompareSystemEffects checks generated effects against the passthrough
+// wormholeSystems.json and effects.json in passthroughDir and returns a
+// description of every disagreement. Missing passthrough files are skipped.
+// Both files are read loosely since only the system ID and effect name are
+// compared.
+func CompareSystemEffects(effects []models.SystemEffect, passthroughDir string) []string {
+	var findings []string
+
+	generated := make(map[int64]models.SystemEffect, len(effects))
+	for _, e := range effects {
+		generated[e.SolarSystemID] = e
+	}
+
+	// wormholeSystems.json: per-system effect assignments
+	entries, err := readPassthroughArray(filepath.Join(passthroughDir, "wormholeSystems.json"))
+	if err != nil {
+		findings = append(findings, err.Error())
+	}
+	seen := make(map[int64]bool, len(entries))
+	for _, entry := range entries {
+		systemID, ok := entryInt(entry, "solarSystemID", "id")
+		if !ok {
+			continue
+		}
+		seen[systemID] = true
+		passthroughEffect, _ := entryString(entry, "effect", "effectName")
+
+		gen, hasGenerated := generated[systemID]
+		switch {
+		case hasGenerated && passthroughEffect == "":
+			findings = append(findings, fmt.Sprintf("System %d (%s): SDE effect %q missing from passthrough",
+				systemID, gen.SolarSystemName, gen.Effect))
+		case !hasGenerated && passthroughEffect != "":
+			findings = append(findings, fmt.Sprintf("System %d: passthrough effect %q not found in SDE",
+				systemID, passthroughEffect))
+		case hasGenerated && normalizeEffectName(passthroughEffect) != normalizeEffectName(gen.Effect):
+			findings = append(findings, fmt.Sprintf("System %d (%s): SDE effect %q, passthrough %q",
+				systemID, gen.SolarSystemName, gen.Effect, passthroughEffect))
+		}
+	}
+	if entries != nil {
+		for _, e := range effects {
+			if !seen[e.SolarSystemID] {
+				findings = append(findings, fmt.Sprintf("System %d (%s): SDE effect %q missing from passthrough",
+					e.SolarSystemID, e.SolarSystemName, e.Effect))
+			}
+		}
+	}
+
+	// effects.json: effect definitions by name
+	definitions, err := readPassthroughArray(filepath.Join(passthroughDir, "effects.json"))
+	if err != nil {
+		findings = append(findings, err.Error())
+	}
+	if definitions != nil {
+		known := make(map[string]bool, len(definitions))
+		for _, def := range definitions {
+			if name, ok := entryString(def, "name", "id"); ok {
+				known[normalizeEffectName(name)] = true
+			}
+		}
+		reported := make(map[string]bool)
+		for _, e := range effects {
+			if !known[normalizeEffectName(e.Effect)] && !reported[e.Effect] {
+				findings = append(findings, fmt.Sprintf("Effect %q missing from passthrough effects.json", e.Effect))
+				reported[e.Effect] = true
+			}
+		}
+	}
+
+	return findings
+}
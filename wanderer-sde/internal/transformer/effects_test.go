@@ -0,0 +1,165 @@
+package transformer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+func TestEffectNameFromBeacon(t *testing.T) {
+	tests := []struct {
+		beacon   string
+		expected string
+		ok       bool
+	}{
+		{"Pulsar Effect Beacon Class 3", "Pulsar", true},
+		{"Wolf Rayet Effect Beacon Class 6", "Wolf-Rayet", true},
+		{"Black Hole Effect Beacon Class 1", "Black Hole", true},
+		{"Cataclysmic Variable Effect Beacon Class 4", "Cataclysmic Variable", true},
+		{"Red Giant Beacon Class 2", "Red Giant", true},
+		{"Magnetar Effect Beacon Class 5", "Magnetar", true},
+		{"Incursion Effect Beacon", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		name, ok := EffectNameFromBeacon(tt.beacon)
+		if name != tt.expected || ok != tt.ok {
+			t.Errorf("EffectNameFromBeacon(%q) = (%q, %v), expected (%q, %v)",
+				tt.beacon, name, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func effectTestInputs() ([]models.SolarSystem, map[int64]parser.SDEMapSecondarySun, map[int64]models.SDEType, map[int64]models.SDETypeDogma) {
+	systems := []models.SolarSystem{
+		{SolarSystemID: 31000001, SolarSystemName: "J123456", ConstellationID: 21000001, RegionID: 11000001},
+		{SolarSystemID: 31000002, SolarSystemName: "J234567", ConstellationID: 21000001, RegionID: 11000001},
+		{SolarSystemID: 31000003, SolarSystemName: "J345678", ConstellationID: 21000001, RegionID: 11000001},
+	}
+	suns := map[int64]parser.SDEMapSecondarySun{
+		40000101: {SolarSystemID: 31000001, TypeID: 45031, EffectBeaconTypeID: 30844},
+		40000102: {SolarSystemID: 31000002, TypeID: 45032, EffectBeaconTypeID: 30850},
+		40000103: {SolarSystemID: 39999999, TypeID: 45031, EffectBeaconTypeID: 30844}, // unknown system
+	}
+	types := map[int64]models.SDEType{
+		30844: {GroupID: 920, Name: map[string]string{"en": "Pulsar Effect Beacon Class 3"}},
+		30850: {GroupID: 920, Name: map[string]string{"en": "Wolf Rayet Effect Beacon Class 3"}},
+	}
+	dogma := map[int64]models.SDETypeDogma{
+		30844: {DogmaAttributes: []models.SDEDogmaAttributeValue{
+			{AttributeID: 1495, Value: 1.5},
+			{AttributeID: 1477, Value: 0.85},
+		}},
+	}
+	return systems, suns, types, dogma
+}
+
+func TestGenerateSystemEffects(t *testing.T) {
+	systems, suns, types, dogma := effectTestInputs()
+	attributes := map[int64]models.SDEDogmaAttribute{
+		1495: {Name: "shieldCapacityMultiplier", DisplayName: map[string]string{"en": "Shield HP"}},
+		1477: {Name: "signatureRadiusMultiplier"},
+	}
+	classes := map[int64]int64{31000001: 3, 31000002: 3}
+
+	result := GenerateSystemEffects(systems, classes, suns, types, dogma, attributes)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 system effects, got %d", len(result))
+	}
+
+	pulsar := result[0]
+	if pulsar.SolarSystemID != 31000001 || pulsar.Effect != "Pulsar" || pulsar.WormholeClassID != 3 {
+		t.Errorf("Unexpected pulsar effect: %+v", pulsar)
+	}
+	if len(pulsar.Modifiers) != 2 {
+		t.Fatalf("Expected 2 pulsar modifiers, got %d", len(pulsar.Modifiers))
+	}
+	// Modifiers sorted by attribute ID; names fall back from display name to internal name
+	if pulsar.Modifiers[0].AttributeID != 1477 || pulsar.Modifiers[0].Name != "signatureRadiusMultiplier" {
+		t.Errorf("Unexpected first modifier: %+v", pulsar.Modifiers[0])
+	}
+	if pulsar.Modifiers[1].Name != "Shield HP" || pulsar.Modifiers[1].Value != 1.5 {
+		t.Errorf("Unexpected second modifier: %+v", pulsar.Modifiers[1])
+	}
+
+	wolfRayet := result[1]
+	if wolfRayet.Effect != "Wolf-Rayet" {
+		t.Errorf("Expected Wolf-Rayet, got %q", wolfRayet.Effect)
+	}
+	if wolfRayet.Modifiers == nil || len(wolfRayet.Modifiers) != 0 {
+		t.Errorf("Expected empty (non-nil) modifiers without dogma, got %v", wolfRayet.Modifiers)
+	}
+}
+
+func TestCompareSystemEffects(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "effects_passthrough")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	wormholeSystems := `[
+  {"id": 31000001, "effect": "Pulsar"},
+  {"id": 31000002, "effect": "Magnetar"},
+  {"id": 31000003, "effect": "Black Hole"},
+  {"id": 31000004, "effect": null}
+]`
+	effectsJSON := `[{"name": "Pulsar"}, {"name": "Magnetar"}]`
+	if err := os.WriteFile(filepath.Join(tmpDir, "wormholeSystems.json"), []byte(wormholeSystems), 0644); err != nil {
+		t.Fatalf("failed to write wormholeSystems.json: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "effects.json"), []byte(effectsJSON), 0644); err != nil {
+		t.Fatalf("failed to write effects.json: %v", err)
+	}
+
+	effects := []models.SystemEffect{
+		{SolarSystemID: 31000001, SolarSystemName: "J123456", Effect: "Pulsar"},
+		{SolarSystemID: 31000002, SolarSystemName: "J234567", Effect: "Wolf-Rayet"},
+		{SolarSystemID: 31000005, SolarSystemName: "J567890", Effect: "Pulsar"},
+	}
+
+	findings := CompareSystemEffects(effects, tmpDir)
+
+	expected := []string{
+		`System 31000002 (J234567): SDE effect "Wolf-Rayet", passthrough "Magnetar"`,
+		`System 31000003: passthrough effect "Black Hole" not found in SDE`,
+		`System 31000005 (J567890): SDE effect "Pulsar" missing from passthrough`,
+		`Effect "Wolf-Rayet" missing from passthrough effects.json`,
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %d: %v", len(expected), len(findings), findings)
+	}
+	for i, exp := range expected {
+		if findings[i] != exp {
+			t.Errorf("Finding %d: expected %q, got %q", i, exp, findings[i])
+		}
+	}
+}
+
+func TestCompareSystemEffects_MissingAndMalformed(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "effects_passthrough")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	effects := []models.SystemEffect{{SolarSystemID: 31000001, Effect: "Pulsar"}}
+
+	if findings := CompareSystemEffects(effects, tmpDir); len(findings) != 0 {
+		t.Errorf("Expected no findings without passthrough files, got %v", findings)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "effects.json"), []byte(`[{"name": "Pulsar",}]`), 0644); err != nil {
+		t.Fatalf("failed to write effects.json: %v", err)
+	}
+	findings := CompareSystemEffects(effects, tmpDir)
+	if len(findings) != 1 || !strings.Contains(findings[0], "failed to parse effects.json") {
+		t.Errorf("Expected a parse failure finding, got %v", findings)
+	}
+}
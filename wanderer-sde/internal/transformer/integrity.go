@@ -0,0 +1,191 @@
+package transformer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// CheckIntegrity cross-checks the references between converted tables and
+// returns a finding for every broken reference or duplicate ID. Records with
+// a zero ID are treated as unset and not checked for duplicates. References
+// into an empty table are not checked, since the minimum count checks
+// already report the missing table.
+func CheckIntegrity(data *models.ConvertedData) []models.Finding {
+	var findings []models.Finding
+	add := func(code models.FindingCode, severity models.Severity, table string, id int64, format string, args ...interface{}) {
+		findings = append(findings, models.Finding{
+			Code:     code,
+			Severity: severity,
+			Table:    table,
+			ID:       strconv.FormatInt(id, 10),
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	universe := data.Universe
+	if universe == nil {
+		universe = &models.UniverseData{}
+	}
+
+	// Duplicate IDs, and lookups for the reference checks
+	regions := make(map[int64]bool, len(universe.Regions))
+	for _, r := range universe.Regions {
+		if regions[r.RegionID] && r.RegionID != 0 {
+			add(models.CodeDuplicateID, models.SeverityError, "mapRegions", r.RegionID,
+				"Region %d appears more than once", r.RegionID)
+		}
+		regions[r.RegionID] = true
+	}
+
+	constellations := make(map[int64]models.Constellation, len(universe.Constellations))
+	for _, c := range universe.Constellations {
+		if _, dup := constellations[c.ConstellationID]; dup && c.ConstellationID != 0 {
+			add(models.CodeDuplicateID, models.SeverityError, "mapConstellations", c.ConstellationID,
+				"Constellation %d appears more than once", c.ConstellationID)
+		}
+		constellations[c.ConstellationID] = c
+	}
+
+	systems := make(map[int64]bool, len(universe.SolarSystems))
+	for _, sys := range universe.SolarSystems {
+		if systems[sys.SolarSystemID] && sys.SolarSystemID != 0 {
+			add(models.CodeDuplicateID, models.SeverityError, "mapSolarSystems", sys.SolarSystemID,
+				"Solar system %d appears more than once", sys.SolarSystemID)
+		}
+		systems[sys.SolarSystemID] = true
+	}
+
+	groups := make(map[int64]bool, len(data.InvGroups))
+	for _, g := range data.InvGroups {
+		if groups[g.GroupID] && g.GroupID != 0 {
+			add(models.CodeDuplicateID, models.SeverityError, "invGroups", g.GroupID,
+				"Group %d appears more than once", g.GroupID)
+		}
+		groups[g.GroupID] = true
+	}
+
+	types := make(map[int64]bool, len(data.InvTypes))
+	for _, typ := range data.InvTypes {
+		if types[typ.TypeID] && typ.TypeID != 0 {
+			add(models.CodeDuplicateID, models.SeverityError, "invTypes", typ.TypeID,
+				"Type %d appears more than once", typ.TypeID)
+		}
+		types[typ.TypeID] = true
+	}
+
+	stations := make(map[int64]bool, len(data.NPCStations))
+	for _, st := range data.NPCStations {
+		if stations[st.StationID] && st.StationID != 0 {
+			add(models.CodeDuplicateID, models.SeverityError, "npcStations", st.StationID,
+				"Station %d appears more than once", st.StationID)
+		}
+		stations[st.StationID] = true
+	}
+
+	classLocations := make(map[int64]bool, len(data.WormholeClasses))
+	for _, wc := range data.WormholeClasses {
+		if classLocations[wc.LocationID] && wc.LocationID != 0 {
+			add(models.CodeDuplicateID, models.SeverityError, "mapLocationWormholeClasses", wc.LocationID,
+				"Wormhole class location %d appears more than once", wc.LocationID)
+		}
+		classLocations[wc.LocationID] = true
+	}
+
+	// Every constellation's region exists
+	for _, c := range universe.Constellations {
+		if len(regions) > 0 && !regions[c.RegionID] {
+			add(models.CodeConstellationUnknownRegion, models.SeverityError, "mapConstellations", c.ConstellationID,
+				"Constellation %d (%s) references unknown region %d", c.ConstellationID, c.ConstellationName, c.RegionID)
+		}
+	}
+
+	// Every system's constellation exists and belongs to the system's region
+	for _, sys := range universe.SolarSystems {
+		c, ok := constellations[sys.ConstellationID]
+		switch {
+		case len(constellations) == 0:
+		case !ok:
+			add(models.CodeSystemUnknownConstellation, models.SeverityError, "mapSolarSystems", sys.SolarSystemID,
+				"Solar system %d (%s) references unknown constellation %d",
+				sys.SolarSystemID, sys.SolarSystemName, sys.ConstellationID)
+		case c.RegionID != sys.RegionID:
+			add(models.CodeSystemRegionMismatch, models.SeverityError, "mapSolarSystems", sys.SolarSystemID,
+				"Solar system %d (%s) is in region %d but its constellation %d is in region %d",
+				sys.SolarSystemID, sys.SolarSystemName, sys.RegionID, sys.ConstellationID, c.RegionID)
+		}
+	}
+
+	// Every jump endpoint exists, and every jump has a reverse jump
+	jumps := make(map[[2]int64]bool, len(data.SystemJumps))
+	for _, j := range data.SystemJumps {
+		jumps[[2]int64{j.FromSolarSystemID, j.ToSolarSystemID}] = true
+	}
+	for _, j := range data.SystemJumps {
+		for _, endpoint := range []int64{j.FromSolarSystemID, j.ToSolarSystemID} {
+			if len(systems) > 0 && !systems[endpoint] {
+				add(models.CodeJumpUnknownSystem, models.SeverityError, "mapSolarSystemJumps", j.FromSolarSystemID,
+					"Jump %d -> %d references unknown solar system %d",
+					j.FromSolarSystemID, j.ToSolarSystemID, endpoint)
+			}
+		}
+		if !jumps[[2]int64{j.ToSolarSystemID, j.FromSolarSystemID}] {
+			add(models.CodeJumpAsymmetric, models.SeverityWarning, "mapSolarSystemJumps", j.FromSolarSystemID,
+				"Jump %d -> %d has no reverse jump", j.FromSolarSystemID, j.ToSolarSystemID)
+		}
+	}
+
+	// Every station's system exists
+	for _, st := range data.NPCStations {
+		if len(systems) > 0 && !systems[st.SolarSystemID] {
+			add(models.CodeStationUnknownSystem, models.SeverityError, "npcStations", st.StationID,
+				"Station %d references unknown solar system %d", st.StationID, st.SolarSystemID)
+		}
+	}
+
+	// Every type's group exists
+	for _, typ := range data.InvTypes {
+		if len(groups) > 0 && !groups[typ.GroupID] {
+			add(models.CodeTypeUnknownGroup, models.SeverityError, "invTypes", typ.TypeID,
+				"Type %d (%s) references unknown group %d", typ.TypeID, typ.TypeName, typ.GroupID)
+		}
+	}
+
+	// Every wormhole class location is a region, constellation or system
+	for _, wc := range data.WormholeClasses {
+		_, isConstellation := constellations[wc.LocationID]
+		if len(systems) > 0 && !regions[wc.LocationID] && !isConstellation && !systems[wc.LocationID] {
+			add(models.CodeWormholeClassUnknownLocation, models.SeverityWarning, "mapLocationWormholeClasses", wc.LocationID,
+				"Wormhole class %d assigned to unknown location %d", wc.WormholeClassID, wc.LocationID)
+		}
+	}
+
+	return findings
+}
+
+// addFindings records findings in result and summarizes them in Errors and
+// Warnings, capping each code at maxReportedFindings messages.
+func addFindings(result *models.ValidationResult, findings []models.Finding) {
+	result.Findings = append(result.Findings, findings...)
+
+	var codes []models.FindingCode
+	bySeverity := make(map[models.FindingCode]models.Severity)
+	messages := make(map[models.FindingCode][]string)
+	for _, f := range findings {
+		if _, seen := messages[f.Code]; !seen {
+			codes = append(codes, f.Code)
+			bySeverity[f.Code] = f.Severity
+		}
+		messages[f.Code] = append(messages[f.Code], fmt.Sprintf("[%s] %s", f.Code, f.Message))
+	}
+
+	for _, code := range codes {
+		switch bySeverity[code] {
+		case models.SeverityError:
+			result.Errors = appendFindings(result.Errors, messages[code])
+		case models.SeverityWarning:
+			result.Warnings = appendFindings(result.Warnings, messages[code])
+		}
+	}
+}
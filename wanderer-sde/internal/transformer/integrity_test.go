@@ -0,0 +1,168 @@
+package transformer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func integrityTestData() *models.ConvertedData {
+	return &models.ConvertedData{
+		Universe: &models.UniverseData{
+			Regions:        []models.Region{{RegionID: 10000002}},
+			Constellations: []models.Constellation{{ConstellationID: 20000020, RegionID: 10000002}},
+			SolarSystems: []models.SolarSystem{
+				{SolarSystemID: 30000142, ConstellationID: 20000020, RegionID: 10000002, SolarSystemName: "Jita"},
+				{SolarSystemID: 30000144, ConstellationID: 20000020, RegionID: 10000002, SolarSystemName: "Perimeter"},
+			},
+		},
+		InvTypes:  []models.InvType{{TypeID: 587, GroupID: 25, TypeName: "Rifter"}},
+		InvGroups: []models.InvGroup{{GroupID: 25}},
+		SystemJumps: []models.SystemJump{
+			{FromSolarSystemID: 30000142, ToSolarSystemID: 30000144},
+			{FromSolarSystemID: 30000144, ToSolarSystemID: 30000142},
+		},
+		NPCStations:     []models.NPCStation{{StationID: 60003760, SolarSystemID: 30000142}},
+		WormholeClasses: []models.WormholeClassLocation{{LocationID: 10000002, WormholeClassID: 7}},
+	}
+}
+
+func TestCheckIntegrity_Clean(t *testing.T) {
+	if findings := CheckIntegrity(integrityTestData()); len(findings) != 0 {
+		t.Errorf("Expected no findings, got %+v", findings)
+	}
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(d *models.ConvertedData)
+		code     models.FindingCode
+		severity models.Severity
+		table    string
+		id       string
+		count    int // Expected number of findings when more than one
+	}{
+		{
+			name: "unknown jump endpoint",
+			mutate: func(d *models.ConvertedData) {
+				d.SystemJumps = append(d.SystemJumps,
+					models.SystemJump{FromSolarSystemID: 30000142, ToSolarSystemID: 30009999},
+					models.SystemJump{FromSolarSystemID: 30009999, ToSolarSystemID: 30000142})
+			},
+			code: models.CodeJumpUnknownSystem, severity: models.SeverityError, table: "mapSolarSystemJumps", id: "30000142",
+			count: 2, // One per direction
+		},
+		{
+			name: "asymmetric jump",
+			mutate: func(d *models.ConvertedData) {
+				d.SystemJumps = d.SystemJumps[:1]
+			},
+			code: models.CodeJumpAsymmetric, severity: models.SeverityWarning, table: "mapSolarSystemJumps", id: "30000142",
+		},
+		{
+			name: "unknown constellation",
+			mutate: func(d *models.ConvertedData) {
+				d.Universe.SolarSystems[1].ConstellationID = 20000999
+			},
+			code: models.CodeSystemUnknownConstellation, severity: models.SeverityError, table: "mapSolarSystems", id: "30000144",
+		},
+		{
+			name: "constellation in another region",
+			mutate: func(d *models.ConvertedData) {
+				d.Universe.SolarSystems[0].RegionID = 10000001
+			},
+			code: models.CodeSystemRegionMismatch, severity: models.SeverityError, table: "mapSolarSystems", id: "30000142",
+		},
+		{
+			name: "constellation with unknown region",
+			mutate: func(d *models.ConvertedData) {
+				d.Universe.Constellations = append(d.Universe.Constellations,
+					models.Constellation{ConstellationID: 20000021, RegionID: 10000999})
+			},
+			code: models.CodeConstellationUnknownRegion, severity: models.SeverityError, table: "mapConstellations", id: "20000021",
+		},
+		{
+			name: "station in unknown system",
+			mutate: func(d *models.ConvertedData) {
+				d.NPCStations[0].SolarSystemID = 30009999
+			},
+			code: models.CodeStationUnknownSystem, severity: models.SeverityError, table: "npcStations", id: "60003760",
+		},
+		{
+			name: "type with unknown group",
+			mutate: func(d *models.ConvertedData) {
+				d.InvTypes[0].GroupID = 26
+			},
+			code: models.CodeTypeUnknownGroup, severity: models.SeverityError, table: "invTypes", id: "587",
+		},
+		{
+			name: "wormhole class for unknown location",
+			mutate: func(d *models.ConvertedData) {
+				d.WormholeClasses = append(d.WormholeClasses, models.WormholeClassLocation{LocationID: 31009999, WormholeClassID: 3})
+			},
+			code: models.CodeWormholeClassUnknownLocation, severity: models.SeverityWarning, table: "mapLocationWormholeClasses", id: "31009999",
+		},
+		{
+			name: "duplicate system ID",
+			mutate: func(d *models.ConvertedData) {
+				d.Universe.SolarSystems = append(d.Universe.SolarSystems, d.Universe.SolarSystems[0])
+			},
+			code: models.CodeDuplicateID, severity: models.SeverityError, table: "mapSolarSystems", id: "30000142",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := integrityTestData()
+			tt.mutate(data)
+
+			count := tt.count
+			if count == 0 {
+				count = 1
+			}
+			findings := CheckIntegrity(data)
+			if len(findings) != count {
+				t.Fatalf("Expected %d findings, got %d: %+v", count, len(findings), findings)
+			}
+			f := findings[0]
+			if f.Code != tt.code || f.Severity != tt.severity || f.Table != tt.table || f.ID != tt.id {
+				t.Errorf("Unexpected finding: %+v", f)
+			}
+			if f.Message == "" {
+				t.Error("Expected a message")
+			}
+		})
+	}
+}
+
+func TestAddFindings(t *testing.T) {
+	result := &models.ValidationResult{}
+	findings := []models.Finding{
+		{Code: models.CodeJumpAsymmetric, Severity: models.SeverityWarning, Message: "one-way"},
+		{Code: models.CodeDuplicateID, Severity: models.SeverityError, Message: "dup"},
+	}
+	for i := 0; i < maxReportedFindings+2; i++ {
+		findings = append(findings, models.Finding{Code: models.CodeStationUnknownSystem, Severity: models.SeverityError, Message: "missing"})
+	}
+
+	addFindings(result, findings)
+
+	if len(result.Findings) != len(findings) {
+		t.Errorf("Expected all %d findings to be kept, got %d", len(findings), len(result.Findings))
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != "[JUMP_ASYMMETRIC] one-way" {
+		t.Errorf("Unexpected warnings: %v", result.Warnings)
+	}
+	// 1 duplicate + 10 station messages + 1 summary
+	if len(result.Errors) != 1+maxReportedFindings+1 {
+		t.Fatalf("Expected %d errors, got %d: %v", maxReportedFindings+2, len(result.Errors), result.Errors)
+	}
+	if !strings.Contains(result.Errors[len(result.Errors)-1], "and 2 more") {
+		t.Errorf("Expected summary of remaining findings, got %q", result.Errors[len(result.Errors)-1])
+	}
+	if result.IsValid() {
+		t.Error("Expected error findings to make the result invalid")
+	}
+}
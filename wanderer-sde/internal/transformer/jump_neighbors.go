@@ -0,0 +1,50 @@
+package transformer
+
+import (
+	"context"
+	"math"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// JumpNeighborsDataset is the name of the precomputed jump neighbour table.
+const JumpNeighborsDataset = "mapJumpNeighbors"
+
+// GenerateJumpNeighbors lists every pair of systems a jump drive can cross
+// within rangeLY, one row per direction allowed (jumps into high security
+// space are not), ordered by origin and then distance. Distances are
+// rounded to a thousandth of a light year.
+func GenerateJumpNeighbors(ctx context.Context, systems []models.SolarSystem, rangeLY float64) (models.Dataset, error) {
+	dataset := models.Dataset{
+		Name:    JumpNeighborsDataset,
+		Columns: []string{"fromSolarSystemID", "toSolarSystemID", "distanceLY"},
+		Keys:    []string{"fromSolarSystemID"},
+		Locations: map[string]models.LocationKind{
+			"fromSolarSystemID": models.LocationSystem,
+			"toSolarSystemID":   models.LocationSystem,
+		},
+		Rows: make([][]interface{}, 0),
+	}
+
+	g := graph.New(systems, nil)
+	for _, sys := range g.Systems() {
+		if err := ctx.Err(); err != nil {
+			return models.Dataset{}, err
+		}
+		if !graph.CanJumpFrom(sys) {
+			continue
+		}
+		targets, err := g.JumpNeighbors(sys.ID, rangeLY)
+		if err != nil {
+			return models.Dataset{}, err
+		}
+		for _, target := range targets {
+			dataset.Rows = append(dataset.Rows, []interface{}{
+				sys.ID, target.System.ID, math.Round(target.Distance*1000) / 1000,
+			})
+		}
+	}
+
+	return dataset, nil
+}
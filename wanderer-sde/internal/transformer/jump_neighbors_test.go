@@ -0,0 +1,52 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestGenerateJumpNeighbors(t *testing.T) {
+	ly := func(x float64) float64 { return x * graph.MetersPerLightYear }
+	systems := []models.SolarSystem{
+		{SolarSystemID: 30000001, Security: 0.8, X: ly(0)},                      // High security
+		{SolarSystemID: 30000002, Security: 0.3, X: ly(2)},                      // Low security
+		{SolarSystemID: 30000003, Security: -0.2, X: ly(5.5)},                   // Null security
+		{SolarSystemID: 30000004, Security: 0.1, X: ly(20)},                     // Out of range
+		{SolarSystemID: 31000001, Security: -1.0, X: ly(1)},                     // Wormhole space
+		{SolarSystemID: 30000005, Security: -1.0, X: ly(3), RegionID: 10000070}, // Pochven
+	}
+
+	dataset, err := GenerateJumpNeighbors(context.Background(), systems, 5)
+	if err != nil {
+		t.Fatalf("GenerateJumpNeighbors failed: %v", err)
+	}
+	if dataset.Name != JumpNeighborsDataset {
+		t.Errorf("Expected dataset %q, got %q", JumpNeighborsDataset, dataset.Name)
+	}
+
+	expected := [][]interface{}{
+		{int64(30000001), int64(30000002), 2.0},
+		{int64(30000002), int64(30000003), 3.5},
+		{int64(30000003), int64(30000002), 3.5},
+	}
+	if len(dataset.Rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %v", len(expected), dataset.Rows)
+	}
+	for i, row := range dataset.Rows {
+		for j := range row {
+			if row[j] != expected[i][j] {
+				t.Errorf("Row %d: expected %v, got %v", i, expected[i], row)
+				break
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GenerateJumpNeighbors(ctx, systems, 5); err == nil {
+		t.Error("Expected an error for a cancelled context")
+	}
+}
@@ -0,0 +1,100 @@
+package transformer
+
+import (
+	"sort"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// Region and constellation adjacency table names.
+const (
+	RegionJumpsDataset        = "mapRegionJumps"
+	ConstellationJumpsDataset = "mapConstellationJumps"
+)
+
+// GenerateRegionJumps lists every pair of neighbouring regions, once in
+// each direction, with the number of stargates between them and the border
+// systems on each side. Jumps must be enriched with region IDs.
+func GenerateRegionJumps(jumps []models.SystemJump) models.Dataset {
+	return locationJumps(RegionJumpsDataset, "RegionID", models.LocationRegion, jumps, func(j models.SystemJump) (int64, int64) {
+		return j.FromRegionID, j.ToRegionID
+	})
+}
+
+// GenerateConstellationJumps lists every pair of neighbouring
+// constellations like GenerateRegionJumps.
+func GenerateConstellationJumps(jumps []models.SystemJump) models.Dataset {
+	return locationJumps(ConstellationJumpsDataset, "ConstellationID", models.LocationConstellation, jumps, func(j models.SystemJump) (int64, int64) {
+		return j.FromConstellationID, j.ToConstellationID
+	})
+}
+
+// locationPair is an ordered pair of neighbouring locations.
+type locationPair struct {
+	from, to int64
+}
+
+// locationJumps aggregates the jumps crossing between locations, keyed by
+// the location IDs locate returns, which are of the given kind. Jumps within one location or touching a
+// system of unknown location are skipped. Rows are ordered by from and then
+// to location, and border systems by ID.
+func locationJumps(name, idColumn string, kind models.LocationKind, jumps []models.SystemJump, locate func(models.SystemJump) (int64, int64)) models.Dataset {
+	dataset := models.Dataset{
+		Name:    name,
+		Columns: []string{"from" + idColumn, "to" + idColumn, "gates", "fromBorderSystemIDs", "toBorderSystemIDs"},
+		Keys:    []string{"from" + idColumn, "to" + idColumn},
+		Locations: map[string]models.LocationKind{
+			"from" + idColumn:     kind,
+			"to" + idColumn:       kind,
+			"fromBorderSystemIDs": models.LocationSystemList,
+			"toBorderSystemIDs":   models.LocationSystemList,
+		},
+		Rows: make([][]interface{}, 0),
+	}
+
+	gates := make(map[locationPair]int64)
+	fromSystems := make(map[locationPair]map[int64]bool)
+	toSystems := make(map[locationPair]map[int64]bool)
+	for _, j := range jumps {
+		from, to := locate(j)
+		if from == to || from == 0 || to == 0 {
+			continue
+		}
+		pair := locationPair{from, to}
+		if gates[pair] == 0 {
+			fromSystems[pair] = make(map[int64]bool)
+			toSystems[pair] = make(map[int64]bool)
+		}
+		gates[pair]++
+		fromSystems[pair][j.FromSolarSystemID] = true
+		toSystems[pair][j.ToSolarSystemID] = true
+	}
+
+	pairs := make([]locationPair, 0, len(gates))
+	for pair := range gates {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].from != pairs[j].from {
+			return pairs[i].from < pairs[j].from
+		}
+		return pairs[i].to < pairs[j].to
+	})
+
+	for _, pair := range pairs {
+		dataset.Rows = append(dataset.Rows, []interface{}{
+			pair.from, pair.to, gates[pair], sortedIDs(fromSystems[pair]), sortedIDs(toSystems[pair]),
+		})
+	}
+	return dataset
+}
+
+// sortedIDs returns the IDs of a set in ascending order.
+func sortedIDs(set map[int64]bool) []int64 {
+	ids := make([]int64, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
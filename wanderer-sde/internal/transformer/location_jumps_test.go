@@ -0,0 +1,64 @@
+package transformer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestGenerateRegionJumps(t *testing.T) {
+	// Region 100 (systems 1, 2) borders region 200 (systems 3, 4) through
+	// two gates, 1-3 and 2-3; 4 is joined to 3 inside region 200
+	jump := func(from, to int64, fromRegion, toRegion int64) []models.SystemJump {
+		return []models.SystemJump{
+			{FromSolarSystemID: from, ToSolarSystemID: to, FromRegionID: fromRegion, ToRegionID: toRegion},
+			{FromSolarSystemID: to, ToSolarSystemID: from, FromRegionID: toRegion, ToRegionID: fromRegion},
+		}
+	}
+	var jumps []models.SystemJump
+	jumps = append(jumps, jump(1, 3, 100, 200)...)
+	jumps = append(jumps, jump(2, 3, 100, 200)...)
+	jumps = append(jumps, jump(3, 4, 200, 200)...)
+	jumps = append(jumps, jump(4, 99, 200, 0)...) // Unknown system
+
+	dataset := GenerateRegionJumps(jumps)
+	if dataset.Name != RegionJumpsDataset {
+		t.Errorf("Expected dataset %q, got %q", RegionJumpsDataset, dataset.Name)
+	}
+	if want := []string{"fromRegionID", "toRegionID", "gates", "fromBorderSystemIDs", "toBorderSystemIDs"}; !reflect.DeepEqual(dataset.Columns, want) {
+		t.Errorf("Expected columns %v, got %v", want, dataset.Columns)
+	}
+
+	expected := [][]interface{}{
+		{int64(100), int64(200), int64(2), []int64{1, 2}, []int64{3}},
+		{int64(200), int64(100), int64(2), []int64{3}, []int64{1, 2}},
+	}
+	if !reflect.DeepEqual(dataset.Rows, expected) {
+		t.Errorf("Expected rows %v, got %v", expected, dataset.Rows)
+	}
+}
+
+func TestGenerateConstellationJumps(t *testing.T) {
+	jumps := []models.SystemJump{
+		{FromSolarSystemID: 1, ToSolarSystemID: 2, FromConstellationID: 10, ToConstellationID: 11, FromRegionID: 100, ToRegionID: 100},
+		{FromSolarSystemID: 2, ToSolarSystemID: 1, FromConstellationID: 11, ToConstellationID: 10, FromRegionID: 100, ToRegionID: 100},
+	}
+
+	dataset := GenerateConstellationJumps(jumps)
+	if dataset.Name != ConstellationJumpsDataset || dataset.Columns[0] != "fromConstellationID" {
+		t.Errorf("Unexpected dataset %q with columns %v", dataset.Name, dataset.Columns)
+	}
+	expected := [][]interface{}{
+		{int64(10), int64(11), int64(1), []int64{1}, []int64{2}},
+		{int64(11), int64(10), int64(1), []int64{2}, []int64{1}},
+	}
+	if !reflect.DeepEqual(dataset.Rows, expected) {
+		t.Errorf("Expected rows %v, got %v", expected, dataset.Rows)
+	}
+
+	// Regions do not border each other here
+	if rows := GenerateRegionJumps(jumps).Rows; len(rows) != 0 {
+		t.Errorf("Expected no region pairs, got %v", rows)
+	}
+}
@@ -0,0 +1,169 @@
+package transformer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+// NearestStationsDataset is the name of the nearest station table.
+const NearestStationsDataset = "nearestStations"
+
+// StationFilter selects NPC stations. A station matches when it matches
+// every non-empty list, and a list when the station matches any entry.
+// An empty filter matches the stations of blue loot buyers.
+type StationFilter struct {
+	Owners   []string // Owner corporations, by name (case-insensitive) or ID
+	Services []string // Station services, by name (case-insensitive) or ID
+	Factions []int64  // Factions of the owner corporation
+}
+
+// IsEmpty reports whether the filter has no criteria.
+func (f StationFilter) IsEmpty() bool {
+	return len(f.Owners) == 0 && len(f.Services) == 0 && len(f.Factions) == 0
+}
+
+// SelectStations returns the NPC stations matching a filter. Services are
+// looked up through stationOperations.yaml and stationServices.yaml, so a
+// service filter fails when they were not parsed. Unknown corporations and
+// services fail too.
+func SelectStations(parse *parser.ParseResult, filter StationFilter) (map[int64]models.SDENPCStation, error) {
+	if filter.IsEmpty() {
+		return parser.FilterBlueLootStations(parse.AllNPCStations), nil
+	}
+
+	owners := make(map[int64]bool, len(filter.Owners))
+	for _, value := range filter.Owners {
+		id, err := matchName(value, "corporation", parse.NPCCorporations, func(c models.SDENPCCorporation) string {
+			return c.Name["en"]
+		})
+		if err != nil {
+			return nil, err
+		}
+		owners[id] = true
+	}
+
+	services := make(map[int64]bool, len(filter.Services))
+	if len(filter.Services) > 0 && (parse.Operations == nil || parse.Services == nil) {
+		return nil, fmt.Errorf("station service filter needs stationOperations.yaml and stationServices.yaml in the SDE")
+	}
+	for _, value := range filter.Services {
+		id, err := matchName(value, "station service", parse.Services, func(s models.SDEStationService) string {
+			return s.ServiceName["en"]
+		})
+		if err != nil {
+			return nil, err
+		}
+		services[id] = true
+	}
+
+	factions := make(map[int64]bool, len(filter.Factions))
+	for _, id := range filter.Factions {
+		factions[id] = true
+	}
+
+	selected := make(map[int64]models.SDENPCStation)
+	for id, station := range parse.AllNPCStations {
+		if len(owners) > 0 && !owners[station.OwnerID] {
+			continue
+		}
+		if len(factions) > 0 && !factions[parse.NPCCorporations[station.OwnerID].FactionID] {
+			continue
+		}
+		if len(services) > 0 && !offersAny(parse.Operations[station.OperationID], services) {
+			continue
+		}
+		selected[id] = station
+	}
+	return selected, nil
+}
+
+// offersAny reports whether a station operation offers any of the services.
+func offersAny(operation models.SDEStationOperation, services map[int64]bool) bool {
+	for _, id := range operation.Services {
+		if services[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// matchName returns the ID of the record whose ID or English name is value.
+func matchName[T any](value, kind string, records map[int64]T, name func(T) string) (int64, error) {
+	if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if _, ok := records[id]; ok {
+			return id, nil
+		}
+	}
+	// Map order is random; take the lowest ID when names collide
+	found := int64(-1)
+	for id, record := range records {
+		if strings.EqualFold(name(record), value) && (found < 0 || id < found) {
+			found = id
+		}
+	}
+	if found < 0 {
+		return 0, fmt.Errorf("unknown %s %q", kind, value)
+	}
+	return found, nil
+}
+
+// GenerateNearestStations lists for every system the nearest of the given
+// stations by gate jumps, ordered by system. Stations must be sorted by ID;
+// in a system with several, the lowest ID stands for all of them. Systems
+// with no gate route to any station have no row.
+func GenerateNearestStations(g *graph.Graph, stations []models.NPCStation) (models.Dataset, error) {
+	dataset := models.Dataset{
+		Name:    NearestStationsDataset,
+		Columns: []string{"fromSolarSystemID", "toSolarSystemID", "stationID", "ownerID", "jumps"},
+		Keys:    []string{"fromSolarSystemID"},
+		Locations: map[string]models.LocationKind{
+			"fromSolarSystemID": models.LocationSystem,
+			"toSolarSystemID":   models.LocationSystem,
+		},
+		Rows: make([][]interface{}, 0),
+	}
+
+	bySystem := make(map[int64]models.NPCStation)
+	var sources []int64
+	for _, st := range stations {
+		if _, ok := g.System(st.SolarSystemID); !ok {
+			continue
+		}
+		if _, ok := bySystem[st.SolarSystemID]; !ok {
+			bySystem[st.SolarSystemID] = st
+			sources = append(sources, st.SolarSystemID)
+		}
+	}
+	if len(sources) == 0 {
+		return dataset, nil
+	}
+
+	nearest, jumps, err := g.NearestSources(sources...)
+	if err != nil {
+		return models.Dataset{}, err
+	}
+	for n, sys := range g.Systems() {
+		if jumps[n] < 0 {
+			continue
+		}
+		st := bySystem[nearest[n]]
+		dataset.Rows = append(dataset.Rows, []interface{}{
+			sys.ID, st.SolarSystemID, st.StationID, st.OwnerID, int64(jumps[n]),
+		})
+	}
+	return dataset, nil
+}
+
+// stationFilter returns the configured nearest station filter.
+func (t *Transformer) stationFilter() StationFilter {
+	return StationFilter{
+		Owners:   t.config.NearestStationOwners,
+		Services: t.config.NearestStationServices,
+		Factions: t.config.NearestStationFactions,
+	}
+}
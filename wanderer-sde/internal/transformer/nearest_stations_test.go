@@ -0,0 +1,114 @@
+package transformer
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+func TestGenerateNearestStations(t *testing.T) {
+	// Two stations in Jita, one in Urlen and one outside the graph
+	stations := []models.NPCStation{
+		{StationID: 60003760, SolarSystemID: 30000142, OwnerID: 1000035},
+		{StationID: 60003761, SolarSystemID: 30000142, OwnerID: 1000035},
+		{StationID: 60012736, SolarSystemID: 30000145, OwnerID: 1000137},
+		{StationID: 60099999, SolarSystemID: 99},
+	}
+
+	dataset, err := GenerateNearestStations(distanceGraph(), stations)
+	if err != nil {
+		t.Fatalf("GenerateNearestStations failed: %v", err)
+	}
+	if dataset.Name != NearestStationsDataset {
+		t.Errorf("Expected dataset %q, got %q", NearestStationsDataset, dataset.Name)
+	}
+
+	// Perimeter is one jump from both; Jita has the lower ID. Amarr has no
+	// gates and no station.
+	expected := [][]interface{}{
+		{int64(30000142), int64(30000142), int64(60003760), int64(1000035), int64(0)},
+		{int64(30000144), int64(30000142), int64(60003760), int64(1000035), int64(1)},
+		{int64(30000145), int64(30000145), int64(60012736), int64(1000137), int64(0)},
+	}
+	assertRows(t, dataset.Rows, expected)
+
+	empty, err := GenerateNearestStations(distanceGraph(), nil)
+	if err != nil || len(empty.Rows) != 0 {
+		t.Errorf("Expected no rows without stations, got %v (%v)", empty.Rows, err)
+	}
+}
+
+func TestSelectStations(t *testing.T) {
+	parse := &parser.ParseResult{
+		AllNPCStations: map[int64]models.SDENPCStation{
+			60003760: {OwnerID: 1000035, SolarSystemID: 30000142, OperationID: 26},
+			60012736: {OwnerID: 1000137, SolarSystemID: 30000142, OperationID: 14},
+			60000001: {OwnerID: 1000002, SolarSystemID: 30000001, OperationID: 14},
+		},
+		NPCCorporations: map[int64]models.SDENPCCorporation{
+			1000035: {Name: map[string]string{"en": "Caldari Navy"}, FactionID: 500001},
+			1000137: {Name: map[string]string{"en": "DED"}, FactionID: 500004},
+			1000002: {Name: map[string]string{"en": "CBD Corporation"}, FactionID: 500001},
+		},
+		Operations: map[int64]models.SDEStationOperation{
+			14: {Services: []int64{5, 16}},
+			26: {Services: []int64{16}},
+		},
+		Services: map[int64]models.SDEStationService{
+			5:  {ServiceName: map[string]string{"en": "Bounty Missions"}},
+			16: {ServiceName: map[string]string{"en": "Cloning"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		filter  StationFilter
+		want    []int64
+		wantErr bool
+	}{
+		{"default blue loot buyers", StationFilter{}, []int64{60012736}, false},
+		{"owner by name", StationFilter{Owners: []string{"caldari navy"}}, []int64{60003760}, false},
+		{"owner by ID", StationFilter{Owners: []string{"1000002"}}, []int64{60000001}, false},
+		{"faction", StationFilter{Factions: []int64{500001}}, []int64{60000001, 60003760}, false},
+		{"service by name", StationFilter{Services: []string{"Bounty Missions"}}, []int64{60000001, 60012736}, false},
+		{"faction and service", StationFilter{Factions: []int64{500001}, Services: []string{"5"}}, []int64{60000001}, false},
+		{"unknown owner", StationFilter{Owners: []string{"Nobody"}}, nil, true},
+		{"unknown service", StationFilter{Services: []string{"Market"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := SelectStations(parse, tt.filter)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %v", selected)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectStations failed: %v", err)
+			}
+			got := make([]int64, 0, len(selected))
+			for id := range selected {
+				got = append(got, id)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected stations %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Expected stations %v, got %v", tt.want, got)
+					break
+				}
+			}
+		})
+	}
+
+	// Service filters need the station operation files
+	parse.Operations = nil
+	if _, err := SelectStations(parse, StationFilter{Services: []string{"Cloning"}}); err == nil {
+		t.Error("Expected an error without stationOperations.yaml")
+	}
+}
@@ -0,0 +1,92 @@
+package transformer
+
+import (
+	"fmt"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/overrides"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+// sourceOverrideTables are the patchable tables other stages derive data
+// from: space kinds, bounds, jump enrichment, region and constellation
+// jumps, distances, chokepoints, connectivity and nearest stations. Their
+// patches are applied to the parsed SDE data before any stage runs.
+var sourceOverrideTables = map[string]bool{
+	"regions":         true,
+	"constellations":  true,
+	"solarSystems":    true,
+	"wormholeClasses": true,
+	"systemJumps":     true,
+}
+
+// AppliedOverrides returns the override patches the last Transform call
+// applied: patches to source tables first, then the rest, each in file
+// order.
+func (t *Transformer) AppliedOverrides() []overrides.AppliedPatch {
+	return t.applied
+}
+
+// loadOverrides reads the configured override patches and splits them into
+// patches to source tables and patches to the other tables.
+func (t *Transformer) loadOverrides() (source, rest []overrides.Patch, err error) {
+	if t.config.OverridesDir == "" {
+		return nil, nil, nil
+	}
+	patches, err := overrides.Load(t.config.OverridesDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load overrides: %w", err)
+	}
+	for _, p := range patches {
+		if sourceOverrideTables[p.Table] {
+			source = append(source, p)
+		} else {
+			rest = append(rest, p)
+		}
+	}
+	return source, rest, nil
+}
+
+// overrideParseResult returns a copy of parsed SDE data with patches to
+// source tables applied. The original is left unchanged.
+func (t *Transformer) overrideParseResult(parse *parser.ParseResult, patches []overrides.Patch) *parser.ParseResult {
+	if len(patches) == 0 {
+		return parse
+	}
+
+	data := &models.ConvertedData{
+		Universe: &models.UniverseData{
+			Regions:        append([]models.Region(nil), parse.Regions...),
+			Constellations: append([]models.Constellation(nil), parse.Constellations...),
+			SolarSystems:   append([]models.SolarSystem(nil), parse.SolarSystems...),
+		},
+		WormholeClasses: append([]models.WormholeClassLocation(nil), parse.WormholeClasses...),
+		SystemJumps:     append([]models.SystemJump(nil), parse.SystemJumps...),
+	}
+	t.applyOverrides(data, patches)
+
+	patched := *parse
+	patched.Regions = data.Universe.Regions
+	patched.Constellations = data.Universe.Constellations
+	patched.SolarSystems = data.Universe.SolarSystems
+	patched.WormholeClasses = data.WormholeClasses
+	patched.SystemJumps = data.SystemJumps
+	return &patched
+}
+
+// applyOverrides applies patches to data, recording those that took effect
+// and reporting the others as warnings.
+func (t *Transformer) applyOverrides(data *models.ConvertedData, patches []overrides.Patch) {
+	if len(patches) == 0 {
+		return
+	}
+	applied, warnings := overrides.Apply(data, patches)
+	for _, warning := range warnings {
+		t.logger.Warn("override not applied", "reason", warning)
+		t.warnings = append(t.warnings, "Override not applied: "+warning)
+	}
+	for _, a := range applied {
+		t.logger.Debug("applied override", "source", a.Source, "op", a.Op, "table", a.Table, "id", a.ID)
+	}
+	t.applied = append(t.applied, applied...)
+}
@@ -0,0 +1,99 @@
+package transformer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+func TestTransformer_Overrides(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "transform_overrides_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	patches := `- table: solarSystems
+  id: 30000001
+  op: set
+  fields:
+    security: -0.3
+    regionID: 10000002
+- table: npcStations
+  id: 60000001
+  op: add
+  fields:
+    solarSystemID: 30000142
+- table: solarSystems
+  id: 30009999
+  op: remove
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "patches.yaml"), []byte(patches), 0644); err != nil {
+		t.Fatalf("failed to write patches: %v", err)
+	}
+
+	parseResult := &parser.ParseResult{
+		Regions: []models.Region{
+			{RegionID: 10000001, RegionName: "Derelik"},
+			{RegionID: 10000002, RegionName: "The Forge"},
+		},
+		SolarSystems: []models.SolarSystem{
+			{SolarSystemID: 30000001, SolarSystemName: "Tanoo", RegionID: 10000001, Security: 0.047},
+			{SolarSystemID: 30000142, SolarSystemName: "Jita", RegionID: 10000002, Security: 0.9459},
+		},
+		SystemJumps: []models.SystemJump{
+			{FromSolarSystemID: 30000142, ToSolarSystemID: 30000001},
+		},
+	}
+
+	tr := New(&config.Config{OverridesDir: tmpDir}, nil)
+	data, err := tr.Transform(context.Background(), parseResult)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	// Stages deriving data from solar systems see the patched values
+	tanoo := data.Universe.SolarSystems[0]
+	if tanoo.Security != -0.3 || tanoo.SpaceKind != SpaceNullSec {
+		t.Errorf("Expected patched Tanoo to be nullsec, got security %v and %q", tanoo.Security, tanoo.SpaceKind)
+	}
+	if jump := data.SystemJumps[0]; jump.ToRegionID != 10000002 {
+		t.Errorf("Expected the jump to be enriched with the patched region, got %d", jump.ToRegionID)
+	}
+	for _, dataset := range data.Datasets {
+		if dataset.Name == RegionJumpsDataset && len(dataset.Rows) != 0 {
+			t.Errorf("Expected no region jumps once both systems are in one region, got %v", dataset.Rows)
+		}
+	}
+
+	// Other tables are patched after the stages
+	if len(data.NPCStations) != 1 || data.NPCStations[0].StationID != 60000001 {
+		t.Errorf("Expected the added station, got %+v", data.NPCStations)
+	}
+
+	if applied := tr.AppliedOverrides(); len(applied) != 2 {
+		t.Errorf("Expected 2 applied overrides, got %+v", applied)
+	}
+	if warnings := tr.Validate(data).Warnings; !containsPrefix(warnings, "Override not applied: ") {
+		t.Errorf("Expected a warning for the stale patch, got %v", warnings)
+	}
+
+	if parseResult.SolarSystems[0].Security != 0.047 {
+		t.Error("Expected the parsed data to be left unchanged")
+	}
+}
+
+func containsPrefix(list []string, prefix string) bool {
+	for _, s := range list {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
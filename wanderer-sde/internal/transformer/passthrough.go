@@ -0,0 +1,105 @@
+package transformer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// readPassthroughArray reads a passthrough JSON file holding an array of
+// objects. It returns nil without error if the file does not exist.
+func readPassthroughArray(path string) ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return entries, nil
+}
+
+// entryInt returns the first of keys holding a JSON number.
+func entryInt(entry map[string]interface{}, keys ...string) (int64, bool) {
+	for _, key := range keys {
+		if v, ok := entry[key].(float64); ok {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+// entryString returns the first of keys holding a non-empty JSON string.
+func entryString(entry map[string]interface{}, keys ...string) (string, bool) {
+	for _, key := range keys {
+		if v, ok := entry[key].(string); ok && v != "" {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// readPassthroughIDs reads the IDs from a passthrough JSON file holding
+// either an array of numbers or an array of objects, using the first of
+// keys present in each object. found is false if the file does not exist.
+func readPassthroughIDs(path string, keys ...string) (ids []int64, found bool, err error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	var raw []interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, true, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	for _, item := range raw {
+		switch v := item.(type) {
+		case float64:
+			ids = append(ids, int64(v))
+		case map[string]interface{}:
+			if id, ok := entryInt(v, keys...); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, true, nil
+}
+
+// diffIDs returns the IDs present only in generated (added) and only in
+// passthrough (removed), both sorted.
+func diffIDs(generated, passthrough []int64) (added, removed []int64) {
+	inGenerated := make(map[int64]bool, len(generated))
+	for _, id := range generated {
+		inGenerated[id] = true
+	}
+	inPassthrough := make(map[int64]bool, len(passthrough))
+	for _, id := range passthrough {
+		inPassthrough[id] = true
+	}
+
+	for id := range inGenerated {
+		if !inPassthrough[id] {
+			added = append(added, id)
+		}
+	}
+	for id := range inPassthrough {
+		if !inGenerated[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	sort.Slice(added, func(i, j int) bool { return added[i] < added[j] })
+	sort.Slice(removed, func(i, j int) bool { return removed[i] < removed[j] })
+	return added, removed
+}
@@ -0,0 +1,66 @@
+package transformer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadPassthroughIDs(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "passthrough_ids")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	tests := []struct {
+		name    string
+		content string
+		ids     []int64
+		wantErr bool
+	}{
+		{"bare numbers", `[3, 1, 2]`, []int64{3, 1, 2}, false},
+		{"objects with fallback keys", `[{"id": 5}, {"constellationID": 6}, {"other": 7}]`, []int64{5, 6}, false},
+		{"malformed", `[1, 2,]`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, "ids.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+
+			ids, found, err := readPassthroughIDs(path, "constellationID", "id")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readPassthroughIDs error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !found {
+				t.Error("Expected found to be true for an existing file")
+			}
+			if !tt.wantErr && !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("Expected %v, got %v", tt.ids, ids)
+			}
+		})
+	}
+
+	if _, found, err := readPassthroughIDs(filepath.Join(tmpDir, "missing.json"), "id"); found || err != nil {
+		t.Errorf("Expected missing file to be not found without error, got found=%v err=%v", found, err)
+	}
+}
+
+func TestDiffIDs(t *testing.T) {
+	added, removed := diffIDs([]int64{4, 1, 2, 2}, []int64{2, 3, 5})
+	if !reflect.DeepEqual(added, []int64{1, 4}) {
+		t.Errorf("Expected added [1 4], got %v", added)
+	}
+	if !reflect.DeepEqual(removed, []int64{3, 5}) {
+		t.Errorf("Expected removed [3 5], got %v", removed)
+	}
+
+	added, removed = diffIDs([]int64{1}, []int64{1})
+	if added != nil || removed != nil {
+		t.Errorf("Expected no differences, got added=%v removed=%v", added, removed)
+	}
+}
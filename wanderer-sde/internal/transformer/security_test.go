@@ -3,6 +3,8 @@ package transformer
 import (
 	"math"
 	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
 )
 
 func TestGetTrueSecurity(t *testing.T) {
@@ -95,3 +97,74 @@ func TestRoundSecurity(t *testing.T) {
 		}
 	}
 }
+
+func TestDisplaySecurity(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected string
+	}{
+		{0.9459, "0.9"},
+		{0.5, "0.5"},
+		{0.047, "0.1"},
+		{0.0, "0.0"},
+		{-0.04, "0.0"}, // Rounds to -0.0, shown without sign
+		{-0.45, "-0.5"},
+		{-0.99, "-1.0"},
+	}
+
+	for _, tt := range tests {
+		if got := DisplaySecurity(tt.input); got != tt.expected {
+			t.Errorf("DisplaySecurity(%v) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestSecurityBand(t *testing.T) {
+	tests := []struct {
+		name     string
+		systemID int64
+		security float64
+		expected string
+	}{
+		{"high", 30000142, 0.9459, SecurityBandHigh},
+		{"high after rounding", 30000001, 0.45, SecurityBandHigh},
+		{"low", 30000001, 0.44, SecurityBandLow},
+		{"low near zero", 30000001, 0.01, SecurityBandLow},
+		{"null", 30000001, -0.2, SecurityBandNull},
+		{"zero is null", 30000001, 0.0, SecurityBandNull},
+		{"wormhole", 31000001, -0.99, SecurityBandWormhole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SecurityBand(tt.systemID, tt.security); got != tt.expected {
+				t.Errorf("SecurityBand(%d, %v) = %q, expected %q", tt.systemID, tt.security, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddSecurityColumns(t *testing.T) {
+	systems := []models.SolarSystem{
+		{SolarSystemID: 30000142, Security: 0.9459},
+		{SolarSystemID: 30000001, Security: -0.04},
+	}
+
+	AddSecurityColumns(systems)
+
+	if systems[0].Security != 0.9459 {
+		t.Errorf("Expected raw security to be unchanged, got %v", systems[0].Security)
+	}
+	if systems[0].TrueSecurity == nil || *systems[0].TrueSecurity != 0.9 {
+		t.Errorf("Expected trueSecurity 0.9, got %v", systems[0].TrueSecurity)
+	}
+	if systems[0].DisplaySecurity != "0.9" || systems[0].SecurityBand != SecurityBandHigh {
+		t.Errorf("Unexpected derived fields: %q %q", systems[0].DisplaySecurity, systems[0].SecurityBand)
+	}
+	if systems[1].TrueSecurity == nil || math.Signbit(*systems[1].TrueSecurity) {
+		t.Errorf("Expected trueSecurity +0.0, got %v", systems[1].TrueSecurity)
+	}
+	if systems[1].SecurityBand != SecurityBandNull {
+		t.Errorf("Expected null band, got %q", systems[1].SecurityBand)
+	}
+}
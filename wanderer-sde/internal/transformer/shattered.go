@@ -0,0 +1,77 @@
+package transformer
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// shatteredSystemName matches the J0xxxxx names CCP gives shattered
+// wormhole systems, which otherwise carry ordinary C1-C6 classes.
+var shatteredSystemName = regexp.MustCompile(`^J0\d{5}$`)
+
+// IsShatteredSystem reports whether a solar system is a shattered wormhole
+// system: either the small-ship shattered class (C13), or a J-space system
+// with a regular class and a shattered J0xxxxx name.
+func IsShatteredSystem(sys models.SolarSystem, classID int64) bool {
+	if classID == WormholeClassShattered {
+		return true
+	}
+	return IsJSpaceSystemID(sys.SolarSystemID) &&
+		classID >= 1 && classID <= 6 &&
+		shatteredSystemName.MatchString(sys.SolarSystemName)
+}
+
+// GenerateShatteredConstellations lists constellations made up entirely of
+// shattered wormhole systems.
+func GenerateShatteredConstellations(
+	systems []models.SolarSystem,
+	constellations []models.Constellation,
+	systemClasses map[int64]int64,
+) []models.ShatteredConstellation {
+	members := make(map[int64][]int64)
+	disqualified := make(map[int64]bool)
+	for _, sys := range systems {
+		if IsShatteredSystem(sys, systemClasses[sys.SolarSystemID]) {
+			members[sys.ConstellationID] = append(members[sys.ConstellationID], sys.SolarSystemID)
+		} else {
+			disqualified[sys.ConstellationID] = true
+		}
+	}
+
+	result := make([]models.ShatteredConstellation, 0)
+	for _, c := range constellations {
+		systemIDs := members[c.ConstellationID]
+		if len(systemIDs) == 0 || disqualified[c.ConstellationID] {
+			continue
+		}
+		sort.Slice(systemIDs, func(i, j int) bool { return systemIDs[i] < systemIDs[j] })
+
+		result = append(result, models.ShatteredConstellation{
+			ConstellationID:   c.ConstellationID,
+			ConstellationName: c.ConstellationName,
+			RegionID:          c.RegionID,
+			SolarSystemIDs:    systemIDs,
+		})
+	}
+
+	// Sort by constellation ID for consistent output
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ConstellationID < result[j].ConstellationID
+	})
+
+	return result
+}
+
+// CompareShatteredConstellations diffs generated shattered constellations
+// against the passthrough shatteredConstellations.json in passthroughDir.
+func CompareShatteredConstellations(generated []models.ShatteredConstellation, passthroughDir string) []string {
+	ids := make([]int64, len(generated))
+	for i, c := range generated {
+		ids[i] = c.ConstellationID
+	}
+	return compareWithPassthrough(filepath.Join(passthroughDir, "shatteredConstellations.json"), ids,
+		"constellationID", "constellationId", "id")
+}
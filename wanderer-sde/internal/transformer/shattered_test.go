@@ -0,0 +1,67 @@
+package transformer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestIsShatteredSystem(t *testing.T) {
+	tests := []struct {
+		name     string
+		sys      models.SolarSystem
+		classID  int64
+		expected bool
+	}{
+		{"C13 class", models.SolarSystem{SolarSystemID: 31000010, SolarSystemName: "J010556"}, WormholeClassShattered, true},
+		{"J0 name with regular class", models.SolarSystem{SolarSystemID: 31002238, SolarSystemName: "J005299"}, 4, true},
+		{"regular J-space", models.SolarSystem{SolarSystemID: 31000001, SolarSystemName: "J123456"}, 3, false},
+		{"Thera", models.SolarSystem{SolarSystemID: 31000005, SolarSystemName: "Thera"}, WormholeClassThera, false},
+		{"J0 name outside J-space", models.SolarSystem{SolarSystemID: 30000001, SolarSystemName: "J012345"}, 4, false},
+		{"J0 name with drifter class", models.SolarSystem{SolarSystemID: 31000003, SolarSystemName: "J055520"}, 14, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsShatteredSystem(tt.sys, tt.classID); got != tt.expected {
+				t.Errorf("IsShatteredSystem() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateShatteredConstellations(t *testing.T) {
+	constellations := []models.Constellation{
+		{ConstellationID: 21000324, ConstellationName: "E-C00324", RegionID: 11000032},
+		{ConstellationID: 21000330, ConstellationName: "F-C00330", RegionID: 11000033},
+		{ConstellationID: 21000001, ConstellationName: "A-C00001", RegionID: 11000001},
+	}
+	systems := []models.SolarSystem{
+		{SolarSystemID: 31002240, SolarSystemName: "J005300", ConstellationID: 21000324},
+		{SolarSystemID: 31002238, SolarSystemName: "J005299", ConstellationID: 21000324},
+		{SolarSystemID: 31002500, SolarSystemName: "J010556", ConstellationID: 21000330},
+		{SolarSystemID: 31002501, SolarSystemName: "J100100", ConstellationID: 21000330}, // disqualifies F-C00330
+		{SolarSystemID: 31000001, SolarSystemName: "J123456", ConstellationID: 21000001},
+	}
+	classes := map[int64]int64{
+		31002240: 4,
+		31002238: 4,
+		31002500: WormholeClassShattered,
+		31002501: 3,
+		31000001: 3,
+	}
+
+	result := GenerateShatteredConstellations(systems, constellations, classes)
+
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 shattered constellation, got %d: %+v", len(result), result)
+	}
+	got := result[0]
+	if got.ConstellationID != 21000324 || got.ConstellationName != "E-C00324" || got.RegionID != 11000032 {
+		t.Errorf("Unexpected constellation: %+v", got)
+	}
+	if !reflect.DeepEqual(got.SolarSystemIDs, []int64{31002238, 31002240}) {
+		t.Errorf("Expected sorted member systems, got %v", got.SolarSystemIDs)
+	}
+}
@@ -0,0 +1,159 @@
+package transformer
+
+import (
+	"fmt"
+
+	"github.com/guarzo/wanderer-sde/internal/eve"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// Space kinds assigned to solar systems.
+const (
+	SpaceHighSec   = "highsec"
+	SpaceLowSec    = "lowsec"
+	SpaceNullSec   = "nullsec"
+	SpaceC1        = "c1"
+	SpaceC2        = "c2"
+	SpaceC3        = "c3"
+	SpaceC4        = "c4"
+	SpaceC5        = "c5"
+	SpaceC6        = "c6"
+	SpaceThera     = "thera"
+	SpaceShattered = "shattered" // Shattered C1-C6 (J0xxxxx systems)
+	SpaceC13       = "c13"
+	SpaceDrifter   = "drifter" // C14-C18 Drifter wormholes
+	SpacePochven   = "pochven"
+	SpaceAbyssal   = "abyssal"
+	SpaceJove      = "jove" // Unreachable Jove regions
+	SpaceVoid      = "void"
+)
+
+// SpaceKinds lists every space kind.
+var SpaceKinds = []string{
+	SpaceHighSec, SpaceLowSec, SpaceNullSec,
+	SpaceC1, SpaceC2, SpaceC3, SpaceC4, SpaceC5, SpaceC6,
+	SpaceThera, SpaceShattered, SpaceC13, SpaceDrifter,
+	SpacePochven, SpaceAbyssal, SpaceJove, SpaceVoid,
+}
+
+// Wormhole classes of the Drifter systems (Sentinel through Redoubt).
+const (
+	wormholeClassDrifterMin = 14
+	wormholeClassDrifterMax = 18
+)
+
+// Solar system and region ID ranges outside known and wormhole space.
+const (
+	abyssalSystemIDMin = 32000000
+	abyssalSystemIDMax = 32999999
+	abyssalRegionIDMin = 12000000
+	abyssalRegionIDMax = 12999999
+	voidRegionIDMin    = 14000000
+	voidRegionIDMax    = 14999999
+)
+
+// joveRegionIDs are the Jove regions, which have no gate connection to the
+// rest of New Eden.
+var joveRegionIDs = map[int64]bool{
+	10000004: true, // UUA-F4
+	10000017: true, // J7HZ-F
+	10000019: true, // A821-A
+}
+
+// wSpaceKinds maps the regular wormhole classes to their space kind.
+var wSpaceKinds = map[int64]string{
+	1: SpaceC1,
+	2: SpaceC2,
+	3: SpaceC3,
+	4: SpaceC4,
+	5: SpaceC5,
+	6: SpaceC6,
+}
+
+// ClassifySpace returns the space kind of a solar system given its resolved
+// wormhole class (0 if it has none), or an empty string if no rule applies.
+//
+// Rules are checked from most to least specific: Abyssal and void by ID
+// range, Pochven by region or class, wormhole space by class, Jove by
+// region, and remaining known space by displayed security status.
+func ClassifySpace(sys models.SolarSystem, classID int64) string {
+	switch {
+	case inRange(sys.SolarSystemID, abyssalSystemIDMin, abyssalSystemIDMax),
+		inRange(sys.RegionID, abyssalRegionIDMin, abyssalRegionIDMax):
+		return SpaceAbyssal
+	case inRange(sys.RegionID, voidRegionIDMin, voidRegionIDMax):
+		return SpaceVoid
+	case sys.RegionID == PochvenRegionID, classID == WormholeClassPochven:
+		return SpacePochven
+	}
+
+	if IsJSpaceSystemID(sys.SolarSystemID) {
+		switch {
+		case classID == WormholeClassThera:
+			return SpaceThera
+		case classID == WormholeClassShattered:
+			return SpaceC13
+		case classID >= wormholeClassDrifterMin && classID <= wormholeClassDrifterMax:
+			return SpaceDrifter
+		case IsShatteredSystem(sys, classID):
+			return SpaceShattered
+		}
+		return wSpaceKinds[classID]
+	}
+
+	if joveRegionIDs[sys.RegionID] {
+		return SpaceJove
+	}
+
+	if inRange(sys.SolarSystemID, eve.KSpaceSystemIDMin, eve.KSpaceSystemIDMax) {
+		security := GetTrueSecurity(sys.Security)
+		switch {
+		case security >= 0.5:
+			return SpaceHighSec
+		case security > 0.0:
+			return SpaceLowSec
+		default:
+			return SpaceNullSec
+		}
+	}
+
+	return ""
+}
+
+// ClassifySystems sets the space kind of every solar system and returns the
+// IDs of systems no rule could classify.
+func ClassifySystems(systems []models.SolarSystem, systemClasses map[int64]int64) []int64 {
+	unclassified := make([]int64, 0)
+	for i := range systems {
+		systems[i].SpaceKind = ClassifySpace(systems[i], systemClasses[systems[i].SolarSystemID])
+		if systems[i].SpaceKind == "" {
+			unclassified = append(unclassified, systems[i].SolarSystemID)
+		}
+	}
+	return unclassified
+}
+
+// validateSpaceKinds reports systems the space classifier could not label.
+func validateSpaceKinds(data *models.ConvertedData, result *models.ValidationResult) {
+	if len(data.UnclassifiedSystems) == 0 {
+		return
+	}
+
+	names := make(map[int64]string, len(data.Universe.SolarSystems))
+	for _, sys := range data.Universe.SolarSystems {
+		names[sys.SolarSystemID] = sys.SolarSystemName
+	}
+
+	findings := make([]string, 0, len(data.UnclassifiedSystems))
+	for _, systemID := range data.UnclassifiedSystems {
+		findings = append(findings,
+			fmt.Sprintf("Solar system %d (%s) has no space classification", systemID, names[systemID]))
+	}
+
+	result.Warnings = appendFindings(result.Warnings, findings)
+}
+
+// inRange reports whether id lies within [lo, hi].
+func inRange(id, lo, hi int64) bool {
+	return id >= lo && id <= hi
+}
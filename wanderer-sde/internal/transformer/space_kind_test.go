@@ -0,0 +1,91 @@
+package transformer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestClassifySpace(t *testing.T) {
+	tests := []struct {
+		name     string
+		system   models.SolarSystem
+		classID  int64
+		expected string
+	}{
+		{"highsec", models.SolarSystem{SolarSystemID: 30000142, RegionID: 10000002, Security: 0.9459}, WormholeClassHighSec, SpaceHighSec},
+		{"highsec rounds up from 0.45", models.SolarSystem{SolarSystemID: 30000001, RegionID: 10000001, Security: 0.45}, WormholeClassHighSec, SpaceHighSec},
+		{"lowsec", models.SolarSystem{SolarSystemID: 30000001, RegionID: 10000001, Security: 0.44}, WormholeClassLowSec, SpaceLowSec},
+		{"lowsec near zero", models.SolarSystem{SolarSystemID: 30000001, RegionID: 10000001, Security: 0.02}, WormholeClassLowSec, SpaceLowSec},
+		{"nullsec", models.SolarSystem{SolarSystemID: 30000001, RegionID: 10000001, Security: -0.3}, WormholeClassNullSec, SpaceNullSec},
+		{"nullsec without class", models.SolarSystem{SolarSystemID: 30000001, RegionID: 10000001, Security: 0.0}, 0, SpaceNullSec},
+		{"c1", models.SolarSystem{SolarSystemID: 31000001, SolarSystemName: "J100001", RegionID: 11000001}, 1, SpaceC1},
+		{"c6", models.SolarSystem{SolarSystemID: 31000002, SolarSystemName: "J100002", RegionID: 11000030}, 6, SpaceC6},
+		{"thera", models.SolarSystem{SolarSystemID: 31000005, SolarSystemName: "Thera", RegionID: 11000031}, WormholeClassThera, SpaceThera},
+		{"shattered", models.SolarSystem{SolarSystemID: 31002000, SolarSystemName: "J005299", RegionID: 11000032}, 4, SpaceShattered},
+		{"c13", models.SolarSystem{SolarSystemID: 31002500, SolarSystemName: "J010366", RegionID: 11000032}, WormholeClassShattered, SpaceC13},
+		{"drifter sentinel", models.SolarSystem{SolarSystemID: 31000001, SolarSystemName: "J055520", RegionID: 11000033}, 14, SpaceDrifter},
+		{"drifter redoubt", models.SolarSystem{SolarSystemID: 31000001, SolarSystemName: "J110145", RegionID: 11000033}, 18, SpaceDrifter},
+		{"pochven by region", models.SolarSystem{SolarSystemID: 30000021, RegionID: PochvenRegionID, Security: -1.0}, 0, SpacePochven},
+		{"pochven by class", models.SolarSystem{SolarSystemID: 30000021, RegionID: 10000001, Security: -1.0}, WormholeClassPochven, SpacePochven},
+		{"abyssal by system", models.SolarSystem{SolarSystemID: 32000001, RegionID: 12000001}, 0, SpaceAbyssal},
+		{"abyssal by region", models.SolarSystem{SolarSystemID: 30100000, RegionID: 12000005}, 0, SpaceAbyssal},
+		{"jove", models.SolarSystem{SolarSystemID: 30000380, RegionID: 10000017, Security: -1.0}, WormholeClassNullSec, SpaceJove},
+		{"void", models.SolarSystem{SolarSystemID: 34000001, RegionID: 14000001}, 0, SpaceVoid},
+		{"j-space without class", models.SolarSystem{SolarSystemID: 31000999, RegionID: 11000001}, 0, ""},
+		{"unknown id range", models.SolarSystem{SolarSystemID: 40000001, RegionID: 19000001}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifySpace(tt.system, tt.classID)
+			if got != tt.expected {
+				t.Errorf("ClassifySpace() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClassifySystems(t *testing.T) {
+	systems := []models.SolarSystem{
+		{SolarSystemID: 30000142, ConstellationID: 20000020, RegionID: 10000002, Security: 0.9459},
+		{SolarSystemID: 31000001, SolarSystemName: "J100001", ConstellationID: 21000001, RegionID: 11000001},
+		{SolarSystemID: 31000002, SolarSystemName: "J100002", ConstellationID: 21000002, RegionID: 11000001},
+		{SolarSystemID: 31000999, SolarSystemName: "J999999", ConstellationID: 21000999, RegionID: 11000999},
+	}
+	classes := []models.WormholeClassLocation{
+		{LocationID: 10000002, WormholeClassID: WormholeClassHighSec},
+		{LocationID: 11000001, WormholeClassID: 3}, // Region default
+		{LocationID: 21000002, WormholeClassID: 5}, // Constellation override
+	}
+
+	unclassified := ClassifySystems(systems, ResolveSystemWormholeClasses(systems, classes))
+
+	expected := []string{SpaceHighSec, SpaceC3, SpaceC5, ""}
+	for i, want := range expected {
+		if systems[i].SpaceKind != want {
+			t.Errorf("System %d: expected %q, got %q", systems[i].SolarSystemID, want, systems[i].SpaceKind)
+		}
+	}
+
+	if len(unclassified) != 1 || unclassified[0] != 31000999 {
+		t.Errorf("Expected unclassified [31000999], got %v", unclassified)
+	}
+}
+
+func TestValidateSpaceKinds(t *testing.T) {
+	data := &models.ConvertedData{
+		Universe: &models.UniverseData{
+			SolarSystems: []models.SolarSystem{{SolarSystemID: 31000999, SolarSystemName: "J999999"}},
+		},
+		UnclassifiedSystems: []int64{31000999},
+	}
+	result := &models.ValidationResult{}
+
+	validateSpaceKinds(data, result)
+
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "31000999 (J999999) has no space classification") {
+		t.Errorf("Expected unclassified system warning, got %v", result.Warnings)
+	}
+}
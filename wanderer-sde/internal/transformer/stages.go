@@ -0,0 +1,501 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guarzo/wanderer-sde/internal/graph"
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+// StageState is shared by the stages of one transform run. Parsed SDE data
+// is always available; everything else is filled in by the stages.
+type StageState struct {
+	Parse *parser.ParseResult
+	Data  *models.ConvertedData
+
+	// SystemClasses is each system's effective wormhole class.
+	SystemClasses map[int64]int64
+}
+
+// Stage is one step of the transform pipeline. Inputs and Outputs name the
+// datasets a stage reads and produces; a stage runs after the stages
+// producing its inputs. A dataset may also name an in-place update, e.g.
+// "spaceKinds" for the spaceKind field of the solar systems, so that later
+// stages can depend on it.
+type Stage struct {
+	Name     string
+	Inputs   []string
+	Outputs  []string
+	Optional bool // Only runs when enabled in config
+	Run      func(ctx context.Context, t *Transformer, state *StageState) error
+}
+
+// StageTiming records how long a stage took.
+type StageTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Registry holds the transform stages in registration order.
+type Registry struct {
+	stages []Stage
+}
+
+// NewRegistry creates an empty stage registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// DefaultRegistry returns a registry with the built-in stages.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, stage := range builtinStages() {
+		if err := r.Register(stage); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds a stage. Stage names must be unique.
+func (r *Registry) Register(stage Stage) error {
+	if stage.Name == "" {
+		return fmt.Errorf("stage has no name")
+	}
+	if stage.Run == nil {
+		return fmt.Errorf("stage %q has no run function", stage.Name)
+	}
+	for _, s := range r.stages {
+		if s.Name == stage.Name {
+			return fmt.Errorf("stage %q is already registered", stage.Name)
+		}
+	}
+	r.stages = append(r.stages, stage)
+	return nil
+}
+
+// Names returns the names of all registered stages.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.stages))
+	for i, s := range r.stages {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// Plan returns the stages to run in dependency order. Optional stages run
+// only if listed in enabled; any stage listed in disabled is skipped.
+// Stages without a dependency between them keep their registration order.
+// It fails on unknown stage names, datasets produced by more than one
+// stage, inputs no planned stage produces, and dependency cycles.
+func (r *Registry) Plan(enabled, disabled []string) ([]Stage, error) {
+	known := make(map[string]bool, len(r.stages))
+	for _, s := range r.stages {
+		known[s.Name] = true
+	}
+	for _, name := range append(append([]string{}, enabled...), disabled...) {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown transform stage %q (available: %s)",
+				name, strings.Join(r.Names(), ", "))
+		}
+	}
+	enabledSet := stringSet(enabled)
+	disabledSet := stringSet(disabled)
+
+	var stages []Stage
+	for _, s := range r.stages {
+		if disabledSet[s.Name] || (s.Optional && !enabledSet[s.Name]) {
+			continue
+		}
+		stages = append(stages, s)
+	}
+
+	producers := make(map[string]int, len(stages))
+	for i, s := range stages {
+		for _, output := range s.Outputs {
+			if j, ok := producers[output]; ok {
+				return nil, fmt.Errorf("dataset %q is produced by both stage %q and stage %q",
+					output, stages[j].Name, s.Name)
+			}
+			producers[output] = i
+		}
+	}
+
+	// Kahn's algorithm, always picking the earliest registered ready stage
+	dependents := make([][]int, len(stages))
+	pending := make([]int, len(stages))
+	for i, s := range stages {
+		for _, input := range s.Inputs {
+			j, ok := producers[input]
+			if !ok {
+				return nil, fmt.Errorf("stage %q needs dataset %q, which no enabled stage produces", s.Name, input)
+			}
+			if j == i {
+				continue
+			}
+			dependents[j] = append(dependents[j], i)
+			pending[i]++
+		}
+	}
+
+	var ready []int
+	for i := range stages {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	ordered := make([]Stage, 0, len(stages))
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		next := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, stages[next])
+		for _, d := range dependents[next] {
+			pending[d]--
+			if pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	if len(ordered) != len(stages) {
+		var cyclic []string
+		for i, s := range stages {
+			if pending[i] > 0 {
+				cyclic = append(cyclic, s.Name)
+			}
+		}
+		return nil, fmt.Errorf("transform stages have a dependency cycle: %s", strings.Join(cyclic, ", "))
+	}
+
+	return ordered, nil
+}
+
+// stringSet converts a list of names to a set.
+func stringSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// builtinStages returns the built-in transform stages in their historical
+// order.
+func builtinStages() []Stage {
+	return []Stage{
+		{
+			Name:    "solarSystems",
+			Outputs: []string{"solarSystems"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.Universe.SolarSystems = t.transformSolarSystems(s.Parse.SolarSystems)
+				return nil
+			},
+		},
+		{
+			Name:     "securityColumns",
+			Inputs:   []string{"solarSystems"},
+			Outputs:  []string{"securityColumns"},
+			Optional: true,
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				AddSecurityColumns(s.Data.Universe.SolarSystems)
+				return nil
+			},
+		},
+		{
+			Name:    "regions",
+			Outputs: []string{"regions"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.Universe.Regions = t.sortRegions(s.Parse.Regions)
+				return nil
+			},
+		},
+		{
+			Name:    "constellations",
+			Outputs: []string{"constellations"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.Universe.Constellations = t.sortConstellations(s.Parse.Constellations)
+				return nil
+			},
+		},
+		{
+			// Select types and groups for each type set (ships go to invTypes/invGroups)
+			Name:    "typeSets",
+			Outputs: []string{"invTypes", "invGroups", "typeSets"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				typeSets, err := t.loadTypeSets()
+				if err != nil {
+					return err
+				}
+				s.Data.TypeSets = make([]models.TypeSetData, 0, len(typeSets))
+				for _, set := range typeSets {
+					if set.Name == ShipsTypeSet {
+						s.Data.InvGroups = set.SelectGroups(s.Parse.Types, s.Parse.Groups)
+						s.Data.InvTypes = set.SelectTypes(s.Parse.Types, s.Parse.Groups)
+						continue
+					}
+					s.Data.TypeSets = append(s.Data.TypeSets, models.TypeSetData{
+						Name:  set.Name,
+						Types: set.SelectTypes(s.Parse.Types, s.Parse.Groups),
+					})
+				}
+				return nil
+			},
+		},
+		{
+			Name:    "wormholeClasses",
+			Outputs: []string{"wormholeClasses"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.WormholeClasses = t.sortWormholeClasses(s.Parse.WormholeClasses)
+				return nil
+			},
+		},
+		{
+			// Enrich system jumps with region/constellation IDs
+			Name:    "systemJumps",
+			Inputs:  []string{"solarSystems"},
+			Outputs: []string{"systemJumps"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.SystemJumps = t.transformSystemJumps(s.Parse.SystemJumps, s.Data.Universe.SolarSystems)
+				return nil
+			},
+		},
+		{
+			// Aggregate gates between neighbouring regions
+			Name:    "regionJumps",
+			Inputs:  []string{"systemJumps"},
+			Outputs: []string{RegionJumpsDataset},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.AddDataset(GenerateRegionJumps(s.Data.SystemJumps))
+				return nil
+			},
+		},
+		{
+			// Aggregate gates between neighbouring constellations
+			Name:    "constellationJumps",
+			Inputs:  []string{"systemJumps"},
+			Outputs: []string{ConstellationJumpsDataset},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.AddDataset(GenerateConstellationJumps(s.Data.SystemJumps))
+				return nil
+			},
+		},
+		{
+			// Calculate bounds for regions and constellations from constituent systems
+			Name:    "bounds",
+			Inputs:  []string{"regions", "constellations", "solarSystems"},
+			Outputs: []string{"regionBounds", "constellationBounds"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				CalculateRegionBounds(s.Data.Universe.Regions, s.Data.Universe.SolarSystems)
+				CalculateConstellationBounds(s.Data.Universe.Constellations, s.Data.Universe.SolarSystems)
+				return nil
+			},
+		},
+		{
+			// Inherit factionID from region for systems that don't have one
+			Name:    "factionInheritance",
+			Inputs:  []string{"solarSystems", "regions"},
+			Outputs: []string{"systemFactions"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				InheritFactionIDs(s.Data.Universe.SolarSystems, s.Data.Universe.Regions)
+				return nil
+			},
+		},
+		{
+			Name:    "npcStations",
+			Outputs: []string{"npcStations"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.NPCStations = t.transformNPCStations(s.Parse.NPCStations, s.Parse.NPCCorporations)
+				return nil
+			},
+		},
+		{
+			// Generate wormhole types from dogma (only when typeDogma.yaml was parsed)
+			Name:    "wormholes",
+			Outputs: []string{"wormholes"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				if len(s.Parse.TypeDogma) == 0 {
+					return nil
+				}
+				overlay, err := t.loadWormholeOverlay()
+				if err != nil {
+					return err
+				}
+				s.Data.Wormholes = GenerateWormholes(s.Parse.Types, s.Parse.TypeDogma, overlay)
+				return nil
+			},
+		},
+		{
+			// Generate sun types from star data (only when mapStars.yaml was parsed)
+			Name:    "sunTypes",
+			Outputs: []string{"sunTypes"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				if len(s.Parse.Stars) > 0 {
+					s.Data.SunTypes = GenerateSunTypes(s.Parse.Types, s.Parse.Stars)
+				}
+				return nil
+			},
+		},
+		{
+			// Resolve each system's effective wormhole class for the derived datasets
+			Name:    "systemClasses",
+			Inputs:  []string{"solarSystems", "wormholeClasses"},
+			Outputs: []string{"systemClasses"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.SystemClasses = ResolveSystemWormholeClasses(s.Data.Universe.SolarSystems, s.Data.WormholeClasses)
+				return nil
+			},
+		},
+		{
+			Name:    "spaceKinds",
+			Inputs:  []string{"solarSystems", "systemClasses"},
+			Outputs: []string{"spaceKinds"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.UnclassifiedSystems = ClassifySystems(s.Data.Universe.SolarSystems, s.SystemClasses)
+				return nil
+			},
+		},
+		{
+			// Derive wormhole environmental effects (only when mapSecondarySuns.yaml was parsed)
+			Name:    "systemEffects",
+			Inputs:  []string{"solarSystems", "systemClasses"},
+			Outputs: []string{"systemEffects"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				if len(s.Parse.SecondarySuns) > 0 {
+					s.Data.SystemEffects = GenerateSystemEffects(s.Data.Universe.SolarSystems, s.SystemClasses,
+						s.Parse.SecondarySuns, s.Parse.Types, s.Parse.TypeDogma, s.Parse.DogmaAttributes)
+				}
+				return nil
+			},
+		},
+		{
+			Name:    "triglavianSystems",
+			Inputs:  []string{"solarSystems", "systemFactions", "constellations", "systemClasses"},
+			Outputs: []string{"triglavianSystems"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.TriglavianSystems = GenerateTriglavianSystems(
+					s.Data.Universe.SolarSystems, s.Data.Universe.Constellations, s.SystemClasses)
+				return nil
+			},
+		},
+		{
+			Name:    "shatteredConstellations",
+			Inputs:  []string{"solarSystems", "constellations", "systemClasses"},
+			Outputs: []string{"shatteredConstellations"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.ShatteredConstellations = GenerateShatteredConstellations(
+					s.Data.Universe.SolarSystems, s.Data.Universe.Constellations, s.SystemClasses)
+				return nil
+			},
+		},
+		{
+			// Precompute jump drive neighbours within the longest ship range
+			Name:     "jumpNeighbors",
+			Inputs:   []string{"solarSystems"},
+			Outputs:  []string{JumpNeighborsDataset},
+			Optional: true,
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				dataset, err := GenerateJumpNeighbors(ctx, s.Data.Universe.SolarSystems, graph.MaxJumpRange())
+				if err != nil {
+					return err
+				}
+				s.Data.AddDataset(dataset)
+				return nil
+			},
+		},
+		{
+			// Count gate jumps from every system to the configured anchors
+			Name:     "systemDistances",
+			Inputs:   []string{"solarSystems", "systemJumps"},
+			Outputs:  []string{SystemDistancesDataset},
+			Optional: true,
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				g := graph.FromData(s.Data)
+				anchors, err := t.distanceAnchors(g)
+				if err != nil {
+					return err
+				}
+				dataset, err := GenerateSystemDistances(g, anchors)
+				if err != nil {
+					return err
+				}
+				s.Data.AddDataset(dataset)
+				return nil
+			},
+		},
+		{
+			// Count gate jumps between every pair of connected systems
+			Name:     "distanceMatrix",
+			Inputs:   []string{"solarSystems", "systemJumps"},
+			Outputs:  []string{"distanceMatrix"},
+			Optional: true,
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				matrix, err := graph.FromData(s.Data).DistanceMatrix(ctx)
+				if err != nil {
+					return err
+				}
+				s.Data.DistanceMatrix = matrix
+				return nil
+			},
+		},
+		{
+			// Find articulation points, bridge gates, pipes and dead ends
+			Name:     "chokepoints",
+			Inputs:   []string{"solarSystems", "systemJumps"},
+			Outputs:  []string{ChokepointsDataset, RegionChokepointsDataset},
+			Optional: true,
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				systems, regions := GenerateChokepoints(graph.FromData(s.Data))
+				s.Data.AddDataset(systems)
+				s.Data.AddDataset(regions)
+				return nil
+			},
+		},
+		{
+			// Label stargate components and mark systems reachable from known space
+			Name:     "connectivity",
+			Inputs:   []string{"solarSystems", "systemJumps"},
+			Outputs:  []string{"connectivityColumns", GateComponentsDataset},
+			Optional: true,
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				g := graph.FromData(s.Data)
+				s.Data.AddDataset(AddConnectivityColumns(s.Data.Universe.SolarSystems, g))
+				return nil
+			},
+		},
+		{
+			// Find the nearest NPC station matching the configured filter
+			Name:     "nearestStations",
+			Inputs:   []string{"solarSystems", "systemJumps"},
+			Outputs:  []string{NearestStationsDataset},
+			Optional: true,
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				selected, err := SelectStations(s.Parse, t.stationFilter())
+				if err != nil {
+					return err
+				}
+				stations := t.transformNPCStations(selected, s.Parse.NPCCorporations)
+				dataset, err := GenerateNearestStations(graph.FromData(s.Data), stations)
+				if err != nil {
+					return err
+				}
+				s.Data.AddDataset(dataset)
+				return nil
+			},
+		},
+		{
+			Name:    "wormholeTypeNames",
+			Outputs: []string{"wormholeTypeNames"},
+			Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+				s.Data.WormholeTypeNames = WormholeTypeNames(s.Parse.Types)
+				return nil
+			},
+		},
+	}
+}
@@ -0,0 +1,190 @@
+package transformer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+func noop(ctx context.Context, t *Transformer, s *StageState) error { return nil }
+
+func stageNames(stages []Stage) string {
+	names := make([]string, len(stages))
+	for i, s := range stages {
+		names[i] = s.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestRegistry_Plan(t *testing.T) {
+	r := NewRegistry()
+	for _, stage := range []Stage{
+		{Name: "c", Inputs: []string{"b"}, Outputs: []string{"c"}, Run: noop},
+		{Name: "a", Outputs: []string{"a"}, Run: noop},
+		{Name: "b", Inputs: []string{"a"}, Outputs: []string{"b"}, Run: noop},
+		{Name: "d", Outputs: []string{"d"}, Run: noop},
+		{Name: "e", Inputs: []string{"a"}, Outputs: []string{"e"}, Optional: true, Run: noop},
+	} {
+		if err := r.Register(stage); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		enabled  []string
+		disabled []string
+		expected string
+		errMsg   string
+	}{
+		{name: "dependency order", expected: "a,b,c,d"},
+		{name: "optional enabled", enabled: []string{"e"}, expected: "a,b,c,d,e"},
+		{name: "independent stage disabled", disabled: []string{"d"}, expected: "a,b,c"},
+		{name: "producer disabled", disabled: []string{"b"}, errMsg: `needs dataset "b"`},
+		{name: "unknown stage", disabled: []string{"x"}, errMsg: `unknown transform stage "x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages, err := r.Plan(tt.enabled, tt.disabled)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Plan failed: %v", err)
+			}
+			if got := stageNames(stages); got != tt.expected {
+				t.Errorf("Expected order %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRegistry_PlanErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		stages []Stage
+		errMsg string
+	}{
+		{
+			name: "cycle",
+			stages: []Stage{
+				{Name: "a", Inputs: []string{"b"}, Outputs: []string{"a"}, Run: noop},
+				{Name: "b", Inputs: []string{"a"}, Outputs: []string{"b"}, Run: noop},
+			},
+			errMsg: "dependency cycle: a, b",
+		},
+		{
+			name: "duplicate producer",
+			stages: []Stage{
+				{Name: "a", Outputs: []string{"x"}, Run: noop},
+				{Name: "b", Outputs: []string{"x"}, Run: noop},
+			},
+			errMsg: `dataset "x" is produced by both stage "a" and stage "b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			for _, stage := range tt.stages {
+				if err := r.Register(stage); err != nil {
+					t.Fatalf("Register failed: %v", err)
+				}
+			}
+			_, err := r.Plan(nil, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(Stage{Name: "a", Run: noop}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := r.Register(Stage{Name: "a", Run: noop}); err == nil {
+		t.Error("Expected error for duplicate stage name")
+	}
+	if err := r.Register(Stage{Name: "b"}); err == nil {
+		t.Error("Expected error for stage without run function")
+	}
+}
+
+func TestDefaultRegistry_Plan(t *testing.T) {
+	stages, err := DefaultRegistry().Plan(nil, nil)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	position := make(map[string]int, len(stages))
+	for i, s := range stages {
+		position[s.Name] = i
+	}
+	if _, ok := position["securityColumns"]; ok {
+		t.Error("Expected optional securityColumns stage to be skipped by default")
+	}
+	if position["factionInheritance"] > position["triglavianSystems"] {
+		t.Error("Expected faction inheritance to run before Triglavian detection")
+	}
+	if position["systemClasses"] > position["spaceKinds"] {
+		t.Error("Expected system classes to be resolved before space classification")
+	}
+}
+
+func TestTransformer_CustomStage(t *testing.T) {
+	tr := New(&config.Config{DisableStages: []string{"systemEffects", "regionJumps", "constellationJumps"}}, nil)
+
+	err := tr.Register(Stage{
+		Name:    "systemNames",
+		Inputs:  []string{"solarSystems", "spaceKinds"},
+		Outputs: []string{"systemNames"},
+		Run: func(ctx context.Context, t *Transformer, s *StageState) error {
+			dataset := models.Dataset{Name: "systemNames", Columns: []string{"solarSystemID", "name", "spaceKind"}}
+			for _, sys := range s.Data.Universe.SolarSystems {
+				dataset.Rows = append(dataset.Rows, []interface{}{sys.SolarSystemID, sys.SolarSystemName, sys.SpaceKind})
+			}
+			s.Data.AddDataset(dataset)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	data, err := tr.Transform(context.Background(), &parser.ParseResult{
+		SolarSystems: []models.SolarSystem{
+			{SolarSystemID: 30000142, SolarSystemName: "Jita", Security: 0.9459},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if len(data.Datasets) != 1 || data.Datasets[0].Name != "systemNames" {
+		t.Fatalf("Expected systemNames dataset, got %+v", data.Datasets)
+	}
+	row := data.Datasets[0].Rows[0]
+	if row[1] != "Jita" || row[2] != SpaceHighSec {
+		t.Errorf("Expected stage to see classified systems, got %v", row)
+	}
+
+	timings := tr.StageTimings()
+	if len(timings) == 0 || timings[len(timings)-1].Name != "systemNames" {
+		t.Errorf("Expected custom stage to run last and be timed, got %+v", timings)
+	}
+	for _, timing := range timings {
+		if timing.Name == "systemEffects" {
+			t.Error("Expected disabled systemEffects stage not to run")
+		}
+	}
+}
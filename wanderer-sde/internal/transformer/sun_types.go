@@ -0,0 +1,97 @@
+package transformer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+// SunGroupID is the group ID for star types ("Sun" group).
+const SunGroupID = 6
+
+// GenerateSunTypes builds Wanderer's sunTypes.json entries.
+//
+// Every type in the Sun group is included, along with any other type
+// referenced by a star in mapStars.yaml. The spectral class is the most
+// common one among stars of that type (ties broken alphabetically), or
+// empty if no star of that type carries statistics.
+func GenerateSunTypes(types map[int64]models.SDEType, stars map[int64]parser.SDEMapStar) []models.SunType {
+	// Count spectral classes per star type
+	classCounts := make(map[int64]map[string]int)
+	starTypeIDs := make(map[int64]bool)
+	for _, star := range stars {
+		if star.TypeID == 0 {
+			continue
+		}
+		starTypeIDs[star.TypeID] = true
+		if star.Statistics == nil || star.Statistics.SpectralClass == "" {
+			continue
+		}
+		if classCounts[star.TypeID] == nil {
+			classCounts[star.TypeID] = make(map[string]int)
+		}
+		classCounts[star.TypeID][star.Statistics.SpectralClass]++
+	}
+
+	result := make([]models.SunType, 0)
+	for typeID, sdeType := range types {
+		if sdeType.GroupID != SunGroupID && !starTypeIDs[typeID] {
+			continue
+		}
+
+		result = append(result, models.SunType{
+			TypeID:        typeID,
+			TypeName:      sdeType.Name["en"],
+			GroupID:       sdeType.GroupID,
+			SpectralClass: dominantSpectralClass(classCounts[typeID]),
+		})
+	}
+
+	// Sort by type ID for consistent output
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TypeID < result[j].TypeID
+	})
+
+	return result
+}
+
+// dominantSpectralClass returns the most frequent spectral class, breaking
+// ties alphabetically so the result is deterministic.
+func dominantSpectralClass(counts map[string]int) string {
+	best := ""
+	bestCount := 0
+	for class, count := range counts {
+		if count > bestCount || (count == bestCount && class < best) {
+			best = class
+			bestCount = count
+		}
+	}
+	return best
+}
+
+// validateSunTypes checks that every solar system's sun type resolves to a
+// generated sunTypes.json entry.
+func validateSunTypes(data *models.ConvertedData, result *models.ValidationResult) {
+	if len(data.SunTypes) == 0 {
+		return
+	}
+
+	known := make(map[int64]bool, len(data.SunTypes))
+	for _, sunType := range data.SunTypes {
+		known[sunType.TypeID] = true
+	}
+
+	var findings []string
+	for _, sys := range data.Universe.SolarSystems {
+		if sys.SunTypeID == nil || known[*sys.SunTypeID] {
+			continue
+		}
+		findings = append(findings,
+			fmt.Sprintf("Solar system %d (%s) has sun type %d with no sunTypes entry",
+				sys.SolarSystemID, sys.SolarSystemName, *sys.SunTypeID))
+	}
+
+	result.Warnings = appendFindings(result.Warnings, findings)
+}
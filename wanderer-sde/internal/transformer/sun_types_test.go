@@ -0,0 +1,88 @@
+package transformer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/parser"
+)
+
+func TestGenerateSunTypes(t *testing.T) {
+	types := map[int64]models.SDEType{
+		3796:  {GroupID: SunGroupID, Name: map[string]string{"en": "Sun G5 (Yellow)"}},
+		3797:  {GroupID: SunGroupID, Name: map[string]string{"en": "Sun K7 (Orange)"}},
+		45041: {GroupID: 995, Name: map[string]string{"en": "Sun A0IV (Turbulent Blue Subgiant)"}},
+		587:   {GroupID: 25, Name: map[string]string{"en": "Rifter"}},
+	}
+	stars := map[int64]parser.SDEMapStar{
+		40000001: {TypeID: 3796, Statistics: &parser.SDEStarStats{SpectralClass: "G5 V"}},
+		40000002: {TypeID: 3796, Statistics: &parser.SDEStarStats{SpectralClass: "G5 V"}},
+		40000003: {TypeID: 3796, Statistics: &parser.SDEStarStats{SpectralClass: "G3 V"}},
+		40000004: {TypeID: 45041, Statistics: &parser.SDEStarStats{SpectralClass: "A0IV"}},
+		40000005: {TypeID: 45041},
+	}
+
+	result := GenerateSunTypes(types, stars)
+
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 sun types (2 in Sun group + 1 referenced by a star), got %d", len(result))
+	}
+
+	expected := []models.SunType{
+		{TypeID: 3796, TypeName: "Sun G5 (Yellow)", GroupID: SunGroupID, SpectralClass: "G5 V"},
+		{TypeID: 3797, TypeName: "Sun K7 (Orange)", GroupID: SunGroupID, SpectralClass: ""},
+		{TypeID: 45041, TypeName: "Sun A0IV (Turbulent Blue Subgiant)", GroupID: 995, SpectralClass: "A0IV"},
+	}
+	for i, exp := range expected {
+		if result[i] != exp {
+			t.Errorf("Sun type %d: expected %+v, got %+v", i, exp, result[i])
+		}
+	}
+}
+
+func TestDominantSpectralClass_Tie(t *testing.T) {
+	counts := map[string]int{"K5 V": 2, "G5 V": 2, "M0 V": 1}
+	if got := dominantSpectralClass(counts); got != "G5 V" {
+		t.Errorf("Expected alphabetical tie-break to pick G5 V, got %q", got)
+	}
+	if got := dominantSpectralClass(nil); got != "" {
+		t.Errorf("Expected empty class for no statistics, got %q", got)
+	}
+}
+
+func TestValidate_UnresolvedSunTypes(t *testing.T) {
+	cfg := &config.Config{Verbose: false}
+	tr := New(cfg, nil)
+
+	known := int64(3796)
+	unknown := int64(99999)
+	data := &models.ConvertedData{
+		Universe: &models.UniverseData{
+			Regions:        []models.Region{{RegionID: 10000002}},
+			Constellations: []models.Constellation{{ConstellationID: 20000020}},
+			SolarSystems: []models.SolarSystem{
+				{SolarSystemID: 30000142, SolarSystemName: "Jita", SunTypeID: &known},
+				{SolarSystemID: 30000001, SolarSystemName: "Tanoo", SunTypeID: &unknown},
+				{SolarSystemID: 30000002, SolarSystemName: "Lashesih"},
+			},
+		},
+		SunTypes: []models.SunType{{TypeID: 3796}},
+	}
+
+	result := tr.Validate(data)
+
+	var found int
+	for _, w := range result.Warnings {
+		if strings.Contains(w, "no sunTypes entry") {
+			found++
+			if !strings.Contains(w, "Tanoo") {
+				t.Errorf("Expected warning for Tanoo, got %q", w)
+			}
+		}
+	}
+	if found != 1 {
+		t.Errorf("Expected 1 unresolved sun type warning, got %d", found)
+	}
+}
@@ -1,120 +1,146 @@
 package transformer
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"sort"
+	"time"
 
 	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/logging"
 	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/internal/overrides"
 	"github.com/guarzo/wanderer-sde/internal/parser"
 )
 
 // Transformer handles transformation of parsed SDE data to Wanderer format.
 type Transformer struct {
-	config *config.Config
+	config   *config.Config
+	logger   *slog.Logger
+	registry *Registry
+	timings  []StageTiming
+	warnings []string // Problems met while transforming, reported by Validate
+	applied  []overrides.AppliedPatch
 }
 
-// New creates a new Transformer with the given configuration.
-func New(cfg *config.Config) *Transformer {
+// New creates a new Transformer with the given configuration and the
+// built-in transform stages. A nil logger discards log output.
+func New(cfg *config.Config, logger *slog.Logger) *Transformer {
 	return &Transformer{
-		config: cfg,
+		config:   cfg,
+		logger:   logging.OrDiscard(logger),
+		registry: DefaultRegistry(),
 	}
 }
 
-// Transform converts parsed SDE data into Wanderer's output format.
-func (t *Transformer) Transform(parseResult *parser.ParseResult) (*models.ConvertedData, error) {
-	if t.config.Verbose {
-		fmt.Println("Transforming SDE data...")
-	}
+// Register adds a transform stage to run alongside the built-in stages.
+func (t *Transformer) Register(stage Stage) error {
+	return t.registry.Register(stage)
+}
 
-	// Transform solar systems with security calculation
-	if t.config.Verbose {
-		fmt.Println("  Transforming solar systems...")
-	}
-	systems := t.transformSolarSystems(parseResult.SolarSystems)
+// StageTimings returns how long each stage of the last Transform call took,
+// in the order the stages ran.
+func (t *Transformer) StageTimings() []StageTiming {
+	return t.timings
+}
 
-	// Sort regions for consistent output
-	if t.config.Verbose {
-		fmt.Println("  Sorting regions...")
-	}
-	regions := t.sortRegions(parseResult.Regions)
+// Transform converts parsed SDE data into Wanderer's output format by
+// running the enabled transform stages in dependency order. Configured
+// override patches to tables other data is derived from are applied to the
+// parsed data before the stages run, so derived data agrees with them; the
+// other patches are applied to the result. The context is checked between
+// stages and once the last stage finishes, and passed to each stage.
+func (t *Transformer) Transform(ctx context.Context, parseResult *parser.ParseResult) (*models.ConvertedData, error) {
+	t.logger.Debug("transforming SDE data")
+	t.warnings = nil
+	t.applied = nil
 
-	// Sort constellations for consistent output
-	if t.config.Verbose {
-		fmt.Println("  Sorting constellations...")
+	enabled := t.config.EnableStages
+	enabled = append([]string{}, enabled...)
+	if t.config.SecurityColumns {
+		enabled = append(enabled, "securityColumns")
 	}
-	constellations := t.sortConstellations(parseResult.Constellations)
-
-	// Transform groups (filter to ships only - category 6)
-	if t.config.Verbose {
-		fmt.Println("  Transforming groups (ships only)...")
+	if t.config.ConnectivityColumns {
+		enabled = append(enabled, "connectivity")
 	}
-	invGroups := t.transformShipGroups(parseResult.Groups)
-
-	// Transform types (filter to ships only - groups with category 6)
-	if t.config.Verbose {
-		fmt.Println("  Transforming types (ships only)...")
+	if t.config.JumpNeighbors {
+		enabled = append(enabled, "jumpNeighbors")
 	}
-	invTypes := t.transformShipTypes(parseResult.Types, parseResult.Groups)
-
-	// Sort wormhole classes for consistent output
-	if t.config.Verbose {
-		fmt.Println("  Sorting wormhole classes...")
+	if t.config.Chokepoints {
+		enabled = append(enabled, "chokepoints")
 	}
-	wormholeClasses := t.sortWormholeClasses(parseResult.WormholeClasses)
-
-	// Transform system jumps with region/constellation lookup
-	if t.config.Verbose {
-		fmt.Println("  Transforming system jumps...")
+	if len(t.config.DistanceAnchors) > 0 {
+		enabled = append(enabled, "systemDistances")
 	}
-	systemJumps := t.transformSystemJumps(parseResult.SystemJumps, systems)
-
-	// Calculate bounds for regions and constellations from constituent systems
-	if t.config.Verbose {
-		fmt.Println("  Calculating region bounds...")
+	if t.config.DistanceMatrix {
+		enabled = append(enabled, "distanceMatrix")
 	}
-	CalculateRegionBounds(regions, systems)
-
-	if t.config.Verbose {
-		fmt.Println("  Calculating constellation bounds...")
+	if len(t.config.NearestStationOwners) > 0 || len(t.config.NearestStationServices) > 0 ||
+		len(t.config.NearestStationFactions) > 0 {
+		enabled = append(enabled, "nearestStations")
 	}
-	CalculateConstellationBounds(constellations, systems)
-
-	// Inherit factionID from region for systems that don't have one
-	if t.config.Verbose {
-		fmt.Println("  Inheriting faction IDs...")
+	stages, err := t.registry.Plan(enabled, t.config.DisableStages)
+	if err != nil {
+		return nil, err
 	}
-	InheritFactionIDs(systems, regions)
 
-	// Transform NPC stations
-	if t.config.Verbose {
-		fmt.Println("  Transforming NPC stations...")
+	sourcePatches, otherPatches, err := t.loadOverrides()
+	if err != nil {
+		return nil, err
 	}
-	npcStations := t.transformNPCStations(parseResult.NPCStations, parseResult.NPCCorporations)
 
-	result := &models.ConvertedData{
-		Universe: &models.UniverseData{
-			Regions:        regions,
-			Constellations: constellations,
-			SolarSystems:   systems,
-		},
-		InvTypes:        invTypes,
-		InvGroups:       invGroups,
-		WormholeClasses: wormholeClasses,
-		SystemJumps:     systemJumps,
-		NPCStations:     npcStations,
+	state := &StageState{
+		Parse: t.overrideParseResult(parseResult, sourcePatches),
+		Data:  &models.ConvertedData{Universe: &models.UniverseData{}},
 	}
 
-	if t.config.Verbose {
-		fmt.Printf("Transformation complete:\n")
-		fmt.Printf("  Regions:         %d\n", len(result.Universe.Regions))
-		fmt.Printf("  Constellations:  %d\n", len(result.Universe.Constellations))
-		fmt.Printf("  Solar Systems:   %d\n", len(result.Universe.SolarSystems))
-		fmt.Printf("  Types:           %d\n", len(result.InvTypes))
-		fmt.Printf("  Groups:          %d\n", len(result.InvGroups))
-		fmt.Printf("  Wormhole Classes: %d\n", len(result.WormholeClasses))
-		fmt.Printf("  System Jumps:    %d\n", len(result.SystemJumps))
-		fmt.Printf("  NPC Stations:    %d\n", len(result.NPCStations))
+	t.timings = make([]StageTiming, 0, len(stages))
+	for _, stage := range stages {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		if err := stage.Run(ctx, t, state); err != nil {
+			return nil, fmt.Errorf("transform stage %s failed: %w", stage.Name, err)
+		}
+		elapsed := time.Since(start)
+		t.timings = append(t.timings, StageTiming{Name: stage.Name, Duration: elapsed})
+
+		t.logger.Debug("transform stage complete", "stage", stage.Name, "duration", elapsed.Round(time.Microsecond))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := state.Data
+	t.applyOverrides(result, otherPatches)
+
+	if t.logger.Enabled(ctx, slog.LevelDebug) {
+		args := []any{
+			"regions", len(result.Universe.Regions),
+			"constellations", len(result.Universe.Constellations),
+			"solarSystems", len(result.Universe.SolarSystems),
+			"types", len(result.InvTypes),
+			"groups", len(result.InvGroups),
+			"wormholeClasses", len(result.WormholeClasses),
+			"systemJumps", len(result.SystemJumps),
+			"npcStations", len(result.NPCStations),
+			"wormholeTypes", len(result.Wormholes),
+			"sunTypes", len(result.SunTypes),
+			"systemEffects", len(result.SystemEffects),
+			"pochvenSystems", len(result.TriglavianSystems),
+			"shatteredConstellations", len(result.ShatteredConstellations),
+			"unclassifiedSystems", len(result.UnclassifiedSystems),
+		}
+		for _, set := range result.TypeSets {
+			args = append(args, "types_"+set.Name, len(set.Types))
+		}
+		for _, dataset := range result.Datasets {
+			args = append(args, dataset.Name, len(dataset.Rows))
+		}
+		t.logger.Debug("transformation complete", args...)
 	}
 
 	return result, nil
@@ -159,14 +185,12 @@ func (t *Transformer) sortConstellations(constellations []models.Constellation)
 	return result
 }
 
-// getLocalizedName retrieves a localized name from a map, logging a warning in verbose mode if missing.
+// getLocalizedName retrieves a localized name from a map, logging at debug level if missing.
 func (t *Transformer) getLocalizedName(names map[string]string, lang string, context string) string {
 	if name, ok := names[lang]; ok {
 		return name
 	}
-	if t.config.Verbose {
-		fmt.Printf("  Warning: missing '%s' name for %s\n", lang, context)
-	}
+	t.logger.Debug("missing localized name", "lang", lang, "for", context)
 	return ""
 }
 
@@ -182,70 +206,13 @@ func (t *Transformer) sortWormholeClasses(classes []models.WormholeClassLocation
 	return result
 }
 
-// transformShipTypes converts SDE types to InvType format, filtering to ships only.
-// Ships are types whose groupID belongs to a group with categoryID == 6.
-func (t *Transformer) transformShipTypes(types map[int64]models.SDEType, groups map[int64]models.SDEGroup) []models.InvType {
-	// Build set of ship group IDs
-	shipGroupIDs := make(map[int64]bool)
-	for groupID, group := range groups {
-		if group.CategoryID == ShipCategoryID {
-			shipGroupIDs[groupID] = true
-		}
-	}
-
-	result := make([]models.InvType, 0)
-
-	for typeID, sdeType := range types {
-		// Only include types that belong to ship groups
-		if !shipGroupIDs[sdeType.GroupID] {
-			continue
-		}
-
-		invType := models.InvType{
-			TypeID:    typeID,
-			GroupID:   sdeType.GroupID,
-			TypeName:  t.getLocalizedName(sdeType.Name, "en", fmt.Sprintf("type %d", typeID)),
-			Mass:      sdeType.Mass,
-			Volume:    sdeType.Volume,
-			Capacity:  sdeType.Capacity,
-			Published: sdeType.Published,
-		}
-		result = append(result, invType)
+// loadTypeSets returns the type set rules from the configured file, or the
+// defaults when none is given.
+func (t *Transformer) loadTypeSets() ([]TypeSet, error) {
+	if t.config.TypeSetsFile == "" {
+		return DefaultTypeSets(), nil
 	}
-
-	// Sort by type ID for consistent output
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].TypeID < result[j].TypeID
-	})
-
-	return result
-}
-
-// transformShipGroups converts SDE groups to InvGroup format, filtering to ships only.
-// Ships are groups with categoryID == 6.
-func (t *Transformer) transformShipGroups(groups map[int64]models.SDEGroup) []models.InvGroup {
-	result := make([]models.InvGroup, 0)
-
-	for groupID, sdeGroup := range groups {
-		// Only include ship groups (category 6)
-		if sdeGroup.CategoryID != ShipCategoryID {
-			continue
-		}
-
-		invGroup := models.InvGroup{
-			GroupID:    groupID,
-			CategoryID: sdeGroup.CategoryID,
-			GroupName:  t.getLocalizedName(sdeGroup.Name, "en", fmt.Sprintf("group %d", groupID)),
-		}
-		result = append(result, invGroup)
-	}
-
-	// Sort by group ID for consistent output
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].GroupID < result[j].GroupID
-	})
-
-	return result
+	return LoadTypeSets(t.config.TypeSetsFile)
 }
 
 // transformNPCStations converts SDE NPC stations to Wanderer format.
@@ -300,15 +267,15 @@ func (t *Transformer) transformSystemJumps(jumps []models.SystemJump, systems []
 		if fromOK {
 			enrichedJump.FromRegionID = fromSys.RegionID
 			enrichedJump.FromConstellationID = fromSys.ConstellationID
-		} else if t.config.Verbose {
-			fmt.Printf("  Warning: unknown from system %d in jump\n", jump.FromSolarSystemID)
+		} else {
+			t.logger.Debug("unknown from system in jump", "system", jump.FromSolarSystemID)
 		}
 
 		if toOK {
 			enrichedJump.ToRegionID = toSys.RegionID
 			enrichedJump.ToConstellationID = toSys.ConstellationID
-		} else if t.config.Verbose {
-			fmt.Printf("  Warning: unknown to system %d in jump\n", jump.ToSolarSystemID)
+		} else {
+			t.logger.Debug("unknown to system in jump", "system", jump.ToSolarSystemID)
 		}
 
 		result = append(result, enrichedJump)
@@ -325,6 +292,20 @@ func (t *Transformer) transformSystemJumps(jumps []models.SystemJump, systems []
 	return result
 }
 
+// maxReportedFindings caps how many individual findings of one kind are
+// listed before the rest are summarized in a single message.
+const maxReportedFindings = 10
+
+// appendFindings appends up to maxReportedFindings findings to list and
+// summarizes the remainder in one message.
+func appendFindings(list []string, findings []string) []string {
+	if len(findings) <= maxReportedFindings {
+		return append(list, findings...)
+	}
+	list = append(list, findings[:maxReportedFindings]...)
+	return append(list, fmt.Sprintf("... and %d more", len(findings)-maxReportedFindings))
+}
+
 // Validate performs validation checks on the converted data.
 func (t *Transformer) Validate(data *models.ConvertedData) *models.ValidationResult {
 	result := &models.ValidationResult{
@@ -337,6 +318,7 @@ func (t *Transformer) Validate(data *models.ConvertedData) *models.ValidationRes
 		WormholeClasses: len(data.WormholeClasses),
 		NPCStations:     len(data.NPCStations),
 	}
+	result.Warnings = append(result.Warnings, t.warnings...)
 
 	// Validation thresholds based on known EVE universe size
 	const (
@@ -412,5 +394,30 @@ func (t *Transformer) Validate(data *models.ConvertedData) *models.ValidationRes
 		result.Errors = append(result.Errors, "No constellations found")
 	}
 
+	addFindings(result, CheckIntegrity(data))
+	validateSunTypes(data, result)
+	validateSpaceKinds(data, result)
+
+	// Guard against unexpected changes since the previous output
+	if t.config.BaselineDir != "" {
+		t.validateBaseline(data, result)
+	}
+
+	// Compare generated datasets with the hand-maintained passthrough copies
+	if t.config.PassthroughDir != "" {
+		if len(data.SystemEffects) > 0 {
+			result.Warnings = appendFindings(result.Warnings,
+				CompareSystemEffects(data.SystemEffects, t.config.PassthroughDir))
+		}
+		if len(data.TriglavianSystems) > 0 {
+			result.Warnings = append(result.Warnings,
+				CompareTriglavianSystems(data.TriglavianSystems, t.config.PassthroughDir)...)
+		}
+		if len(data.ShatteredConstellations) > 0 {
+			result.Warnings = append(result.Warnings,
+				CompareShatteredConstellations(data.ShatteredConstellations, t.config.PassthroughDir)...)
+		}
+	}
+
 	return result
 }
@@ -1,6 +1,7 @@
 package transformer
 
 import (
+	"context"
 	"testing"
 
 	"github.com/guarzo/wanderer-sde/internal/config"
@@ -10,7 +11,7 @@ import (
 
 func TestTransformer_Transform(t *testing.T) {
 	cfg := &config.Config{Verbose: false}
-	tr := New(cfg)
+	tr := New(cfg, nil)
 
 	sunType6 := int64(6)
 	sunType7 := int64(7)
@@ -69,7 +70,7 @@ func TestTransformer_Transform(t *testing.T) {
 		},
 	}
 
-	result, err := tr.Transform(parseResult)
+	result, err := tr.Transform(context.Background(), parseResult)
 	if err != nil {
 		t.Fatalf("Transform failed: %v", err)
 	}
@@ -132,7 +133,7 @@ func TestTransformer_Transform(t *testing.T) {
 
 func TestTransformer_Validate(t *testing.T) {
 	cfg := &config.Config{Verbose: false}
-	tr := New(cfg)
+	tr := New(cfg, nil)
 
 	tests := []struct {
 		name           string
@@ -209,7 +210,7 @@ func TestTransformer_Validate(t *testing.T) {
 
 func TestTransformer_SortFunctions(t *testing.T) {
 	cfg := &config.Config{Verbose: false}
-	tr := New(cfg)
+	tr := New(cfg, nil)
 
 	// Test sortRegions
 	t.Run("sortRegions", func(t *testing.T) {
@@ -297,7 +298,7 @@ func TestTransformer_SortFunctions(t *testing.T) {
 
 func TestTransformer_TransformSolarSystems(t *testing.T) {
 	cfg := &config.Config{Verbose: false}
-	tr := New(cfg)
+	tr := New(cfg, nil)
 
 	systems := []models.SolarSystem{
 		{SolarSystemID: 3, SolarSystemName: "C", Security: 0.9459},
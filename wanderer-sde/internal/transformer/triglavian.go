@@ -0,0 +1,101 @@
+package transformer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/guarzo/wanderer-sde/internal/eve"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// Pochven identifiers in the SDE.
+const (
+	PochvenRegionID     = eve.PochvenRegionID
+	TriglavianFactionID = 500026
+)
+
+// GenerateTriglavianSystems lists the systems of Pochven. A system belongs
+// to Pochven if it is in the Pochven region, is owned by the Triglavian
+// Collective directly or through its constellation, or has the Pochven
+// wormhole class.
+func GenerateTriglavianSystems(
+	systems []models.SolarSystem,
+	constellations []models.Constellation,
+	systemClasses map[int64]int64,
+) []models.TriglavianSystem {
+	constellationByID := make(map[int64]models.Constellation, len(constellations))
+	for _, c := range constellations {
+		constellationByID[c.ConstellationID] = c
+	}
+
+	result := make([]models.TriglavianSystem, 0)
+	for _, sys := range systems {
+		constellation := constellationByID[sys.ConstellationID]
+		inPochven := sys.RegionID == PochvenRegionID ||
+			isTriglavianFaction(sys.FactionID) ||
+			isTriglavianFaction(constellation.FactionID) ||
+			systemClasses[sys.SolarSystemID] == WormholeClassPochven
+		if !inPochven {
+			continue
+		}
+
+		result = append(result, models.TriglavianSystem{
+			SolarSystemID:     sys.SolarSystemID,
+			SolarSystemName:   sys.SolarSystemName,
+			ConstellationID:   sys.ConstellationID,
+			ConstellationName: constellation.ConstellationName,
+		})
+	}
+
+	// Sort by system ID for consistent output
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SolarSystemID < result[j].SolarSystemID
+	})
+
+	return result
+}
+
+// isTriglavianFaction reports whether a faction ID is the Triglavian Collective.
+func isTriglavianFaction(factionID *int64) bool {
+	return factionID != nil && *factionID == TriglavianFactionID
+}
+
+// CompareTriglavianSystems diffs generated Pochven systems against the
+// passthrough triglavianSystems.json in passthroughDir.
+func CompareTriglavianSystems(generated []models.TriglavianSystem, passthroughDir string) []string {
+	ids := make([]int64, len(generated))
+	for i, sys := range generated {
+		ids[i] = sys.SolarSystemID
+	}
+	return compareWithPassthrough(filepath.Join(passthroughDir, "triglavianSystems.json"), ids,
+		"solarSystemID", "solarSystemId", "id")
+}
+
+// compareWithPassthrough diffs generated IDs against the IDs in a passthrough
+// file and describes any difference. Missing files produce no findings.
+func compareWithPassthrough(path string, generated []int64, keys ...string) []string {
+	passthrough, found, err := readPassthroughIDs(path, keys...)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if !found {
+		return nil
+	}
+
+	added, removed := diffIDs(generated, passthrough)
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%s differs from passthrough: %d added %s, %d removed %s",
+		filepath.Base(path), len(added), formatIDList(added), len(removed), formatIDList(removed))}
+}
+
+// formatIDList formats IDs for a one-line diff, eliding long lists.
+func formatIDList(ids []int64) string {
+	if len(ids) <= maxReportedFindings {
+		return fmt.Sprint(ids)
+	}
+	return fmt.Sprintf("%v...", ids[:maxReportedFindings])
+}
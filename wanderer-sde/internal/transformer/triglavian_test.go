@@ -0,0 +1,80 @@
+package transformer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestGenerateTriglavianSystems(t *testing.T) {
+	triglavian := int64(TriglavianFactionID)
+	caldari := int64(500001)
+
+	constellations := []models.Constellation{
+		{ConstellationID: 20000788, ConstellationName: "Krai Perun", RegionID: PochvenRegionID},
+		{ConstellationID: 20000789, ConstellationName: "Krai Veles", RegionID: 10000002, FactionID: &triglavian},
+		{ConstellationID: 20000020, ConstellationName: "Kimotoro", RegionID: 10000002, FactionID: &caldari},
+	}
+	systems := []models.SolarSystem{
+		{SolarSystemID: 30000021, SolarSystemName: "Kuharah", ConstellationID: 20000788, RegionID: PochvenRegionID},
+		{SolarSystemID: 30001372, SolarSystemName: "Kino", ConstellationID: 20000789, RegionID: 10000002},
+		{SolarSystemID: 30002079, SolarSystemName: "Krirald", ConstellationID: 20000020, RegionID: 10000002, FactionID: &triglavian},
+		{SolarSystemID: 30002225, SolarSystemName: "Harva", ConstellationID: 20000020, RegionID: 10000002},
+		{SolarSystemID: 30000142, SolarSystemName: "Jita", ConstellationID: 20000020, RegionID: 10000002, FactionID: &caldari},
+	}
+	classes := map[int64]int64{30002225: WormholeClassPochven, 30000142: WormholeClassHighSec}
+
+	result := GenerateTriglavianSystems(systems, constellations, classes)
+
+	expected := []models.TriglavianSystem{
+		{SolarSystemID: 30000021, SolarSystemName: "Kuharah", ConstellationID: 20000788, ConstellationName: "Krai Perun"}, // region
+		{SolarSystemID: 30001372, SolarSystemName: "Kino", ConstellationID: 20000789, ConstellationName: "Krai Veles"},    // constellation faction
+		{SolarSystemID: 30002079, SolarSystemName: "Krirald", ConstellationID: 20000020, ConstellationName: "Kimotoro"},   // system faction
+		{SolarSystemID: 30002225, SolarSystemName: "Harva", ConstellationID: 20000020, ConstellationName: "Kimotoro"},     // wormhole class
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d Pochven systems, got %d: %+v", len(expected), len(result), result)
+	}
+	for i, exp := range expected {
+		if result[i] != exp {
+			t.Errorf("System %d: expected %+v, got %+v", i, exp, result[i])
+		}
+	}
+}
+
+func TestCompareTriglavianSystems(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "triglavian_passthrough")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	generated := []models.TriglavianSystem{{SolarSystemID: 30000021}, {SolarSystemID: 30001372}}
+
+	// Missing passthrough file is not a disagreement
+	if findings := CompareTriglavianSystems(generated, tmpDir); len(findings) != 0 {
+		t.Errorf("Expected no findings without passthrough file, got %v", findings)
+	}
+
+	content := `[{"solarSystemId": 30000021}, {"solarSystemId": 30002079}]`
+	if err := os.WriteFile(filepath.Join(tmpDir, "triglavianSystems.json"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write triglavianSystems.json: %v", err)
+	}
+
+	findings := CompareTriglavianSystems(generated, tmpDir)
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %v", findings)
+	}
+	if !strings.Contains(findings[0], "1 added [30001372]") || !strings.Contains(findings[0], "1 removed [30002079]") {
+		t.Errorf("Unexpected diff: %q", findings[0])
+	}
+
+	// Identical sets produce no findings
+	generated = []models.TriglavianSystem{{SolarSystemID: 30002079}, {SolarSystemID: 30000021}}
+	if findings := CompareTriglavianSystems(generated, tmpDir); len(findings) != 0 {
+		t.Errorf("Expected no findings for identical sets, got %v", findings)
+	}
+}
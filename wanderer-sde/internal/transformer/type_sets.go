@@ -0,0 +1,198 @@
+package transformer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+	"github.com/guarzo/wanderer-sde/pkg/yaml"
+)
+
+// ShipsTypeSet is the name of the type set written to invTypes and
+// invGroups. All other type sets are written to their own files.
+const ShipsTypeSet = "ships"
+
+// Category IDs used by the default type sets.
+const (
+	DeployableCategoryID = 22
+	StructureCategoryID  = 65
+)
+
+// TypeSet is a declarative rule selecting types for one output file. A type
+// is included if its category, its group or its own ID is listed, unless it
+// is excluded. Groups listed by category or ID are included even if they
+// have no types, but not if all their types are excluded.
+type TypeSet struct {
+	Name           string  `yaml:"name"`
+	Categories     []int64 `yaml:"categories"`
+	Groups         []int64 `yaml:"groups"`
+	TypeIDs        []int64 `yaml:"typeIDs"`
+	ExcludeTypeIDs []int64 `yaml:"excludeTypeIDs"`
+	PublishedOnly  bool    `yaml:"publishedOnly"` // Only published types and groups
+}
+
+// typeSetRules is the layout of a type set rules file.
+type typeSetRules struct {
+	TypeSets []TypeSet `yaml:"typeSets"`
+}
+
+// typeSetName restricts set names to characters safe in file names.
+var typeSetName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// DefaultTypeSets returns the type sets used when no rules file is given.
+// The ships set matches the historical ships-only invTypes output.
+func DefaultTypeSets() []TypeSet {
+	return []TypeSet{
+		{Name: ShipsTypeSet, Categories: []int64{ShipCategoryID}},
+		{Name: "structures", Categories: []int64{StructureCategoryID}, PublishedOnly: true},
+		{Name: "deployables", Categories: []int64{DeployableCategoryID}, PublishedOnly: true},
+		{Name: "wormholes", Groups: []int64{WormholeGroupID}},
+	}
+}
+
+// LoadTypeSets reads type set rules from a YAML or JSON file. A rules file
+// without a ships set keeps the default ships rule for invTypes.
+func LoadTypeSets(path string) ([]TypeSet, error) {
+	var rules typeSetRules
+	if err := yaml.ParseFile(path, &rules); err != nil {
+		return nil, fmt.Errorf("failed to load type set rules %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(rules.TypeSets))
+	hasShips := false
+	for _, set := range rules.TypeSets {
+		if !typeSetName.MatchString(set.Name) {
+			return nil, fmt.Errorf("type set rules %s: invalid set name %q", path, set.Name)
+		}
+		if seen[set.Name] {
+			return nil, fmt.Errorf("type set rules %s: duplicate set %q", path, set.Name)
+		}
+		seen[set.Name] = true
+		hasShips = hasShips || set.Name == ShipsTypeSet
+	}
+
+	if !hasShips {
+		rules.TypeSets = append([]TypeSet{DefaultTypeSets()[0]}, rules.TypeSets...)
+	}
+
+	return rules.TypeSets, nil
+}
+
+// SelectGroups returns the groups matched by a type set: groups listed
+// directly or by category, plus the groups of explicitly listed types.
+// Groups whose types are all excluded are left out.
+func (s TypeSet) SelectGroups(types map[int64]models.SDEType, groups map[int64]models.SDEGroup) []models.InvGroup {
+	if s.PublishedOnly {
+		types = FilterPublishedTypes(types)
+		groups = FilterPublishedGroups(groups)
+	}
+
+	selected := s.matchingGroupIDs(groups)
+	excluded := int64Set(s.ExcludeTypeIDs)
+	for _, typeID := range s.TypeIDs {
+		if sdeType, ok := types[typeID]; ok && !excluded[typeID] {
+			if _, ok := groups[sdeType.GroupID]; ok {
+				selected[sdeType.GroupID] = true
+			}
+		}
+	}
+
+	// Count the types left in each selected group after exclusions
+	hasTypes := make(map[int64]bool)
+	kept := make(map[int64]bool)
+	for typeID, sdeType := range types {
+		if !selected[sdeType.GroupID] {
+			continue
+		}
+		hasTypes[sdeType.GroupID] = true
+		if !excluded[typeID] {
+			kept[sdeType.GroupID] = true
+		}
+	}
+	for groupID := range selected {
+		if hasTypes[groupID] && !kept[groupID] {
+			delete(selected, groupID)
+		}
+	}
+
+	result := make([]models.InvGroup, 0, len(selected))
+	for groupID := range selected {
+		result = append(result, models.InvGroup{
+			GroupID:    groupID,
+			CategoryID: groups[groupID].CategoryID,
+			GroupName:  groups[groupID].Name["en"],
+		})
+	}
+
+	// Sort by group ID for consistent output
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GroupID < result[j].GroupID
+	})
+
+	return result
+}
+
+// SelectTypes returns the types matched by a type set in invTypes format.
+func (s TypeSet) SelectTypes(types map[int64]models.SDEType, groups map[int64]models.SDEGroup) []models.InvType {
+	if s.PublishedOnly {
+		types = FilterPublishedTypes(types)
+		groups = FilterPublishedGroups(groups)
+	}
+
+	groupIDs := s.matchingGroupIDs(groups)
+	explicit := int64Set(s.TypeIDs)
+	excluded := int64Set(s.ExcludeTypeIDs)
+
+	result := make([]models.InvType, 0)
+	for typeID, sdeType := range types {
+		if excluded[typeID] || !(groupIDs[sdeType.GroupID] || explicit[typeID]) {
+			continue
+		}
+		if s.PublishedOnly {
+			if _, ok := groups[sdeType.GroupID]; !ok {
+				continue
+			}
+		}
+
+		result = append(result, models.InvType{
+			TypeID:    typeID,
+			GroupID:   sdeType.GroupID,
+			TypeName:  sdeType.Name["en"],
+			Mass:      sdeType.Mass,
+			Volume:    sdeType.Volume,
+			Capacity:  sdeType.Capacity,
+			Published: sdeType.Published,
+		})
+	}
+
+	// Sort by type ID for consistent output
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TypeID < result[j].TypeID
+	})
+
+	return result
+}
+
+// matchingGroupIDs returns the IDs of groups listed directly or by category.
+func (s TypeSet) matchingGroupIDs(groups map[int64]models.SDEGroup) map[int64]bool {
+	categories := int64Set(s.Categories)
+	listed := int64Set(s.Groups)
+
+	result := make(map[int64]bool)
+	for groupID, group := range groups {
+		if categories[group.CategoryID] || listed[groupID] {
+			result[groupID] = true
+		}
+	}
+	return result
+}
+
+// int64Set converts a list of IDs to a set.
+func int64Set(ids []int64) map[int64]bool {
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,187 @@
+package transformer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func typeSetTestData() (map[int64]models.SDEType, map[int64]models.SDEGroup) {
+	types := map[int64]models.SDEType{
+		587:   {GroupID: 25, Name: map[string]string{"en": "Rifter"}, Published: true},
+		588:   {GroupID: 25, Name: map[string]string{"en": "Slasher"}, Published: false},
+		35832: {GroupID: 1657, Name: map[string]string{"en": "Astrahus"}, Published: true},
+		35833: {GroupID: 1657, Name: map[string]string{"en": "Fortizar"}, Published: true},
+		47512: {GroupID: 1657, Name: map[string]string{"en": "'Moreau' Fortizar"}, Published: false},
+		33474: {GroupID: 1246, Name: map[string]string{"en": "Mobile Depot"}, Published: true},
+		30583: {GroupID: WormholeGroupID, Name: map[string]string{"en": "Wormhole A009"}},
+		2456:  {GroupID: 100, Name: map[string]string{"en": "Hobgoblin I"}, Published: true},
+	}
+	groups := map[int64]models.SDEGroup{
+		25:              {CategoryID: ShipCategoryID, Name: map[string]string{"en": "Frigate"}, Published: true},
+		26:              {CategoryID: ShipCategoryID, Name: map[string]string{"en": "Cruiser"}, Published: true},
+		1657:            {CategoryID: StructureCategoryID, Name: map[string]string{"en": "Citadel"}, Published: true},
+		1246:            {CategoryID: DeployableCategoryID, Name: map[string]string{"en": "Mobile Depot"}, Published: true},
+		WormholeGroupID: {CategoryID: 2, Name: map[string]string{"en": "Wormhole"}},
+		100:             {CategoryID: 18, Name: map[string]string{"en": "Combat Drone"}, Published: true},
+	}
+	return types, groups
+}
+
+func typeIDs(types []models.InvType) []int64 {
+	ids := make([]int64, len(types))
+	for i, t := range types {
+		ids[i] = t.TypeID
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTypeSet_SelectTypes(t *testing.T) {
+	types, groups := typeSetTestData()
+
+	tests := []struct {
+		name     string
+		set      TypeSet
+		expected []int64
+	}{
+		{"ships include unpublished", TypeSet{Categories: []int64{ShipCategoryID}}, []int64{587, 588}},
+		{"published structures", TypeSet{Categories: []int64{StructureCategoryID}, PublishedOnly: true}, []int64{35832, 35833}},
+		{"by group", TypeSet{Groups: []int64{WormholeGroupID}}, []int64{30583}},
+		{"explicit type IDs", TypeSet{Categories: []int64{DeployableCategoryID}, TypeIDs: []int64{2456}}, []int64{2456, 33474}},
+		{"exclusions", TypeSet{Categories: []int64{StructureCategoryID}, ExcludeTypeIDs: []int64{47512}}, []int64{35832, 35833}},
+		{"empty rule", TypeSet{}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := typeIDs(tt.set.SelectTypes(types, groups))
+			if !equalIDs(got, tt.expected) {
+				t.Errorf("SelectTypes() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTypeSet_SelectGroups(t *testing.T) {
+	types, groups := typeSetTestData()
+
+	ships := DefaultTypeSets()[0].SelectGroups(types, groups)
+	if len(ships) != 2 || ships[0].GroupID != 25 || ships[1].GroupID != 26 {
+		t.Fatalf("Expected ship groups [25 26] including empty Cruiser group, got %+v", ships)
+	}
+	if ships[0].GroupName != "Frigate" || ships[0].CategoryID != ShipCategoryID {
+		t.Errorf("Unexpected group fields: %+v", ships[0])
+	}
+
+	explicit := TypeSet{TypeIDs: []int64{2456}}.SelectGroups(types, groups)
+	if len(explicit) != 1 || explicit[0].GroupID != 100 {
+		t.Errorf("Expected group of explicit type, got %+v", explicit)
+	}
+
+	tests := []struct {
+		name     string
+		set      TypeSet
+		expected []int64
+	}{
+		{"partly excluded group kept", TypeSet{Categories: []int64{StructureCategoryID}, ExcludeTypeIDs: []int64{47512}}, []int64{1657}},
+		{"fully excluded group dropped", TypeSet{Categories: []int64{ShipCategoryID}, ExcludeTypeIDs: []int64{587, 588}}, []int64{26}},
+		{"excluded explicit type", TypeSet{TypeIDs: []int64{2456}, ExcludeTypeIDs: []int64{2456}}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := tt.set.SelectGroups(types, groups)
+			got := make([]int64, len(selected))
+			for i, g := range selected {
+				got[i] = g.GroupID
+			}
+			if !equalIDs(got, tt.expected) {
+				t.Errorf("SelectGroups() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadTypeSets(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "type_sets_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	rules := `typeSets:
+  - name: structures
+    categories: [65]
+    publishedOnly: true
+  - name: citadels
+    groups: [1657]
+    excludeTypeIDs: [47512]
+`
+	path := filepath.Join(tmpDir, "type_sets.yaml")
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+
+	sets, err := LoadTypeSets(path)
+	if err != nil {
+		t.Fatalf("LoadTypeSets failed: %v", err)
+	}
+
+	// The default ships set is added for invTypes
+	if len(sets) != 3 || sets[0].Name != ShipsTypeSet {
+		t.Fatalf("Expected ships set to be added first, got %+v", sets)
+	}
+	if sets[1].Name != "structures" || !sets[1].PublishedOnly || sets[1].Categories[0] != 65 {
+		t.Errorf("Unexpected structures set: %+v", sets[1])
+	}
+	if sets[2].ExcludeTypeIDs[0] != 47512 {
+		t.Errorf("Unexpected citadels set: %+v", sets[2])
+	}
+}
+
+func TestLoadTypeSets_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  string
+		errMsg string
+	}{
+		{"duplicate name", "typeSets:\n  - name: ships\n  - name: ships\n", "duplicate set"},
+		{"unsafe name", "typeSets:\n  - name: ../ships\n", "invalid set name"},
+		{"missing name", "typeSets:\n  - categories: [6]\n", "invalid set name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "type_sets_test")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %v", err)
+			}
+			defer func() { _ = os.RemoveAll(tmpDir) }()
+
+			path := filepath.Join(tmpDir, "type_sets.yaml")
+			if err := os.WriteFile(path, []byte(tt.rules), 0644); err != nil {
+				t.Fatalf("failed to write rules: %v", err)
+			}
+
+			_, err = LoadTypeSets(path)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
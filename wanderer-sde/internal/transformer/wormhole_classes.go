@@ -0,0 +1,53 @@
+package transformer
+
+import "github.com/guarzo/wanderer-sde/internal/models"
+
+// Wormhole class IDs with special meaning (see mapLocationWormholeClasses).
+const (
+	WormholeClassHighSec   = 7
+	WormholeClassLowSec    = 8
+	WormholeClassNullSec   = 9
+	WormholeClassThera     = 12
+	WormholeClassShattered = 13
+	WormholeClassPochven   = 25
+)
+
+// J-space solar system IDs occupy the 31xxxxxx range.
+const (
+	jSpaceSystemIDMin = 31000000
+	jSpaceSystemIDMax = 31999999
+)
+
+// IsJSpaceSystemID reports whether a solar system ID is in the wormhole
+// space ID range.
+func IsJSpaceSystemID(systemID int64) bool {
+	return systemID >= jSpaceSystemIDMin && systemID <= jSpaceSystemIDMax
+}
+
+// ResolveSystemWormholeClasses returns the effective wormhole class of each
+// solar system. mapLocationWormholeClasses assigns classes to regions,
+// constellations and systems; a system inherits from its constellation, and
+// the constellation from its region, unless a more specific entry exists.
+// Systems with no class at any level are omitted.
+func ResolveSystemWormholeClasses(
+	systems []models.SolarSystem,
+	classes []models.WormholeClassLocation,
+) map[int64]int64 {
+	byLocation := make(map[int64]int64, len(classes))
+	for _, c := range classes {
+		byLocation[c.LocationID] = c.WormholeClassID
+	}
+
+	resolved := make(map[int64]int64, len(systems))
+	for _, sys := range systems {
+		if classID, ok := byLocation[sys.SolarSystemID]; ok {
+			resolved[sys.SolarSystemID] = classID
+		} else if classID, ok := byLocation[sys.ConstellationID]; ok {
+			resolved[sys.SolarSystemID] = classID
+		} else if classID, ok := byLocation[sys.RegionID]; ok {
+			resolved[sys.SolarSystemID] = classID
+		}
+	}
+
+	return resolved
+}
@@ -0,0 +1,41 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestResolveSystemWormholeClasses(t *testing.T) {
+	systems := []models.SolarSystem{
+		{SolarSystemID: 30000142, ConstellationID: 20000020, RegionID: 10000002}, // region only
+		{SolarSystemID: 31000001, ConstellationID: 21000001, RegionID: 11000001}, // constellation overrides region
+		{SolarSystemID: 31000005, ConstellationID: 21000001, RegionID: 11000001}, // system overrides all
+		{SolarSystemID: 30100000, ConstellationID: 20100000, RegionID: 10100000}, // no class
+	}
+	classes := []models.WormholeClassLocation{
+		{LocationID: 10000002, WormholeClassID: 7},
+		{LocationID: 11000001, WormholeClassID: 1},
+		{LocationID: 21000001, WormholeClassID: 3},
+		{LocationID: 31000005, WormholeClassID: 12},
+	}
+
+	resolved := ResolveSystemWormholeClasses(systems, classes)
+
+	expected := map[int64]int64{
+		30000142: 7,
+		31000001: 3,
+		31000005: 12,
+	}
+	if len(resolved) != len(expected) {
+		t.Errorf("Expected %d resolved systems, got %d: %v", len(expected), len(resolved), resolved)
+	}
+	for systemID, classID := range expected {
+		if resolved[systemID] != classID {
+			t.Errorf("System %d: expected class %d, got %d", systemID, classID, resolved[systemID])
+		}
+	}
+	if _, ok := resolved[30100000]; ok {
+		t.Error("Expected system without any class to be omitted")
+	}
+}
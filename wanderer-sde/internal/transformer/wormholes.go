@@ -0,0 +1,217 @@
+package transformer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// WormholeGroupID is the group ID for wormhole types ("Wormhole" group).
+const WormholeGroupID = 988
+
+// Dogma attribute IDs carried by wormhole types.
+const (
+	AttrWormholeTargetSystemClass = 1381
+	AttrWormholeMaxStableTime     = 1382 // minutes
+	AttrWormholeMaxStableMass     = 1383
+	AttrWormholeMassRegeneration  = 1384
+	AttrWormholeMaxJumpMass       = 1385
+)
+
+// wormholeTypePrefix is the name prefix shared by all real wormhole types.
+// Types in the wormhole group without it are test/QA entries.
+const wormholeTypePrefix = "Wormhole "
+
+// wormholeDestinations maps the wormholeTargetSystemClass attribute to the
+// destination labels used in Wanderer's wormholes.json.
+var wormholeDestinations = map[int64]string{
+	1:  "c1",
+	2:  "c2",
+	3:  "c3",
+	4:  "c4",
+	5:  "c5",
+	6:  "c6",
+	7:  "hs",
+	8:  "ls",
+	9:  "ns",
+	12: "thera",
+	13: "c13",
+	14: "sentinel",
+	15: "barbican",
+	16: "vidette",
+	17: "conflux",
+	18: "redoubt",
+	25: "pochven",
+}
+
+// WormholeDestination returns the Wanderer destination label for a target
+// system class, or false if the class has no label.
+func WormholeDestination(classID int64) (string, bool) {
+	dest, ok := wormholeDestinations[classID]
+	return dest, ok
+}
+
+// wormholeName returns the Wanderer name of a wormhole type ("A009" for
+// "Wormhole A009"), or false if the type is not a real wormhole.
+func wormholeName(sdeType models.SDEType) (string, bool) {
+	if sdeType.GroupID != WormholeGroupID {
+		return "", false
+	}
+	fullName := sdeType.Name["en"]
+	if !strings.HasPrefix(fullName, wormholeTypePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(fullName, wormholeTypePrefix), true
+}
+
+// WormholeTypeNames returns the sorted, de-duplicated names of all wormhole
+// types.
+func WormholeTypeNames(types map[int64]models.SDEType) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, sdeType := range types {
+		name, ok := wormholeName(sdeType)
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadWormholeOverlay reads a Wanderer wormholes.json file to be used as the
+// source of hand-curated wormhole fields.
+func LoadWormholeOverlay(path string) ([]models.Wormhole, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wormhole overlay %s: %w", path, err)
+	}
+
+	overlay, err := models.ParseWormholes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse wormhole overlay %s: %w", path, err)
+	}
+
+	return overlay, nil
+}
+
+// GenerateWormholes builds Wanderer's wormholes.json entries from wormhole
+// types and their dogma attributes.
+//
+// Destination, lifetime and mass values come from the SDE. Src, static and
+// respawn are not in the SDE and are taken from the overlay entry with the
+// same name; overlay values also fill any attribute the SDE does not carry.
+// Overlay entries with no matching SDE type are kept as-is so hand-curated
+// data is never dropped.
+func GenerateWormholes(
+	types map[int64]models.SDEType,
+	dogma map[int64]models.SDETypeDogma,
+	overlay []models.Wormhole,
+) []models.Wormhole {
+	overlayByName := make(map[string]models.Wormhole, len(overlay))
+	for _, entry := range overlay {
+		overlayByName[entry.Name] = entry
+	}
+
+	matched := make(map[string]bool)
+	result := make([]models.Wormhole, 0)
+
+	for typeID, sdeType := range types {
+		name, ok := wormholeName(sdeType)
+		if !ok {
+			continue
+		}
+
+		curated, hasCurated := overlayByName[name]
+		if hasCurated {
+			matched[name] = true
+		}
+
+		wormhole := models.Wormhole{
+			ID:             typeID,
+			Name:           name,
+			Src:            curated.Src,
+			Dest:           curated.Dest,
+			Lifetime:       curated.Lifetime,
+			TotalMass:      curated.TotalMass,
+			MaxMassPerJump: curated.MaxMassPerJump,
+			MassRegen:      curated.MassRegen,
+			Static:         curated.Static,
+			Respawn:        curated.Respawn,
+		}
+		if wormhole.Src == nil {
+			wormhole.Src = []string{}
+		}
+
+		attrs := dogma[typeID]
+		if class, ok := attrs.Attribute(AttrWormholeTargetSystemClass); ok {
+			if dest, ok := WormholeDestination(int64(class)); ok {
+				wormhole.Dest = &dest
+			}
+		}
+		if minutes, ok := attrs.Attribute(AttrWormholeMaxStableTime); ok && minutes > 0 {
+			wormhole.Lifetime = strconv.FormatFloat(minutes/60, 'f', -1, 64)
+		}
+		if mass, ok := attrs.Attribute(AttrWormholeMaxStableMass); ok && mass > 0 {
+			wormhole.TotalMass = int64(mass)
+		}
+		if mass, ok := attrs.Attribute(AttrWormholeMaxJumpMass); ok && mass > 0 {
+			wormhole.MaxMassPerJump = int64(mass)
+		}
+		if regen, ok := attrs.Attribute(AttrWormholeMassRegeneration); ok {
+			wormhole.MassRegen = int64(regen)
+		}
+
+		result = append(result, wormhole)
+	}
+
+	// Keep overlay-only entries (e.g. wormholes missing from this SDE build)
+	for _, entry := range overlay {
+		if !matched[entry.Name] {
+			result = append(result, entry)
+		}
+	}
+
+	// Sort by name, then type ID for consistent output
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+// loadWormholeOverlay returns the configured wormhole overlay. An explicit
+// --wormhole-overlay must load. The implicit passthrough copy is skipped
+// when it does not parse: writer.CheckPassthroughFiles reports that file,
+// as an error with --strict, so it is not reported twice.
+func (t *Transformer) loadWormholeOverlay() ([]models.Wormhole, error) {
+	if t.config.WormholeOverlay != "" {
+		return LoadWormholeOverlay(t.config.WormholeOverlay)
+	}
+
+	if t.config.PassthroughDir == "" {
+		return nil, nil
+	}
+
+	path := filepath.Join(t.config.PassthroughDir, "wormholes.json")
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+
+	overlay, err := LoadWormholeOverlay(path)
+	if err != nil {
+		t.logger.Debug("skipping wormhole overlay", "path", path, "error", err)
+		return nil, nil
+	}
+	return overlay, nil
+}
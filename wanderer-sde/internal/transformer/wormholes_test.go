@@ -0,0 +1,204 @@
+package transformer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func wormholeTestTypes() map[int64]models.SDEType {
+	return map[int64]models.SDEType{
+		30583: {GroupID: WormholeGroupID, Name: map[string]string{"en": "Wormhole A009"}},
+		30584: {GroupID: WormholeGroupID, Name: map[string]string{"en": "Wormhole K162"}},
+		30585: {GroupID: WormholeGroupID, Name: map[string]string{"en": "QA Wormhole"}},
+		587:   {GroupID: 25, Name: map[string]string{"en": "Rifter"}},
+	}
+}
+
+func wormholeTestDogma() map[int64]models.SDETypeDogma {
+	return map[int64]models.SDETypeDogma{
+		30583: {DogmaAttributes: []models.SDEDogmaAttributeValue{
+			{AttributeID: AttrWormholeTargetSystemClass, Value: 13},
+			{AttributeID: AttrWormholeMaxStableTime, Value: 960},
+			{AttributeID: AttrWormholeMaxStableMass, Value: 500000000},
+			{AttributeID: AttrWormholeMaxJumpMass, Value: 5000000},
+			{AttributeID: AttrWormholeMassRegeneration, Value: 0},
+		}},
+		30584: {DogmaAttributes: []models.SDEDogmaAttributeValue{
+			{AttributeID: AttrWormholeMaxStableTime, Value: 1440},
+			{AttributeID: AttrWormholeMaxStableMass, Value: 3000000000},
+			{AttributeID: AttrWormholeMaxJumpMass, Value: 375000000},
+		}},
+	}
+}
+
+func TestGenerateWormholes(t *testing.T) {
+	result := GenerateWormholes(wormholeTestTypes(), wormholeTestDogma(), nil)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 wormholes (QA and non-wormhole types skipped), got %d", len(result))
+	}
+
+	a009 := result[0]
+	if a009.Name != "A009" || a009.ID != 30583 {
+		t.Errorf("Expected A009/30583 first, got %s/%d", a009.Name, a009.ID)
+	}
+	if a009.Dest == nil || *a009.Dest != "c13" {
+		t.Errorf("Expected A009 dest c13, got %v", a009.Dest)
+	}
+	if a009.Lifetime != "16" {
+		t.Errorf("Expected A009 lifetime 16, got %q", a009.Lifetime)
+	}
+	if a009.TotalMass != 500000000 {
+		t.Errorf("Expected A009 total mass 500000000, got %d", a009.TotalMass)
+	}
+	if a009.MaxMassPerJump != 5000000 {
+		t.Errorf("Expected A009 max mass per jump 5000000, got %d", a009.MaxMassPerJump)
+	}
+	if a009.Src == nil {
+		t.Error("Expected non-nil src so JSON encodes an empty array")
+	}
+
+	k162 := result[1]
+	if k162.Dest != nil {
+		t.Errorf("Expected K162 without target class to have nil dest, got %q", *k162.Dest)
+	}
+	if k162.Lifetime != "24" {
+		t.Errorf("Expected K162 lifetime 24, got %q", k162.Lifetime)
+	}
+}
+
+func TestGenerateWormholes_Overlay(t *testing.T) {
+	dest := "hs"
+	overlay := []models.Wormhole{
+		{
+			Name:      "A009",
+			Src:       []string{"c13"},
+			Static:    true,
+			Respawn:   []string{"static"},
+			TotalMass: 1, // stale value, SDE must win
+		},
+		{Name: "K162", Dest: &dest},
+		{ID: 99999, Name: "Z999", Src: []string{"c1"}, Lifetime: "16"},
+	}
+
+	result := GenerateWormholes(wormholeTestTypes(), wormholeTestDogma(), overlay)
+
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 wormholes (2 generated + 1 overlay-only), got %d", len(result))
+	}
+
+	byName := make(map[string]models.Wormhole)
+	for _, wh := range result {
+		byName[wh.Name] = wh
+	}
+
+	a009 := byName["A009"]
+	if !a009.Static || len(a009.Src) != 1 || a009.Src[0] != "c13" {
+		t.Errorf("Expected curated src/static on A009, got %+v", a009)
+	}
+	if len(a009.Respawn) != 1 || a009.Respawn[0] != "static" {
+		t.Errorf("Expected curated respawn on A009, got %v", a009.Respawn)
+	}
+	if a009.TotalMass != 500000000 {
+		t.Errorf("Expected SDE mass to override overlay, got %d", a009.TotalMass)
+	}
+
+	k162 := byName["K162"]
+	if k162.Dest == nil || *k162.Dest != "hs" {
+		t.Errorf("Expected overlay dest to fill missing SDE target class, got %v", k162.Dest)
+	}
+
+	if z999, ok := byName["Z999"]; !ok || z999.ID != 99999 {
+		t.Errorf("Expected overlay-only Z999 to be kept, got %+v", z999)
+	}
+}
+
+func TestLoadWormholeOverlay(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "wormhole_overlay_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	path := filepath.Join(tmpDir, "wormholes.json")
+	content := `[{"id":30583,"name":"A009","src":["c13"],"dest":"c13","lifetime":"16","static":true}]`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write overlay: %v", err)
+	}
+
+	overlay, err := LoadWormholeOverlay(path)
+	if err != nil {
+		t.Fatalf("LoadWormholeOverlay failed: %v", err)
+	}
+	if len(overlay) != 1 || overlay[0].Name != "A009" || !overlay[0].Static {
+		t.Errorf("Unexpected overlay: %+v", overlay)
+	}
+
+	badPath := filepath.Join(tmpDir, "bad.json")
+	if err := os.WriteFile(badPath, []byte(`[{"name":"A009",}]`), 0644); err != nil {
+		t.Fatalf("failed to write bad overlay: %v", err)
+	}
+	if _, err := LoadWormholeOverlay(badPath); err == nil {
+		t.Error("Expected error for malformed overlay")
+	}
+}
+
+func TestTransformer_LoadWormholeOverlay_Malformed(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "wormhole_overlay_strict_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// A trailing comma in the implicit passthrough copy
+	if err := os.WriteFile(filepath.Join(tmpDir, "wormholes.json"), []byte(`[{"name":"A009",}]`), 0644); err != nil {
+		t.Fatalf("failed to write overlay: %v", err)
+	}
+
+	// writer.CheckPassthroughFiles reports the file, so it is skipped here
+	// without a warning or an error, with or without strict mode
+	for _, strict := range []bool{false, true} {
+		tr := New(&config.Config{PassthroughDir: tmpDir, Strict: strict}, nil)
+		overlay, err := tr.loadWormholeOverlay()
+		if err != nil || overlay != nil {
+			t.Fatalf("Expected the overlay to be skipped (strict=%v), got %v (%v)", strict, overlay, err)
+		}
+		if len(tr.warnings) != 0 {
+			t.Errorf("Expected no overlay warning (strict=%v), got %v", strict, tr.warnings)
+		}
+	}
+
+	explicit := New(&config.Config{WormholeOverlay: filepath.Join(tmpDir, "wormholes.json")}, nil)
+	if _, err := explicit.loadWormholeOverlay(); err == nil {
+		t.Error("Expected an error for a malformed explicit overlay")
+	}
+}
+
+func TestWormholeDestination(t *testing.T) {
+	tests := []struct {
+		classID  int64
+		expected string
+		ok       bool
+	}{
+		{1, "c1", true},
+		{6, "c6", true},
+		{7, "hs", true},
+		{9, "ns", true},
+		{12, "thera", true},
+		{25, "pochven", true},
+		{0, "", false},
+		{99, "", false},
+	}
+
+	for _, tt := range tests {
+		dest, ok := WormholeDestination(tt.classID)
+		if dest != tt.expected || ok != tt.ok {
+			t.Errorf("WormholeDestination(%d) = (%q, %v), expected (%q, %v)",
+				tt.classID, dest, ok, tt.expected, tt.ok)
+		}
+	}
+}
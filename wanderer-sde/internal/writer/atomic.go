@@ -0,0 +1,43 @@
+package writer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// partialSuffix marks an output file that is still being written.
+const partialSuffix = ".partial"
+
+// writeFileAtomic writes a file through write, first to a temporary
+// <path>.partial file that is renamed over path once write succeeds. If
+// write fails, for example because the run was cancelled, the partial file
+// is removed and any previous file at path is left untouched.
+func writeFileAtomic(path string, perm os.FileMode, write func(io.Writer) error) (err error) {
+	tmpPath := path + partialSuffix
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", path, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if err := write(file); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -2,8 +2,11 @@
 package writer
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 
@@ -27,18 +30,22 @@ const (
 type CSVWriter struct {
 	config    *config.Config
 	outputDir string
+	generated map[string]bool // Files generated from the SDE; skipped by passthrough copy
+	files     *fileLog        // Files written so far
 }
 
-// NewCSVWriter creates a new CSVWriter with the given configuration.
-func NewCSVWriter(cfg *config.Config) *CSVWriter {
+// NewCSVWriter creates a new CSVWriter with the given configuration. A
+// nil logger discards log output.
+func NewCSVWriter(cfg *config.Config, logger *slog.Logger) *CSVWriter {
 	return &CSVWriter{
 		config:    cfg,
 		outputDir: cfg.OutputDir,
+		files:     newFileLog(logger),
 	}
 }
 
 // WriteAll writes all converted data to CSV files.
-func (w *CSVWriter) WriteAll(data *models.ConvertedData) error {
+func (w *CSVWriter) WriteAll(ctx context.Context, data *models.ConvertedData) error {
 	// Validate input data
 	if data == nil {
 		return fmt.Errorf("converted data is nil")
@@ -47,206 +54,238 @@ func (w *CSVWriter) WriteAll(data *models.ConvertedData) error {
 		return fmt.Errorf("universe data is nil")
 	}
 
+	if w.config.Reproducible {
+		Canonicalize(data)
+	}
+
 	// Ensure output directory exists
 	if err := os.MkdirAll(w.outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	if w.config.Verbose {
-		fmt.Printf("Writing CSV files to: %s\n", w.outputDir)
-	}
+	w.files.logger.Debug("writing CSV files", "dir", w.outputDir)
 
 	// Write all data files
-	if err := w.WriteSolarSystems(data.Universe.SolarSystems); err != nil {
+	if err := w.WriteSolarSystems(ctx, data.Universe.SolarSystems); err != nil {
 		return fmt.Errorf("failed to write solar systems: %w", err)
 	}
 
-	if err := w.WriteRegions(data.Universe.Regions); err != nil {
+	if err := w.WriteRegions(ctx, data.Universe.Regions); err != nil {
 		return fmt.Errorf("failed to write regions: %w", err)
 	}
 
-	if err := w.WriteConstellations(data.Universe.Constellations); err != nil {
+	if err := w.WriteConstellations(ctx, data.Universe.Constellations); err != nil {
 		return fmt.Errorf("failed to write constellations: %w", err)
 	}
 
-	if err := w.WriteWormholeClasses(data.WormholeClasses); err != nil {
+	if err := w.WriteWormholeClasses(ctx, data.WormholeClasses); err != nil {
 		return fmt.Errorf("failed to write wormhole classes: %w", err)
 	}
 
-	if err := w.WriteTypes(data.InvTypes); err != nil {
+	if err := w.WriteTypes(ctx, data.InvTypes); err != nil {
 		return fmt.Errorf("failed to write types: %w", err)
 	}
 
-	if err := w.WriteGroups(data.InvGroups); err != nil {
+	for _, set := range data.TypeSets {
+		if err := w.WriteTypeSet(ctx, set); err != nil {
+			return fmt.Errorf("failed to write type set %s: %w", set.Name, err)
+		}
+	}
+
+	if err := w.WriteGroups(ctx, data.InvGroups); err != nil {
 		return fmt.Errorf("failed to write groups: %w", err)
 	}
 
-	if err := w.WriteSystemJumps(data.SystemJumps); err != nil {
+	if err := w.WriteSystemJumps(ctx, data.SystemJumps); err != nil {
 		return fmt.Errorf("failed to write system jumps: %w", err)
 	}
 
-	if err := w.WriteNPCStations(data.NPCStations); err != nil {
+	if err := w.WriteNPCStations(ctx, data.NPCStations); err != nil {
 		return fmt.Errorf("failed to write NPC stations: %w", err)
 	}
 
-	return nil
+	for _, dataset := range data.Datasets {
+		if err := w.WriteDataset(ctx, dataset); err != nil {
+			return fmt.Errorf("failed to write dataset %s: %w", dataset.Name, err)
+		}
+	}
+
+	generated, err := writeGeneratedFiles(ctx, w.config, w.files, w.outputDir, data)
+	w.generated = generated
+	return err
 }
 
 // WriteSolarSystems writes solar system data to CSV.
-func (w *CSVWriter) WriteSolarSystems(systems []models.SolarSystem) error {
+func (w *CSVWriter) WriteSolarSystems(ctx context.Context, systems []models.SolarSystem) error {
+	if w.config.SpaceKindColumn || w.config.SecurityColumns || w.config.ConnectivityColumns {
+		headers := append([]string{}, models.CSVHeaders["mapSolarSystems"]...)
+		if w.config.SpaceKindColumn {
+			headers = append(headers, models.SolarSystemSpaceKindHeaders...)
+		}
+		if w.config.SecurityColumns {
+			headers = append(headers, models.SolarSystemSecurityHeaders...)
+		}
+		if w.config.ConnectivityColumns {
+			headers = append(headers, models.SolarSystemConnectivityHeaders...)
+		}
+		rows := make([][]string, len(systems))
+		for i, s := range systems {
+			rows[i] = s.ToCSVRow()
+			if w.config.SpaceKindColumn {
+				rows[i] = append(rows[i], s.SpaceKind)
+			}
+			if w.config.SecurityColumns {
+				rows[i] = append(rows[i], s.SecurityCSVColumns()...)
+			}
+			if w.config.ConnectivityColumns {
+				rows[i] = append(rows[i], s.ConnectivityCSVColumns()...)
+			}
+		}
+		return w.writeCSVWithHeaders(ctx, CSVFileSolarSystems, headers, rows)
+	}
+
 	rows := make([][]string, len(systems))
 	for i, s := range systems {
 		rows[i] = s.ToCSVRow()
 	}
-	return w.writeCSV(CSVFileSolarSystems, "mapSolarSystems", rows)
+	return w.writeCSV(ctx, CSVFileSolarSystems, "mapSolarSystems", rows)
 }
 
 // WriteRegions writes region data to CSV.
-func (w *CSVWriter) WriteRegions(regions []models.Region) error {
+func (w *CSVWriter) WriteRegions(ctx context.Context, regions []models.Region) error {
 	rows := make([][]string, len(regions))
 	for i, r := range regions {
 		rows[i] = r.ToCSVRow()
 	}
-	return w.writeCSV(CSVFileRegions, "mapRegions", rows)
+	return w.writeCSV(ctx, CSVFileRegions, "mapRegions", rows)
 }
 
 // WriteConstellations writes constellation data to CSV.
-func (w *CSVWriter) WriteConstellations(constellations []models.Constellation) error {
+func (w *CSVWriter) WriteConstellations(ctx context.Context, constellations []models.Constellation) error {
 	rows := make([][]string, len(constellations))
 	for i, c := range constellations {
 		rows[i] = c.ToCSVRow()
 	}
-	return w.writeCSV(CSVFileConstellations, "mapConstellations", rows)
+	return w.writeCSV(ctx, CSVFileConstellations, "mapConstellations", rows)
 }
 
 // WriteWormholeClasses writes wormhole class data to CSV.
-func (w *CSVWriter) WriteWormholeClasses(classes []models.WormholeClassLocation) error {
+func (w *CSVWriter) WriteWormholeClasses(ctx context.Context, classes []models.WormholeClassLocation) error {
 	rows := make([][]string, len(classes))
 	for i, c := range classes {
 		rows[i] = c.ToCSVRow()
 	}
-	return w.writeCSV(CSVFileWormholeClasses, "mapLocationWormholeClasses", rows)
+	return w.writeCSV(ctx, CSVFileWormholeClasses, "mapLocationWormholeClasses", rows)
 }
 
 // WriteTypes writes type data to CSV.
-func (w *CSVWriter) WriteTypes(types []models.InvType) error {
+func (w *CSVWriter) WriteTypes(ctx context.Context, types []models.InvType) error {
 	rows := make([][]string, len(types))
 	for i, t := range types {
 		rows[i] = t.ToCSVRow()
 	}
-	return w.writeCSV(CSVFileTypes, "invTypes", rows)
+	return w.writeCSV(ctx, CSVFileTypes, "invTypes", rows)
+}
+
+// WriteTypeSet writes an additional type set to its own invTypes-style CSV.
+func (w *CSVWriter) WriteTypeSet(ctx context.Context, set models.TypeSetData) error {
+	rows := make([][]string, len(set.Types))
+	for i, t := range set.Types {
+		rows[i] = t.ToCSVRow()
+	}
+	return w.writeCSV(ctx, TypeSetFile(set.Name, config.FormatCSV), "invTypes", rows)
 }
 
 // WriteGroups writes group data to CSV.
-func (w *CSVWriter) WriteGroups(groups []models.InvGroup) error {
+func (w *CSVWriter) WriteGroups(ctx context.Context, groups []models.InvGroup) error {
 	rows := make([][]string, len(groups))
 	for i, g := range groups {
 		rows[i] = g.ToCSVRow()
 	}
-	return w.writeCSV(CSVFileGroups, "invGroups", rows)
+	return w.writeCSV(ctx, CSVFileGroups, "invGroups", rows)
 }
 
 // WriteSystemJumps writes system jump data to CSV.
-func (w *CSVWriter) WriteSystemJumps(jumps []models.SystemJump) error {
+func (w *CSVWriter) WriteSystemJumps(ctx context.Context, jumps []models.SystemJump) error {
+	if w.config.EnrichedJumps {
+		headers := append(append([]string{}, models.CSVHeaders["mapSolarSystemJumps"]...), models.SystemJumpEnrichedHeaders...)
+		rows := make([][]string, len(jumps))
+		for i, j := range jumps {
+			rows[i] = append(j.ToCSVRow(), j.EnrichedCSVColumns()...)
+		}
+		return w.writeCSVWithHeaders(ctx, CSVFileSystemJumps, headers, rows)
+	}
+
 	rows := make([][]string, len(jumps))
 	for i, j := range jumps {
 		rows[i] = j.ToCSVRow()
 	}
-	return w.writeCSV(CSVFileSystemJumps, "mapSolarSystemJumps", rows)
+	return w.writeCSV(ctx, CSVFileSystemJumps, "mapSolarSystemJumps", rows)
 }
 
 // WriteNPCStations writes NPC station data to CSV.
-func (w *CSVWriter) WriteNPCStations(stations []models.NPCStation) error {
+func (w *CSVWriter) WriteNPCStations(ctx context.Context, stations []models.NPCStation) error {
 	rows := make([][]string, len(stations))
 	for i, s := range stations {
 		rows[i] = s.ToCSVRow()
 	}
-	return w.writeCSV(CSVFileNPCStations, "npcStations", rows)
+	return w.writeCSV(ctx, CSVFileNPCStations, "npcStations", rows)
 }
 
-// CopyPassthroughFiles copies community-maintained JSON files from the source directory.
-// For CSV output, we still want to copy these JSON files as they're used by Wanderer.
-func (w *CSVWriter) CopyPassthroughFiles(sourceDir string) error {
-	if sourceDir == "" {
-		return nil
-	}
-
-	if w.config.Verbose {
-		fmt.Printf("Copying passthrough files from: %s\n", sourceDir)
-	}
-
-	var copied, skipped int
-	for _, filename := range PassthroughFiles {
-		srcPath := filepath.Join(sourceDir, filename)
-		dstPath := filepath.Join(w.outputDir, filename)
-
-		// Check if source file exists
-		if _, err := os.Stat(srcPath); os.IsNotExist(err) {
-			if w.config.Verbose {
-				fmt.Printf("  Skipping %s (not found)\n", filename)
-			}
-			skipped++
-			continue
-		}
-
-		if err := copyFile(srcPath, dstPath); err != nil {
-			return fmt.Errorf("failed to copy %s: %w", filename, err)
-		}
+// CopyPassthroughFiles copies community-maintained JSON files
+// from the source directory. Files already generated from the SDE are skipped.
+func (w *CSVWriter) CopyPassthroughFiles(ctx context.Context, sourceDir string) error {
+	return copyPassthroughFiles(ctx, w.files, w.outputDir, sourceDir, w.generated)
+}
 
-		if w.config.Verbose {
-			fmt.Printf("  Copied %s\n", filename)
-		}
-		copied++
-	}
+// Files returns the names of the files written so far.
+func (w *CSVWriter) Files() []string {
+	return w.files.names()
+}
 
-	if w.config.Verbose {
-		fmt.Printf("Passthrough complete: %d copied, %d skipped\n", copied, skipped)
+// writeCSV writes data rows to a CSV file with the appropriate headers.
+func (w *CSVWriter) writeCSV(ctx context.Context, filename, headerKey string, rows [][]string) error {
+	headers, ok := models.CSVHeaders[headerKey]
+	if !ok {
+		return fmt.Errorf("no headers defined for %s", headerKey)
 	}
-
-	return nil
+	return w.writeCSVWithHeaders(ctx, filename, headers, rows)
 }
 
-// writeCSV writes data rows to a CSV file with the appropriate headers.
-func (w *CSVWriter) writeCSV(filename, headerKey string, rows [][]string) (err error) {
+// writeCSVWithHeaders writes a header row and data rows to a CSV file,
+// checking ctx between rows.
+func (w *CSVWriter) writeCSVWithHeaders(ctx context.Context, filename string, headers []string, rows [][]string) error {
 	path := filepath.Join(w.outputDir, filename)
 
-	file, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", path, err)
-	}
-	defer func() {
-		if closeErr := file.Close(); closeErr != nil && err == nil {
-			err = fmt.Errorf("failed to close file %s: %w", path, closeErr)
-		}
-	}()
+	err := writeFileAtomic(path, 0644, func(out io.Writer) error {
+		csvWriter := csv.NewWriter(out)
 
-	csvWriter := csv.NewWriter(file)
-	defer func() {
-		csvWriter.Flush()
-		if flushErr := csvWriter.Error(); flushErr != nil && err == nil {
-			err = fmt.Errorf("failed to flush CSV writer for %s: %w", path, flushErr)
+		// Write header row
+		if err := csvWriter.Write(headers); err != nil {
+			return fmt.Errorf("failed to write headers to %s: %w", path, err)
 		}
-	}()
 
-	// Write header row
-	headers, ok := models.CSVHeaders[headerKey]
-	if !ok {
-		return fmt.Errorf("no headers defined for %s", headerKey)
-	}
-	if err := csvWriter.Write(headers); err != nil {
-		return fmt.Errorf("failed to write headers to %s: %w", path, err)
-	}
+		// Write data rows
+		for _, row := range rows {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := csvWriter.Write(row); err != nil {
+				return fmt.Errorf("failed to write row to %s: %w", path, err)
+			}
+		}
 
-	// Write data rows
-	for _, row := range rows {
-		if err := csvWriter.Write(row); err != nil {
-			return fmt.Errorf("failed to write row to %s: %w", path, err)
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return fmt.Errorf("failed to flush CSV writer for %s: %w", path, err)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if w.config.Verbose {
-		fmt.Printf("  Wrote %s (%d rows)\n", filename, len(rows))
-	}
+	w.files.wrote(filename, "rows", len(rows))
 
 	return nil
 }
@@ -1,9 +1,11 @@
 package writer
 
 import (
+	"context"
 	"encoding/csv"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/guarzo/wanderer-sde/internal/config"
@@ -24,7 +26,7 @@ func TestCSVWriter_WriteAll(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := NewCSVWriter(cfg)
+	w := NewCSVWriter(cfg, nil)
 
 	// Create test data
 	sunTypeID := int64(6)
@@ -158,7 +160,7 @@ func TestCSVWriter_WriteAll(t *testing.T) {
 	}
 
 	// Write all files
-	if err := w.WriteAll(data); err != nil {
+	if err := w.WriteAll(context.Background(), data); err != nil {
 		t.Fatalf("WriteAll failed: %v", err)
 	}
 
@@ -240,7 +242,7 @@ func TestCSVWriter_SolarSystemRow(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := NewCSVWriter(cfg)
+	w := NewCSVWriter(cfg, nil)
 
 	sunTypeID := int64(6)
 	factionID := int64(500001)
@@ -256,7 +258,7 @@ func TestCSVWriter_SolarSystemRow(t *testing.T) {
 		},
 	}
 
-	if err := w.WriteSolarSystems(systems); err != nil {
+	if err := w.WriteSolarSystems(context.Background(), systems); err != nil {
 		t.Fatalf("WriteSolarSystems failed: %v", err)
 	}
 
@@ -300,6 +302,269 @@ func TestCSVWriter_SolarSystemRow(t *testing.T) {
 	}
 }
 
+func TestCSVWriter_SpaceKindColumn(t *testing.T) {
+	tests := []struct {
+		name            string
+		spaceKindColumn bool
+		expectedColumns int
+	}{
+		{"default", false, len(models.CSVHeaders["mapSolarSystems"])},
+		{"enabled", true, len(models.CSVHeaders["mapSolarSystems"]) + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "csv_space_kind_test")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %v", err)
+			}
+			defer func() { _ = os.RemoveAll(tmpDir) }()
+
+			cfg := &config.Config{
+				OutputDir:       tmpDir,
+				OutputFormat:    config.FormatCSV,
+				SpaceKindColumn: tt.spaceKindColumn,
+			}
+
+			w := NewCSVWriter(cfg, nil)
+			systems := []models.SolarSystem{
+				{SolarSystemID: 31000005, SolarSystemName: "Thera", SpaceKind: "thera"},
+			}
+			if err := w.WriteSolarSystems(context.Background(), systems); err != nil {
+				t.Fatalf("WriteSolarSystems failed: %v", err)
+			}
+
+			file, err := os.Open(filepath.Join(tmpDir, CSVFileSolarSystems))
+			if err != nil {
+				t.Fatalf("failed to open file: %v", err)
+			}
+			defer func() { _ = file.Close() }()
+
+			records, err := csv.NewReader(file).ReadAll()
+			if err != nil {
+				t.Fatalf("failed to read CSV: %v", err)
+			}
+
+			header, row := records[0], records[1]
+			if len(header) != tt.expectedColumns || len(row) != tt.expectedColumns {
+				t.Fatalf("Expected %d columns, got %d headers and %d values", tt.expectedColumns, len(header), len(row))
+			}
+			if tt.spaceKindColumn {
+				last := len(header) - 1
+				if header[last] != "spaceKind" || row[last] != "thera" {
+					t.Errorf("Expected spaceKind column with thera, got %s=%s", header[last], row[last])
+				}
+			}
+		})
+	}
+}
+
+func TestCSVWriter_SecurityColumns(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "csv_security_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	cfg := &config.Config{
+		OutputDir:       tmpDir,
+		OutputFormat:    config.FormatCSV,
+		SecurityColumns: true,
+	}
+
+	w := NewCSVWriter(cfg, nil)
+	trueSecurity := 0.9
+	systems := []models.SolarSystem{
+		{
+			SolarSystemID:   30000142,
+			SolarSystemName: "Jita",
+			Security:        0.9459131166648389,
+			TrueSecurity:    &trueSecurity,
+			DisplaySecurity: "0.9",
+			SecurityBand:    "high",
+		},
+	}
+
+	if err := w.WriteSolarSystems(context.Background(), systems); err != nil {
+		t.Fatalf("WriteSolarSystems failed: %v", err)
+	}
+
+	file, err := os.Open(filepath.Join(tmpDir, CSVFileSolarSystems))
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+
+	baseColumns := len(models.CSVHeaders["mapSolarSystems"])
+	header := records[0]
+	if len(header) != baseColumns+3 || header[baseColumns] != "trueSecurity" || header[baseColumns+2] != "securityBand" {
+		t.Fatalf("unexpected headers: %v", header)
+	}
+
+	row := records[1]
+	if row[4] != "0.9459131166648389" {
+		t.Errorf("expected raw security to be unchanged, got %s", row[4])
+	}
+	if row[baseColumns] != "0.9" || row[baseColumns+1] != "0.9" || row[baseColumns+2] != "high" {
+		t.Errorf("unexpected security columns: %v", row[baseColumns:])
+	}
+}
+
+func TestCSVWriter_ConnectivityColumns(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "csv_connectivity_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// Connectivity columns follow the security columns when both are on
+	cfg := &config.Config{
+		OutputDir:           tmpDir,
+		OutputFormat:        config.FormatCSV,
+		SecurityColumns:     true,
+		ConnectivityColumns: true,
+	}
+
+	w := NewCSVWriter(cfg, nil)
+	reachable := true
+	systems := []models.SolarSystem{
+		{
+			SolarSystemID:   30000142,
+			SolarSystemName: "Jita",
+			SecurityBand:    "high",
+			GateReachable:   &reachable,
+			ComponentID:     models.Int64PtrAlways(30000001),
+		},
+		{SolarSystemID: 31000005, SolarSystemName: "Thera"},
+	}
+
+	if err := w.WriteSolarSystems(context.Background(), systems); err != nil {
+		t.Fatalf("WriteSolarSystems failed: %v", err)
+	}
+
+	file, err := os.Open(filepath.Join(tmpDir, CSVFileSolarSystems))
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+
+	start := len(models.CSVHeaders["mapSolarSystems"]) + len(models.SolarSystemSecurityHeaders)
+	header := records[0]
+	if len(header) != start+2 || header[start] != "gateReachable" || header[start+1] != "componentID" {
+		t.Fatalf("unexpected headers: %v", header)
+	}
+	if got := records[1][start:]; got[0] != "1" || got[1] != "30000001" {
+		t.Errorf("unexpected connectivity columns: %v", got)
+	}
+	if got := records[2][start:]; got[0] != "None" || got[1] != "None" {
+		t.Errorf("expected None for unset connectivity columns, got %v", got)
+	}
+}
+
+func TestCSVWriter_EnrichedJumps(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "csv_jumps_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	jumps := []models.SystemJump{
+		{
+			FromRegionID:        10000002,
+			FromConstellationID: 20000020,
+			FromSolarSystemID:   30000142,
+			ToSolarSystemID:     30001363,
+			ToConstellationID:   20000199,
+			ToRegionID:          10000033,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		enriched bool
+		expected []string
+	}{
+		{"default", false, []string{"fromSolarSystemID,toSolarSystemID", "30000142,30001363"}},
+		{"enriched", true, []string{
+			"fromSolarSystemID,toSolarSystemID,fromRegionID,fromConstellationID,toConstellationID,toRegionID",
+			"30000142,30001363,10000002,20000020,20000199,10000033",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{OutputDir: tmpDir, OutputFormat: config.FormatCSV, EnrichedJumps: tt.enriched}
+			if err := NewCSVWriter(cfg, nil).WriteSystemJumps(context.Background(), jumps); err != nil {
+				t.Fatalf("WriteSystemJumps failed: %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(tmpDir, CSVFileSystemJumps))
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+			expected := strings.Join(tt.expected, "\n") + "\n"
+			if string(content) != expected {
+				t.Errorf("expected %q, got %q", expected, content)
+			}
+		})
+	}
+}
+
+func TestCSVWriter_WriteTypeSet(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "csv_typeset_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	cfg := &config.Config{
+		OutputDir:    tmpDir,
+		OutputFormat: config.FormatCSV,
+	}
+
+	w := NewCSVWriter(cfg, nil)
+	set := models.TypeSetData{
+		Name: "structures",
+		Types: []models.InvType{
+			{TypeID: 35832, GroupID: 1657, TypeName: "Astrahus", Mass: 1e10, Volume: 8000, Capacity: 0},
+		},
+	}
+
+	if err := w.WriteTypeSet(context.Background(), set); err != nil {
+		t.Fatalf("WriteTypeSet failed: %v", err)
+	}
+
+	file, err := os.Open(filepath.Join(tmpDir, "invTypes_structures.csv"))
+	if err != nil {
+		t.Fatalf("failed to open type set file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 rows (header + data), got %d", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(models.CSVHeaders["invTypes"], ",") {
+		t.Errorf("expected invTypes headers, got %v", records[0])
+	}
+	if records[1][0] != "35832" || records[1][2] != "Astrahus" {
+		t.Errorf("unexpected row: %v", records[1])
+	}
+}
+
 func TestCSVWriter_NullHandling(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "csv_null_test")
 	if err != nil {
@@ -313,7 +578,7 @@ func TestCSVWriter_NullHandling(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := NewCSVWriter(cfg)
+	w := NewCSVWriter(cfg, nil)
 
 	// Create system with nil optional fields
 	systems := []models.SolarSystem{
@@ -327,7 +592,7 @@ func TestCSVWriter_NullHandling(t *testing.T) {
 		},
 	}
 
-	if err := w.WriteSolarSystems(systems); err != nil {
+	if err := w.WriteSolarSystems(context.Background(), systems); err != nil {
 		t.Fatalf("WriteSolarSystems failed: %v", err)
 	}
 
@@ -419,9 +684,9 @@ func TestCSVWriter_CopyPassthroughFiles(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := NewCSVWriter(cfg)
+	w := NewCSVWriter(cfg, nil)
 
-	if err := w.CopyPassthroughFiles(srcDir); err != nil {
+	if err := w.CopyPassthroughFiles(context.Background(), srcDir); err != nil {
 		t.Fatalf("CopyPassthroughFiles failed: %v", err)
 	}
 
@@ -457,7 +722,7 @@ func TestNewWriter(t *testing.T) {
 			OutputFormat: tt.format,
 		}
 
-		w, err := NewWriter(cfg)
+		w, err := NewWriter(cfg, nil)
 
 		if tt.expectError {
 			if err == nil {
@@ -564,7 +829,7 @@ func TestCSVWriter_RegionRow(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := NewCSVWriter(cfg)
+	w := NewCSVWriter(cfg, nil)
 
 	regions := []models.Region{
 		{
@@ -573,7 +838,7 @@ func TestCSVWriter_RegionRow(t *testing.T) {
 		},
 	}
 
-	if err := w.WriteRegions(regions); err != nil {
+	if err := w.WriteRegions(context.Background(), regions); err != nil {
 		t.Fatalf("WriteRegions failed: %v", err)
 	}
 
@@ -624,7 +889,7 @@ func TestCSVWriter_ConstellationRow(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := NewCSVWriter(cfg)
+	w := NewCSVWriter(cfg, nil)
 
 	constellations := []models.Constellation{
 		{
@@ -634,7 +899,7 @@ func TestCSVWriter_ConstellationRow(t *testing.T) {
 		},
 	}
 
-	if err := w.WriteConstellations(constellations); err != nil {
+	if err := w.WriteConstellations(context.Background(), constellations); err != nil {
 		t.Fatalf("WriteConstellations failed: %v", err)
 	}
 
@@ -685,7 +950,7 @@ func TestCSVWriter_TypeRow(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := NewCSVWriter(cfg)
+	w := NewCSVWriter(cfg, nil)
 
 	types := []models.InvType{
 		{
@@ -698,7 +963,7 @@ func TestCSVWriter_TypeRow(t *testing.T) {
 		},
 	}
 
-	if err := w.WriteTypes(types); err != nil {
+	if err := w.WriteTypes(context.Background(), types); err != nil {
 		t.Fatalf("WriteTypes failed: %v", err)
 	}
 
@@ -753,7 +1018,7 @@ func TestCSVWriter_GroupRow(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := NewCSVWriter(cfg)
+	w := NewCSVWriter(cfg, nil)
 
 	groups := []models.InvGroup{
 		{
@@ -763,7 +1028,7 @@ func TestCSVWriter_GroupRow(t *testing.T) {
 		},
 	}
 
-	if err := w.WriteGroups(groups); err != nil {
+	if err := w.WriteGroups(context.Background(), groups); err != nil {
 		t.Fatalf("WriteGroups failed: %v", err)
 	}
 
@@ -815,7 +1080,7 @@ func TestCSVWriter_SystemJumpRow(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := NewCSVWriter(cfg)
+	w := NewCSVWriter(cfg, nil)
 
 	jumps := []models.SystemJump{
 		{
@@ -828,7 +1093,7 @@ func TestCSVWriter_SystemJumpRow(t *testing.T) {
 		},
 	}
 
-	if err := w.WriteSystemJumps(jumps); err != nil {
+	if err := w.WriteSystemJumps(context.Background(), jumps); err != nil {
 		t.Fatalf("WriteSystemJumps failed: %v", err)
 	}
 
@@ -879,14 +1144,14 @@ func TestCSVWriter_WormholeClassRow(t *testing.T) {
 		Verbose:      false,
 	}
 
-	w := NewCSVWriter(cfg)
+	w := NewCSVWriter(cfg, nil)
 
 	classes := []models.WormholeClassLocation{
 		{LocationID: 11000001, WormholeClassID: 7},
 		{LocationID: 31000001, WormholeClassID: 3},
 	}
 
-	if err := w.WriteWormholeClasses(classes); err != nil {
+	if err := w.WriteWormholeClasses(context.Background(), classes); err != nil {
 		t.Fatalf("WriteWormholeClasses failed: %v", err)
 	}
 
@@ -921,3 +1186,50 @@ func TestCSVWriter_WormholeClassRow(t *testing.T) {
 		t.Errorf("unexpected second row: %v", records[2])
 	}
 }
+
+func TestWriteDataset(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dataset_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	cfg := &config.Config{OutputDir: tmpDir, PrettyPrint: false}
+	dataset := models.Dataset{
+		Name:    "nearest",
+		Columns: []string{"solarSystemID", "name", "distance", "isHub", "stationID"},
+		Rows: [][]interface{}{
+			{int64(30000142), "Jita", 0.5, true, nil},
+		},
+	}
+
+	if err := NewCSVWriter(cfg, nil).WriteDataset(context.Background(), dataset); err != nil {
+		t.Fatalf("CSV WriteDataset failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "nearest.csv"))
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	expectedCSV := "solarSystemID,name,distance,isHub,stationID\n30000142,Jita,0.5,1,None\n"
+	if string(content) != expectedCSV {
+		t.Errorf("Expected CSV %q, got %q", expectedCSV, content)
+	}
+
+	if err := NewJSONWriter(cfg, nil).WriteDataset(context.Background(), dataset); err != nil {
+		t.Fatalf("JSON WriteDataset failed: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(tmpDir, "nearest.json"))
+	if err != nil {
+		t.Fatalf("failed to read JSON: %v", err)
+	}
+	expectedJSON := `[{"solarSystemID":30000142,"name":"Jita","distance":0.5,"isHub":true,"stationID":null}]` + "\n"
+	if string(content) != expectedJSON {
+		t.Errorf("Expected JSON %q, got %q", expectedJSON, content)
+	}
+
+	for _, name := range []string{"mapSolarSystems", "../escape"} {
+		if err := NewCSVWriter(cfg, nil).WriteDataset(context.Background(), models.Dataset{Name: name}); err == nil {
+			t.Errorf("Expected error for dataset name %q", name)
+		}
+	}
+}
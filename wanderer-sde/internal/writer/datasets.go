@@ -0,0 +1,92 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// datasetName restricts dataset names to characters safe in file names.
+var datasetName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// DatasetFile returns the output file name of a stage dataset, e.g.
+// nearestStations.csv.
+func DatasetFile(name string, format config.OutputFormat) string {
+	if format == config.FormatJSON {
+		return name + ".json"
+	}
+	return name + ".csv"
+}
+
+// checkDatasetName rejects dataset names that are unsafe as file names or
+// would overwrite one of the core output files.
+func checkDatasetName(name string) error {
+	if !datasetName.MatchString(name) {
+		return fmt.Errorf("invalid dataset name %q", name)
+	}
+	if _, ok := models.CSVHeaders[name]; ok {
+		return fmt.Errorf("dataset name %q clashes with a core output file", name)
+	}
+	return nil
+}
+
+// WriteDataset writes a stage dataset to its own CSV file.
+func (w *CSVWriter) WriteDataset(ctx context.Context, dataset models.Dataset) error {
+	if err := checkDatasetName(dataset.Name); err != nil {
+		return err
+	}
+	return w.writeCSVWithHeaders(ctx, DatasetFile(dataset.Name, config.FormatCSV), dataset.Columns, dataset.CSVRows())
+}
+
+// WriteDataset writes a stage dataset to its own JSON file as an array of
+// objects keyed by column name.
+func (w *JSONWriter) WriteDataset(ctx context.Context, dataset models.Dataset) error {
+	if err := checkDatasetName(dataset.Name); err != nil {
+		return err
+	}
+	records := make([]datasetRecord, len(dataset.Rows))
+	for i, row := range dataset.Rows {
+		records[i] = datasetRecord{columns: dataset.Columns, values: row}
+	}
+	return w.writeJSON(ctx, DatasetFile(dataset.Name, config.FormatJSON), records)
+}
+
+// datasetRecord is one dataset row encoded as a JSON object with its keys in
+// column order.
+type datasetRecord struct {
+	columns []string
+	values  []interface{}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r datasetRecord) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range r.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if i < len(r.values) {
+			value = r.values[i]
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(encoded)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
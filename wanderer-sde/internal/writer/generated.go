@@ -0,0 +1,93 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// Generated files that replace their passthrough counterparts. These are
+// always written as JSON in Wanderer's shape, regardless of output format.
+const (
+	FileWormholes               = "wormholes.json"
+	FileSunTypes                = "sunTypes.json"
+	FileTriglavianSystems       = "triglavianSystems.json"
+	FileShatteredConstellations = "shatteredConstellations.json"
+)
+
+// Generated files with no passthrough counterpart.
+const (
+	FileSystemEffects = "systemEffects.json"
+)
+
+// writeGeneratedFiles writes datasets derived from the SDE that Wanderer
+// otherwise receives as passthrough files. Empty datasets are skipped so
+// the passthrough copy remains the fallback. It returns the set of files
+// that replace passthrough files.
+func writeGeneratedFiles(ctx context.Context, cfg *config.Config, files *fileLog, outputDir string, data *models.ConvertedData) (map[string]bool, error) {
+	generated := make(map[string]bool)
+
+	replacements := []struct {
+		filename string
+		count    int
+		data     interface{}
+	}{
+		{FileWormholes, len(data.Wormholes), data.Wormholes},
+		{FileSunTypes, len(data.SunTypes), data.SunTypes},
+		{FileTriglavianSystems, len(data.TriglavianSystems), data.TriglavianSystems},
+		{FileShatteredConstellations, len(data.ShatteredConstellations), data.ShatteredConstellations},
+	}
+	for _, r := range replacements {
+		if r.count == 0 {
+			continue
+		}
+		if err := writeJSONFile(ctx, filepath.Join(outputDir, r.filename), r.data, cfg.PrettyPrint); err != nil {
+			return generated, fmt.Errorf("failed to write %s: %w", r.filename, err)
+		}
+		generated[r.filename] = true
+		files.wrote(r.filename, "entries", r.count, "generated", true)
+	}
+
+	if len(data.SystemEffects) > 0 {
+		if err := writeJSONFile(ctx, filepath.Join(outputDir, FileSystemEffects), data.SystemEffects, cfg.PrettyPrint); err != nil {
+			return generated, fmt.Errorf("failed to write system effects: %w", err)
+		}
+		files.wrote(FileSystemEffects, "systems", len(data.SystemEffects), "generated", true)
+	}
+
+	if data.DistanceMatrix != nil {
+		if err := writeDistanceMatrixFile(ctx, filepath.Join(outputDir, FileDistanceMatrix), data.DistanceMatrix); err != nil {
+			return generated, fmt.Errorf("failed to write distance matrix: %w", err)
+		}
+		files.wrote(FileDistanceMatrix, "systems", len(data.DistanceMatrix.SystemIDs), "generated", true)
+	}
+
+	return generated, nil
+}
+
+// writeJSONFile marshals data to JSON and writes it to path.
+func writeJSONFile(ctx context.Context, path string, data interface{}, pretty bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, 0644, func(out io.Writer) error {
+		encoder := json.NewEncoder(out)
+		if pretty {
+			encoder.SetIndent("", "  ")
+		}
+
+		if err := encoder.Encode(data); err != nil {
+			return fmt.Errorf("failed to encode JSON to %s: %w", path, err)
+		}
+
+		// Encoding a large table takes a while; don't move it into place
+		// if the run was cancelled meanwhile
+		return ctx.Err()
+	})
+}
@@ -0,0 +1,120 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestWriteAll_GeneratedWormholesReplacePassthrough(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "generated_src")
+	if err != nil {
+		t.Fatalf("failed to create src temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(srcDir) }()
+
+	dstDir, err := os.MkdirTemp("", "generated_dst")
+	if err != nil {
+		t.Fatalf("failed to create dst temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(dstDir) }()
+
+	for _, filename := range []string{"wormholes.json", "effects.json"} {
+		if err := os.WriteFile(filepath.Join(srcDir, filename), []byte(`{"passthrough": true}`), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", filename, err)
+		}
+	}
+
+	dest := "c13"
+	data := &models.ConvertedData{
+		Universe: &models.UniverseData{},
+		Wormholes: []models.Wormhole{
+			{ID: 30583, Name: "A009", Src: []string{"c13"}, Dest: &dest, Lifetime: "16", TotalMass: 500000000},
+		},
+	}
+
+	for _, format := range []config.OutputFormat{config.FormatCSV, config.FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			cfg := &config.Config{OutputDir: dstDir, OutputFormat: format, PrettyPrint: true}
+			w, err := NewWriter(cfg, nil)
+			if err != nil {
+				t.Fatalf("NewWriter failed: %v", err)
+			}
+
+			if err := w.WriteAll(context.Background(), data); err != nil {
+				t.Fatalf("WriteAll failed: %v", err)
+			}
+			if err := w.CopyPassthroughFiles(context.Background(), srcDir); err != nil {
+				t.Fatalf("CopyPassthroughFiles failed: %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(dstDir, FileWormholes))
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", FileWormholes, err)
+			}
+			var wormholes []models.Wormhole
+			if err := json.Unmarshal(content, &wormholes); err != nil {
+				t.Fatalf("generated %s was overwritten or invalid: %v", FileWormholes, err)
+			}
+			if len(wormholes) != 1 || wormholes[0].Name != "A009" {
+				t.Errorf("Unexpected generated wormholes: %+v", wormholes)
+			}
+
+			// Non-generated passthrough files are still copied
+			if _, err := os.Stat(filepath.Join(dstDir, "effects.json")); os.IsNotExist(err) {
+				t.Error("effects.json passthrough was not copied")
+			}
+
+			// Files lists each file once, in write order, ending with the
+			// generated and copied files
+			files := w.Files()
+			if len(files) < 2 || files[len(files)-2] != FileWormholes || files[len(files)-1] != "effects.json" {
+				t.Errorf("Expected Files to end with %s, effects.json, got %v", FileWormholes, files)
+			}
+			if files[0] != GetOutputFiles(format)[0] {
+				t.Errorf("Expected Files to start with %s, got %v", GetOutputFiles(format)[0], files)
+			}
+		})
+	}
+}
+
+func TestWriteAll_NoGeneratedWormholesKeepsPassthrough(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "generated_src")
+	if err != nil {
+		t.Fatalf("failed to create src temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(srcDir) }()
+
+	dstDir, err := os.MkdirTemp("", "generated_dst")
+	if err != nil {
+		t.Fatalf("failed to create dst temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(dstDir) }()
+
+	passthrough := []byte(`{"passthrough": true}`)
+	if err := os.WriteFile(filepath.Join(srcDir, FileWormholes), passthrough, 0644); err != nil {
+		t.Fatalf("failed to create wormholes.json: %v", err)
+	}
+
+	cfg := &config.Config{OutputDir: dstDir, OutputFormat: config.FormatCSV}
+	w := NewCSVWriter(cfg, nil)
+	if err := w.WriteAll(context.Background(), &models.ConvertedData{Universe: &models.UniverseData{}}); err != nil {
+		t.Fatalf("WriteAll failed: %v", err)
+	}
+	if err := w.CopyPassthroughFiles(context.Background(), srcDir); err != nil {
+		t.Fatalf("CopyPassthroughFiles failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dstDir, FileWormholes))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", FileWormholes, err)
+	}
+	if string(content) != string(passthrough) {
+		t.Errorf("Expected passthrough fallback content, got %s", content)
+	}
+}
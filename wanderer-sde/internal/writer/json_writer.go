@@ -2,9 +2,10 @@
 package writer
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 
@@ -24,37 +25,28 @@ const (
 	FileNPCStations     = "npcStations.json"
 )
 
-// PassthroughFiles lists the community-maintained JSON files to copy.
-var PassthroughFiles = []string{
-	"wormholes.json",
-	"wormholeClasses.json",
-	"wormholeClassesInfo.json",
-	"wormholeSystems.json",
-	"triglavianSystems.json",
-	"effects.json",
-	"shatteredConstellations.json",
-	"sunTypes.json",
-	"triglavianEffectsByFaction.json",
-}
-
 // JSONWriter handles writing converted data to JSON files.
 type JSONWriter struct {
 	config    *config.Config
 	outputDir string
+	generated map[string]bool // Files generated from the SDE; skipped by passthrough copy
+	files     *fileLog        // Files written so far
 	pretty    bool
 }
 
-// NewJSONWriter creates a new JSONWriter with the given configuration.
-func NewJSONWriter(cfg *config.Config) *JSONWriter {
+// NewJSONWriter creates a new JSONWriter with the given configuration. A
+// nil logger discards log output.
+func NewJSONWriter(cfg *config.Config, logger *slog.Logger) *JSONWriter {
 	return &JSONWriter{
 		config:    cfg,
 		outputDir: cfg.OutputDir,
+		files:     newFileLog(logger),
 		pretty:    cfg.PrettyPrint,
 	}
 }
 
 // WriteAll writes all converted data to JSON files.
-func (w *JSONWriter) WriteAll(data *models.ConvertedData) error {
+func (w *JSONWriter) WriteAll(ctx context.Context, data *models.ConvertedData) error {
 	// Validate input data
 	if data == nil {
 		return fmt.Errorf("converted data is nil")
@@ -63,158 +55,137 @@ func (w *JSONWriter) WriteAll(data *models.ConvertedData) error {
 		return fmt.Errorf("universe data is nil")
 	}
 
+	if w.config.Reproducible {
+		Canonicalize(data)
+	}
+
 	// Ensure output directory exists
 	if err := os.MkdirAll(w.outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	if w.config.Verbose {
-		fmt.Printf("Writing JSON files to: %s\n", w.outputDir)
-	}
+	w.files.logger.Debug("writing JSON files", "dir", w.outputDir)
 
 	// Write universe data files
-	if err := w.WriteSolarSystems(data.Universe.SolarSystems); err != nil {
+	if err := w.WriteSolarSystems(ctx, data.Universe.SolarSystems); err != nil {
 		return fmt.Errorf("failed to write solar systems: %w", err)
 	}
 
-	if err := w.WriteRegions(data.Universe.Regions); err != nil {
+	if err := w.WriteRegions(ctx, data.Universe.Regions); err != nil {
 		return fmt.Errorf("failed to write regions: %w", err)
 	}
 
-	if err := w.WriteConstellations(data.Universe.Constellations); err != nil {
+	if err := w.WriteConstellations(ctx, data.Universe.Constellations); err != nil {
 		return fmt.Errorf("failed to write constellations: %w", err)
 	}
 
-	if err := w.WriteWormholeClasses(data.WormholeClasses); err != nil {
+	if err := w.WriteWormholeClasses(ctx, data.WormholeClasses); err != nil {
 		return fmt.Errorf("failed to write wormhole classes: %w", err)
 	}
 
-	if err := w.WriteTypes(data.InvTypes); err != nil {
+	if err := w.WriteTypes(ctx, data.InvTypes); err != nil {
 		return fmt.Errorf("failed to write types: %w", err)
 	}
 
-	if err := w.WriteGroups(data.InvGroups); err != nil {
+	for _, set := range data.TypeSets {
+		if err := w.WriteTypeSet(ctx, set); err != nil {
+			return fmt.Errorf("failed to write type set %s: %w", set.Name, err)
+		}
+	}
+
+	if err := w.WriteGroups(ctx, data.InvGroups); err != nil {
 		return fmt.Errorf("failed to write groups: %w", err)
 	}
 
-	if err := w.WriteSystemJumps(data.SystemJumps); err != nil {
+	if err := w.WriteSystemJumps(ctx, data.SystemJumps); err != nil {
 		return fmt.Errorf("failed to write system jumps: %w", err)
 	}
 
-	if err := w.WriteNPCStations(data.NPCStations); err != nil {
+	if err := w.WriteNPCStations(ctx, data.NPCStations); err != nil {
 		return fmt.Errorf("failed to write NPC stations: %w", err)
 	}
 
-	return nil
+	for _, dataset := range data.Datasets {
+		if err := w.WriteDataset(ctx, dataset); err != nil {
+			return fmt.Errorf("failed to write dataset %s: %w", dataset.Name, err)
+		}
+	}
+
+	generated, err := writeGeneratedFiles(ctx, w.config, w.files, w.outputDir, data)
+	w.generated = generated
+	return err
 }
 
 // WriteSolarSystems writes solar system data to JSON.
-func (w *JSONWriter) WriteSolarSystems(systems []models.SolarSystem) error {
-	return w.writeJSON(FileSolarSystems, systems)
+// The spaceKind field is only written with SpaceKindColumn, as in CSV.
+func (w *JSONWriter) WriteSolarSystems(ctx context.Context, systems []models.SolarSystem) error {
+	if !w.config.SpaceKindColumn {
+		systems = append([]models.SolarSystem(nil), systems...)
+		for i := range systems {
+			systems[i].SpaceKind = ""
+		}
+	}
+	return w.writeJSON(ctx, FileSolarSystems, systems)
 }
 
 // WriteRegions writes region data to JSON.
-func (w *JSONWriter) WriteRegions(regions []models.Region) error {
-	return w.writeJSON(FileRegions, regions)
+func (w *JSONWriter) WriteRegions(ctx context.Context, regions []models.Region) error {
+	return w.writeJSON(ctx, FileRegions, regions)
 }
 
 // WriteConstellations writes constellation data to JSON.
-func (w *JSONWriter) WriteConstellations(constellations []models.Constellation) error {
-	return w.writeJSON(FileConstellations, constellations)
+func (w *JSONWriter) WriteConstellations(ctx context.Context, constellations []models.Constellation) error {
+	return w.writeJSON(ctx, FileConstellations, constellations)
 }
 
 // WriteWormholeClasses writes wormhole class data to JSON.
-func (w *JSONWriter) WriteWormholeClasses(classes []models.WormholeClassLocation) error {
-	return w.writeJSON(FileWormholeClasses, classes)
+func (w *JSONWriter) WriteWormholeClasses(ctx context.Context, classes []models.WormholeClassLocation) error {
+	return w.writeJSON(ctx, FileWormholeClasses, classes)
 }
 
 // WriteTypes writes type data to JSON.
-func (w *JSONWriter) WriteTypes(types []models.InvType) error {
-	return w.writeJSON(FileShipTypes, types)
+func (w *JSONWriter) WriteTypes(ctx context.Context, types []models.InvType) error {
+	return w.writeJSON(ctx, FileShipTypes, types)
+}
+
+// WriteTypeSet writes an additional type set to its own invTypes-style JSON.
+func (w *JSONWriter) WriteTypeSet(ctx context.Context, set models.TypeSetData) error {
+	return w.writeJSON(ctx, TypeSetFile(set.Name, config.FormatJSON), set.Types)
 }
 
 // WriteGroups writes group data to JSON.
-func (w *JSONWriter) WriteGroups(groups []models.InvGroup) error {
-	return w.writeJSON(FileItemGroups, groups)
+func (w *JSONWriter) WriteGroups(ctx context.Context, groups []models.InvGroup) error {
+	return w.writeJSON(ctx, FileItemGroups, groups)
 }
 
 // WriteSystemJumps writes system jump data to JSON.
-func (w *JSONWriter) WriteSystemJumps(jumps []models.SystemJump) error {
-	return w.writeJSON(FileSystemJumps, jumps)
+func (w *JSONWriter) WriteSystemJumps(ctx context.Context, jumps []models.SystemJump) error {
+	return w.writeJSON(ctx, FileSystemJumps, jumps)
 }
 
 // WriteNPCStations writes NPC station data to JSON.
-func (w *JSONWriter) WriteNPCStations(stations []models.NPCStation) error {
-	return w.writeJSON(FileNPCStations, stations)
+func (w *JSONWriter) WriteNPCStations(ctx context.Context, stations []models.NPCStation) error {
+	return w.writeJSON(ctx, FileNPCStations, stations)
 }
 
-// CopyPassthroughFiles copies community-maintained JSON files from the source directory.
-func (w *JSONWriter) CopyPassthroughFiles(sourceDir string) error {
-	if sourceDir == "" {
-		return nil
-	}
-
-	if w.config.Verbose {
-		fmt.Printf("Copying passthrough files from: %s\n", sourceDir)
-	}
-
-	var copied, skipped int
-	for _, filename := range PassthroughFiles {
-		srcPath := filepath.Join(sourceDir, filename)
-		dstPath := filepath.Join(w.outputDir, filename)
-
-		// Check if source file exists
-		if _, err := os.Stat(srcPath); os.IsNotExist(err) {
-			if w.config.Verbose {
-				fmt.Printf("  Skipping %s (not found)\n", filename)
-			}
-			skipped++
-			continue
-		}
-
-		if err := copyFile(srcPath, dstPath); err != nil {
-			return fmt.Errorf("failed to copy %s: %w", filename, err)
-		}
-
-		if w.config.Verbose {
-			fmt.Printf("  Copied %s\n", filename)
-		}
-		copied++
-	}
-
-	if w.config.Verbose {
-		fmt.Printf("Passthrough complete: %d copied, %d skipped\n", copied, skipped)
-	}
+// CopyPassthroughFiles copies community-maintained JSON files
+// from the source directory. Files already generated from the SDE are skipped.
+func (w *JSONWriter) CopyPassthroughFiles(ctx context.Context, sourceDir string) error {
+	return copyPassthroughFiles(ctx, w.files, w.outputDir, sourceDir, w.generated)
+}
 
-	return nil
+// Files returns the names of the files written so far.
+func (w *JSONWriter) Files() []string {
+	return w.files.names()
 }
 
 // writeJSON marshals data to JSON and writes it to a file.
-func (w *JSONWriter) writeJSON(filename string, data interface{}) (err error) {
-	path := filepath.Join(w.outputDir, filename)
-
-	file, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", path, err)
-	}
-	defer func() {
-		if closeErr := file.Close(); closeErr != nil && err == nil {
-			err = fmt.Errorf("failed to close file %s: %w", path, closeErr)
-		}
-	}()
-
-	encoder := json.NewEncoder(file)
-	if w.pretty {
-		encoder.SetIndent("", "  ")
-	}
-
-	if err := encoder.Encode(data); err != nil {
-		return fmt.Errorf("failed to encode JSON to %s: %w", path, err)
+func (w *JSONWriter) writeJSON(ctx context.Context, filename string, data interface{}) error {
+	if err := writeJSONFile(ctx, filepath.Join(w.outputDir, filename), data, w.pretty); err != nil {
+		return err
 	}
 
-	if w.config.Verbose {
-		fmt.Printf("  Wrote %s\n", filename)
-	}
+	w.files.wrote(filename)
 
 	return nil
 }
@@ -233,14 +204,8 @@ func copyFile(src, dst string) error {
 		return err
 	}
 
-	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, srcInfo.Mode())
-	if err != nil {
+	return writeFileAtomic(dst, srcInfo.Mode(), func(out io.Writer) error {
+		_, err := io.Copy(out, srcFile)
 		return err
-	}
-	defer func() { _ = dstFile.Close() }()
-
-	if _, err = io.Copy(dstFile, srcFile); err != nil {
-		return err
-	}
-	return dstFile.Sync()
+	})
 }
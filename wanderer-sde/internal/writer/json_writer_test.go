@@ -1,9 +1,11 @@
 package writer
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/guarzo/wanderer-sde/internal/config"
@@ -24,7 +26,7 @@ func TestJSONWriter_WriteAll(t *testing.T) {
 		Verbose:     false,
 	}
 
-	w := NewJSONWriter(cfg)
+	w := NewJSONWriter(cfg, nil)
 
 	// Create test data
 	sunTypeID := int64(6)
@@ -66,7 +68,7 @@ func TestJSONWriter_WriteAll(t *testing.T) {
 	}
 
 	// Write all files
-	if err := w.WriteAll(data); err != nil {
+	if err := w.WriteAll(context.Background(), data); err != nil {
 		t.Fatalf("WriteAll failed: %v", err)
 	}
 
@@ -138,9 +140,9 @@ func TestJSONWriter_CopyPassthroughFiles(t *testing.T) {
 		Verbose:     false,
 	}
 
-	w := NewJSONWriter(cfg)
+	w := NewJSONWriter(cfg, nil)
 
-	if err := w.CopyPassthroughFiles(srcDir); err != nil {
+	if err := w.CopyPassthroughFiles(context.Background(), srcDir); err != nil {
 		t.Fatalf("CopyPassthroughFiles failed: %v", err)
 	}
 
@@ -183,10 +185,10 @@ func TestJSONWriter_PrettyPrint(t *testing.T) {
 				Verbose:     false,
 			}
 
-			w := NewJSONWriter(cfg)
+			w := NewJSONWriter(cfg, nil)
 			data := []models.Region{{RegionID: 1, RegionName: "Test"}}
 
-			if err := w.WriteRegions(data); err != nil {
+			if err := w.WriteRegions(context.Background(), data); err != nil {
 				t.Fatalf("WriteRegions failed: %v", err)
 			}
 
@@ -229,3 +231,42 @@ func TestJSONWriter_PrettyPrint(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONWriter_SpaceKindField(t *testing.T) {
+	tests := []struct {
+		name            string
+		spaceKindColumn bool
+		expected        bool
+	}{
+		{"default", false, false},
+		{"enabled", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "json_space_kind_test")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %v", err)
+			}
+			defer func() { _ = os.RemoveAll(tmpDir) }()
+
+			cfg := &config.Config{OutputDir: tmpDir, SpaceKindColumn: tt.spaceKindColumn}
+			w := NewJSONWriter(cfg, nil)
+			systems := []models.SolarSystem{{SolarSystemID: 31000005, SolarSystemName: "Thera", SpaceKind: "thera"}}
+			if err := w.WriteSolarSystems(context.Background(), systems); err != nil {
+				t.Fatalf("WriteSolarSystems failed: %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(tmpDir, FileSolarSystems))
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			if got := strings.Contains(string(content), `"spaceKind"`); got != tt.expected {
+				t.Errorf("Expected spaceKind field %v, got %v in %s", tt.expected, got, content)
+			}
+			if systems[0].SpaceKind != "thera" {
+				t.Error("Expected the input systems to be left unchanged")
+			}
+		})
+	}
+}
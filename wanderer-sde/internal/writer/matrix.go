@@ -0,0 +1,124 @@
+package writer
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// FileDistanceMatrix is the binary all-pairs gate jump matrix.
+const FileDistanceMatrix = "systemDistanceMatrix.bin"
+
+// distanceMatrixMagic starts every distance matrix file.
+const distanceMatrixMagic = "WSDM"
+
+// MaxDistanceMatrixSystems bounds the system count ReadDistanceMatrix
+// accepts, about three times the gate-connected systems of New Eden, so a
+// corrupt header cannot make it allocate gigabytes.
+const MaxDistanceMatrixSystems = 16384
+
+// WriteDistanceMatrix encodes a distance matrix. All integers are little
+// endian:
+//
+//	4 bytes      "WSDM"
+//	uint32       N, the number of systems
+//	N x int64    solar system IDs, the row and column order
+//	N x N uint8  jumps, row-major; 255 means unreachable
+func WriteDistanceMatrix(w io.Writer, m *models.DistanceMatrix) error {
+	n := len(m.SystemIDs)
+	if len(m.Jumps) != n*n {
+		return fmt.Errorf("distance matrix has %d cells for %d systems", len(m.Jumps), n)
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(distanceMatrixMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(bw, binary.LittleEndian, uint32(n)); err != nil {
+		return err
+	}
+	if err := binary.Write(bw, binary.LittleEndian, m.SystemIDs); err != nil {
+		return err
+	}
+	if _, err := bw.Write(m.Jumps); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ReadDistanceMatrix decodes a distance matrix written by
+// WriteDistanceMatrix. The system count in the header is checked against
+// MaxDistanceMatrixSystems and, if r is an io.Seeker such as a file,
+// against the bytes left before anything is allocated.
+func ReadDistanceMatrix(r io.Reader) (*models.DistanceMatrix, error) {
+	remaining := int64(-1)
+	if s, ok := r.(io.Seeker); ok {
+		var err error
+		if remaining, err = remainingBytes(s); err != nil {
+			return nil, fmt.Errorf("failed to read distance matrix size: %w", err)
+		}
+	}
+
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(distanceMatrixMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, fmt.Errorf("failed to read distance matrix header: %w", err)
+	}
+	if string(magic) != distanceMatrixMagic {
+		return nil, fmt.Errorf("not a distance matrix file")
+	}
+
+	var n uint32
+	if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+		return nil, fmt.Errorf("failed to read distance matrix size: %w", err)
+	}
+	if n > MaxDistanceMatrixSystems {
+		return nil, fmt.Errorf("distance matrix has %d systems, more than the maximum of %d", n, MaxDistanceMatrixSystems)
+	}
+	size := int64(len(distanceMatrixMagic)) + 4 + 8*int64(n) + int64(n)*int64(n)
+	if remaining >= 0 && remaining < size {
+		return nil, fmt.Errorf("distance matrix of %d systems needs %d bytes, got %d", n, size, remaining)
+	}
+	m := &models.DistanceMatrix{
+		SystemIDs: make([]int64, n),
+		Jumps:     make([]uint8, int(n)*int(n)),
+	}
+	if err := binary.Read(br, binary.LittleEndian, m.SystemIDs); err != nil {
+		return nil, fmt.Errorf("failed to read distance matrix index: %w", err)
+	}
+	if _, err := io.ReadFull(br, m.Jumps); err != nil {
+		return nil, fmt.Errorf("failed to read distance matrix: %w", err)
+	}
+	return m, nil
+}
+
+// remainingBytes returns the number of bytes between the current offset of
+// s and its end, leaving the offset unchanged.
+func remainingBytes(s io.Seeker) (int64, error) {
+	offset, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := s.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return end - offset, nil
+}
+
+// writeDistanceMatrixFile writes a distance matrix to path.
+func writeDistanceMatrixFile(ctx context.Context, path string, m *models.DistanceMatrix) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, 0644, func(out io.Writer) error {
+		return WriteDistanceMatrix(out, m)
+	})
+}
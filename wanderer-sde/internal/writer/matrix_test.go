@@ -0,0 +1,95 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestDistanceMatrix_RoundTrip(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "matrix-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	m := &models.DistanceMatrix{
+		SystemIDs: []int64{30000142, 30000144, 31000005},
+		Jumps: []uint8{
+			0, 1, models.UnreachableJumps,
+			1, 0, models.UnreachableJumps,
+			models.UnreachableJumps, models.UnreachableJumps, 0,
+		},
+	}
+	path := filepath.Join(tmpDir, FileDistanceMatrix)
+	if err := writeDistanceMatrixFile(context.Background(), path, m); err != nil {
+		t.Fatalf("writeDistanceMatrixFile failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat matrix: %v", err)
+	}
+	if want := int64(4 + 4 + 3*8 + 3*3); info.Size() != want {
+		t.Errorf("Expected %d bytes, got %d", want, info.Size())
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open matrix: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	got, err := ReadDistanceMatrix(f)
+	if err != nil {
+		t.Fatalf("ReadDistanceMatrix failed: %v", err)
+	}
+	if !reflect.DeepEqual(got.SystemIDs, m.SystemIDs) || !reflect.DeepEqual(got.Jumps, m.Jumps) {
+		t.Errorf("Expected %+v, got %+v", m, got)
+	}
+	if jumps, ok := got.JumpsBetween(30000144, 30000142); !ok || jumps != 1 {
+		t.Errorf("Expected 1 jump, got %d (%v)", jumps, ok)
+	}
+	if _, ok := got.JumpsBetween(30000142, 31000005); ok {
+		t.Error("Expected no route to an unreachable system")
+	}
+}
+
+func TestReadDistanceMatrix_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		contains string
+	}{
+		{"empty", nil, "header"},
+		{"bad magic", []byte("NOPE\x01\x00\x00\x00"), "not a distance matrix"},
+		{"truncated", []byte("WSDM\x01\x00\x00\x00\x01"), "needs 17 bytes, got 9"},
+		{"size beyond file", []byte("WSDM\x00\x10\x00\x00\x01"), "needs"},
+		{"size beyond maximum", []byte("WSDM\xff\xff\xff\xff\x01"), "maximum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadDistanceMatrix(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got %v", tt.contains, err)
+			}
+		})
+	}
+
+	// A plain reader cannot be measured, the maximum still applies
+	huge := bytes.NewBufferString("WSDM\xff\xff\xff\xff")
+	if _, err := ReadDistanceMatrix(huge); err == nil || !strings.Contains(err.Error(), "maximum") {
+		t.Errorf("Expected maximum size error, got %v", err)
+	}
+
+	bad := &models.DistanceMatrix{SystemIDs: []int64{1, 2}, Jumps: []uint8{0}}
+	if err := WriteDistanceMatrix(&bytes.Buffer{}, bad); err == nil {
+		t.Error("Expected an error for a matrix with the wrong number of cells")
+	}
+}
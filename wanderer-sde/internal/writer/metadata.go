@@ -0,0 +1,77 @@
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/downloader"
+	"github.com/guarzo/wanderer-sde/internal/overrides"
+)
+
+// SDEMetadata contains metadata about the SDE conversion.
+type SDEMetadata struct {
+	SDEVersion  string `json:"sde_version"`
+	ReleaseDate string `json:"release_date"`
+	GeneratedBy string `json:"generated_by"`
+	GeneratedAt string `json:"generated_at"`
+	Source      string `json:"source"`
+	// Overrides lists the local patches applied to the converted data.
+	Overrides []overrides.AppliedPatch `json:"overrides,omitempty"`
+}
+
+// MetadataFileName is the name of the metadata output file.
+const MetadataFileName = "sde_metadata.json"
+
+// MetadataTime returns the generation time recorded in the metadata file.
+// In reproducible mode it is SOURCE_DATE_EPOCH, falling back to the SDE
+// release date and then to the Unix epoch, so reruns on the same input
+// write the same metadata.
+func MetadataTime(cfg *config.Config, versionInfo *downloader.VersionInfo) (string, error) {
+	if !cfg.Reproducible {
+		return time.Now().UTC().Format(time.RFC3339), nil
+	}
+
+	epoch, ok, err := config.SourceDateEpoch()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case ok:
+		return epoch.Format(time.RFC3339), nil
+	case versionInfo != nil && versionInfo.ReleaseDate != "":
+		return versionInfo.ReleaseDate, nil
+	default:
+		return time.Unix(0, 0).UTC().Format(time.RFC3339), nil
+	}
+}
+
+// WriteMetadata writes the SDE metadata file to the output directory.
+// versionInfo may be nil when the SDE was not downloaded.
+func WriteMetadata(outputDir, generatedAt string, versionInfo *downloader.VersionInfo, applied []overrides.AppliedPatch) error {
+	metadata := SDEMetadata{
+		GeneratedBy: "wanderer-sde",
+		GeneratedAt: generatedAt,
+		Source:      "https://developers.eveonline.com/static-data",
+		Overrides:   applied,
+	}
+	if versionInfo != nil {
+		metadata.SDEVersion = versionInfo.BuildNumber
+		metadata.ReleaseDate = versionInfo.ReleaseDate
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	metadataPath := filepath.Join(outputDir, MetadataFileName)
+	if err := os.WriteFile(metadataPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write metadata file: %w", err)
+	}
+
+	return nil
+}
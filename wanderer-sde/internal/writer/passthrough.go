@@ -0,0 +1,335 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// CodePassthroughViolation is the finding code of a passthrough file that
+// does not match its schema or refers to data missing from the SDE.
+const CodePassthroughViolation models.FindingCode = "PASSTHROUGH_VIOLATION"
+
+// PassthroughFiles lists the community-maintained JSON files to copy.
+var PassthroughFiles = []string{
+	"wormholes.json",
+	"wormholeClasses.json",
+	"wormholeClassesInfo.json",
+	"wormholeSystems.json",
+	"triglavianSystems.json",
+	"effects.json",
+	"shatteredConstellations.json",
+	"sunTypes.json",
+	"triglavianEffectsByFaction.json",
+}
+
+// passthroughField names a field of each array element. Wanderer's files are
+// not consistent about key casing, so alternative spellings are listed.
+type passthroughField []string
+
+// Fields shared by several passthrough schemas.
+var (
+	fieldSolarSystemID   = passthroughField{"solarSystemID", "solarSystemId", "solar_system_id", "id"}
+	fieldConstellationID = passthroughField{"constellationID", "constellationId", "constellation_id", "id"}
+	fieldTypeID          = passthroughField{"typeID", "typeId", "type_id", "id"}
+	fieldName            = passthroughField{"name"}
+)
+
+// referenceTable identifies converted data that a passthrough field must
+// refer to.
+type referenceTable int
+
+const (
+	refSolarSystems referenceTable = iota
+	refConstellations
+	refSunTypes
+	refWormholeNames
+)
+
+// passthroughRef cross-references a field against converted data.
+type passthroughRef struct {
+	table referenceTable
+	field passthroughField
+}
+
+// passthroughSchema describes the expected structure of a passthrough file.
+// A nil schema only requires the file to be valid JSON.
+type passthroughSchema struct {
+	required []passthroughField         // Fields every array element must have
+	refs     []passthroughRef           // Fields cross-referenced against converted data
+	decode   func(content []byte) error // Typed decoding the file's consumer applies
+}
+
+// passthroughSchemas describes each file in PassthroughFiles. Files with an
+// array schema must hold a top-level array of objects.
+var passthroughSchemas = map[string]*passthroughSchema{
+	"wormholes.json": {
+		required: []passthroughField{fieldName},
+		refs:     []passthroughRef{{refWormholeNames, fieldName}},
+		decode:   decodeWormholes,
+	},
+	"wormholeClasses.json":     nil,
+	"wormholeClassesInfo.json": nil,
+	"wormholeSystems.json": {
+		required: []passthroughField{fieldSolarSystemID},
+		refs:     []passthroughRef{{refSolarSystems, fieldSolarSystemID}},
+	},
+	"triglavianSystems.json": {
+		required: []passthroughField{fieldSolarSystemID},
+		refs:     []passthroughRef{{refSolarSystems, fieldSolarSystemID}},
+	},
+	"effects.json": {
+		required: []passthroughField{fieldName},
+	},
+	"shatteredConstellations.json": {
+		required: []passthroughField{fieldConstellationID},
+		refs:     []passthroughRef{{refConstellations, fieldConstellationID}},
+	},
+	"sunTypes.json": {
+		required: []passthroughField{fieldTypeID},
+		refs:     []passthroughRef{{refSunTypes, fieldTypeID}},
+	},
+	"triglavianEffectsByFaction.json": nil,
+}
+
+// ReferenceData holds the IDs and names from freshly converted data that
+// passthrough files are cross-referenced against. A nil set disables the
+// corresponding check.
+type ReferenceData struct {
+	SolarSystemIDs   map[int64]bool
+	ConstellationIDs map[int64]bool
+	SunTypeIDs       map[int64]bool
+	WormholeNames    map[string]bool
+}
+
+// NewReferenceData builds reference sets from converted data. Sun type and
+// wormhole name checks are only enabled when that data is available.
+func NewReferenceData(data *models.ConvertedData) *ReferenceData {
+	refs := &ReferenceData{
+		SolarSystemIDs:   make(map[int64]bool),
+		ConstellationIDs: make(map[int64]bool),
+	}
+
+	if data.Universe != nil {
+		for _, sys := range data.Universe.SolarSystems {
+			refs.SolarSystemIDs[sys.SolarSystemID] = true
+		}
+		for _, c := range data.Universe.Constellations {
+			refs.ConstellationIDs[c.ConstellationID] = true
+		}
+	}
+
+	if len(data.SunTypes) > 0 {
+		refs.SunTypeIDs = make(map[int64]bool, len(data.SunTypes))
+		for _, sunType := range data.SunTypes {
+			refs.SunTypeIDs[sunType.TypeID] = true
+		}
+	}
+
+	if len(data.WormholeTypeNames) > 0 {
+		refs.WormholeNames = make(map[string]bool, len(data.WormholeTypeNames))
+		for _, name := range data.WormholeTypeNames {
+			refs.WormholeNames[name] = true
+		}
+	}
+
+	return refs
+}
+
+// ValidatePassthrough parses a passthrough file, checks it against its
+// schema and cross-references it against converted data. It returns a
+// description of each violation. refs may be nil to skip cross-referencing.
+func ValidatePassthrough(filename string, content []byte, refs *ReferenceData) []string {
+	var parsed interface{}
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		return []string{fmt.Sprintf("invalid JSON: %v", err)}
+	}
+
+	schema := passthroughSchemas[filename]
+	if schema == nil {
+		return nil
+	}
+
+	entries, ok := parsed.([]interface{})
+	if !ok {
+		return []string{"expected a top-level array"}
+	}
+
+	if schema.decode != nil {
+		if err := schema.decode(content); err != nil {
+			return []string{fmt.Sprintf("invalid entries: %v", err)}
+		}
+	}
+
+	var violations []string
+	for i, raw := range entries {
+		entry, ok := raw.(map[string]interface{})
+		if !ok {
+			violations = append(violations, fmt.Sprintf("entry %d: expected an object", i))
+			continue
+		}
+
+		for _, field := range schema.required {
+			if _, ok := lookupField(entry, field); !ok {
+				violations = append(violations, fmt.Sprintf("entry %d: missing required field %q", i, field[0]))
+			}
+		}
+
+		if refs == nil {
+			continue
+		}
+		for _, ref := range schema.refs {
+			value, ok := lookupField(entry, ref.field)
+			if !ok {
+				continue
+			}
+			if violation := checkReference(ref.table, value, refs); violation != "" {
+				violations = append(violations, fmt.Sprintf("entry %d: %s", i, violation))
+			}
+		}
+	}
+
+	return violations
+}
+
+// decodeWormholes decodes wormholes.json the way the transformer's overlay
+// does, so a file it cannot use is reported here.
+func decodeWormholes(content []byte) error {
+	_, err := models.ParseWormholes(content)
+	return err
+}
+
+// lookupField returns the value of the first of field's names in entry.
+func lookupField(entry map[string]interface{}, field passthroughField) (interface{}, bool) {
+	for _, name := range field {
+		if v, ok := entry[name]; ok && v != nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// checkReference returns a violation if value does not exist in the
+// referenced table, or an empty string if it does or the check is disabled.
+func checkReference(table referenceTable, value interface{}, refs *ReferenceData) string {
+	switch table {
+	case refWormholeNames:
+		name, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("wormhole name %v is not a string", value)
+		}
+		if refs.WormholeNames != nil && !refs.WormholeNames[name] {
+			return fmt.Sprintf("wormhole type %q not found in SDE types", name)
+		}
+		return ""
+	}
+
+	number, ok := value.(float64)
+	if !ok {
+		return fmt.Sprintf("ID %v is not a number", value)
+	}
+	id := int64(number)
+
+	switch table {
+	case refSolarSystems:
+		if refs.SolarSystemIDs != nil && !refs.SolarSystemIDs[id] {
+			return fmt.Sprintf("solar system %d not found in mapSolarSystems", id)
+		}
+	case refConstellations:
+		if refs.ConstellationIDs != nil && !refs.ConstellationIDs[id] {
+			return fmt.Sprintf("constellation %d not found in mapConstellations", id)
+		}
+	case refSunTypes:
+		if refs.SunTypeIDs != nil && !refs.SunTypeIDs[id] {
+			return fmt.Sprintf("sun type %d not found in SDE sun types", id)
+		}
+	}
+	return ""
+}
+
+// CheckPassthroughFiles validates every passthrough file in sourceDir with
+// ValidatePassthrough, including files that are generated from the SDE:
+// the passthrough copy is still the source of hand-curated fields, e.g. the
+// wormhole overlay. It returns one finding per violation, errors in strict
+// mode and warnings otherwise, so callers can fail before writing anything.
+func CheckPassthroughFiles(cfg *config.Config, sourceDir string, refs *ReferenceData) ([]models.Finding, error) {
+	severity := models.SeverityWarning
+	if cfg.Strict {
+		severity = models.SeverityError
+	}
+
+	var findings []models.Finding
+	for _, filename := range PassthroughFiles {
+		content, err := os.ReadFile(filepath.Join(sourceDir, filename))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+		}
+
+		for _, violation := range ValidatePassthrough(filename, content, refs) {
+			findings = append(findings, models.Finding{
+				Code:     CodePassthroughViolation,
+				Severity: severity,
+				Table:    filename,
+				Message:  fmt.Sprintf("%s: %s", filename, violation),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// copyPassthroughFiles copies community-maintained JSON files from
+// sourceDir to outputDir. Files in generated were produced from the SDE and
+// are not overwritten. Files are not validated here; see
+// CheckPassthroughFiles.
+func copyPassthroughFiles(
+	ctx context.Context,
+	files *fileLog,
+	outputDir string,
+	sourceDir string,
+	generated map[string]bool,
+) error {
+	if sourceDir == "" {
+		return nil
+	}
+
+	files.logger.Debug("copying passthrough files", "dir", sourceDir)
+
+	var copied, skipped int
+	for _, filename := range PassthroughFiles {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		srcPath := filepath.Join(sourceDir, filename)
+		if _, err := os.Stat(srcPath); os.IsNotExist(err) {
+			files.logger.Debug("skipping passthrough file", "file", filename, "reason", "not found")
+			skipped++
+			continue
+		}
+
+		// Generated files take precedence over the passthrough copy
+		if generated[filename] {
+			files.logger.Debug("skipping passthrough file", "file", filename, "reason", "generated from SDE")
+			skipped++
+			continue
+		}
+
+		dstPath := filepath.Join(outputDir, filename)
+		if err := copyFile(srcPath, dstPath); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", filename, err)
+		}
+		files.wrote(filename, "passthrough", true)
+		copied++
+	}
+
+	files.logger.Debug("passthrough complete", "copied", copied, "skipped", skipped)
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package writer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func passthroughTestData() *models.ConvertedData {
+	return &models.ConvertedData{
+		Universe: &models.UniverseData{
+			SolarSystems:   []models.SolarSystem{{SolarSystemID: 31000001}, {SolarSystemID: 30000021}},
+			Constellations: []models.Constellation{{ConstellationID: 21000324}},
+		},
+		SunTypes:          []models.SunType{{TypeID: 3796}},
+		WormholeTypeNames: []string{"A009", "K162"},
+	}
+}
+
+func TestValidatePassthrough(t *testing.T) {
+	refs := NewReferenceData(passthroughTestData())
+
+	tests := []struct {
+		name       string
+		filename   string
+		content    string
+		violations []string
+	}{
+		{
+			name:     "valid wormholes",
+			filename: "wormholes.json",
+			content:  `[{"id": 30583, "name": "A009"}, {"id": 30642, "name": "K162"}]`,
+		},
+		{
+			name:       "trailing comma",
+			filename:   "wormholes.json",
+			content:    `[{"id": 30583, "name": "A009"},]`,
+			violations: []string{"invalid JSON"},
+		},
+		{
+			name:       "wrong wormhole field type",
+			filename:   "wormholes.json",
+			content:    `[{"name": "A009", "static": "yes"}, {"name": "Z999"}]`,
+			violations: []string{"invalid entries"},
+		},
+		{
+			name:       "unknown wormhole name",
+			filename:   "wormholes.json",
+			content:    `[{"name": "A009"}, {"name": "Z999"}]`,
+			violations: []string{`entry 1: wormhole type "Z999" not found in SDE types`},
+		},
+		{
+			name:       "missing required field",
+			filename:   "wormholes.json",
+			content:    `[{"id": 30583}]`,
+			violations: []string{`entry 0: missing required field "name"`},
+		},
+		{
+			name:       "stale system ID",
+			filename:   "wormholeSystems.json",
+			content:    `[{"id": 31000001}, {"id": 31009999}]`,
+			violations: []string{"entry 1: solar system 31009999 not found in mapSolarSystems"},
+		},
+		{
+			name:     "alternate ID key",
+			filename: "triglavianSystems.json",
+			content:  `[{"solarSystemId": 30000021}]`,
+		},
+		{
+			name:       "unknown sun type",
+			filename:   "sunTypes.json",
+			content:    `[{"typeID": 3796}, {"typeID": 45041}]`,
+			violations: []string{"entry 1: sun type 45041 not found in SDE sun types"},
+		},
+		{
+			name:       "unknown constellation",
+			filename:   "shatteredConstellations.json",
+			content:    `[{"constellationID": 21000999}]`,
+			violations: []string{"entry 0: constellation 21000999 not found in mapConstellations"},
+		},
+		{
+			name:       "object instead of array",
+			filename:   "effects.json",
+			content:    `{"test": true}`,
+			violations: []string{"expected a top-level array"},
+		},
+		{
+			name:     "unschematized file only needs valid JSON",
+			filename: "wormholeClassesInfo.json",
+			content:  `{"anything": [1, 2, 3]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := ValidatePassthrough(tt.filename, []byte(tt.content), refs)
+			if len(violations) != len(tt.violations) {
+				t.Fatalf("Expected %d violations, got %d: %v", len(tt.violations), len(violations), violations)
+			}
+			for i, expected := range tt.violations {
+				if !strings.Contains(violations[i], expected) {
+					t.Errorf("Violation %d: expected %q, got %q", i, expected, violations[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePassthrough_NoReferences(t *testing.T) {
+	// Without converted data only syntax and schema are checked
+	violations := ValidatePassthrough("wormholeSystems.json", []byte(`[{"id": 31009999}]`), nil)
+	if len(violations) != 0 {
+		t.Errorf("Expected no violations without references, got %v", violations)
+	}
+
+	// Checks for data that was not generated are disabled
+	refs := NewReferenceData(&models.ConvertedData{Universe: &models.UniverseData{}})
+	violations = ValidatePassthrough("sunTypes.json", []byte(`[{"typeID": 45041}]`), refs)
+	if len(violations) != 0 {
+		t.Errorf("Expected sun type check to be disabled without sun types, got %v", violations)
+	}
+}
+
+func TestCheckPassthroughFiles(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "passthrough_check_src")
+	if err != nil {
+		t.Fatalf("failed to create src temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(srcDir) }()
+
+	// sunTypes.json and wormholes.json are generated from the SDE but are
+	// still checked
+	files := map[string]string{
+		"effects.json":         `[{"name": "Pulsar"}]`,
+		"wormholeSystems.json": `[{"id": 31009999}]`,
+		"sunTypes.json":        `[{"typeID": 45041}]`,
+		"wormholes.json":       `[{"name": "A009"}, {"name": "Z999"}]`,
+	}
+	for filename, content := range files {
+		if err := os.WriteFile(filepath.Join(srcDir, filename), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", filename, err)
+		}
+	}
+
+	refs := NewReferenceData(passthroughTestData())
+
+	tests := []struct {
+		name     string
+		strict   bool
+		severity models.Severity
+	}{
+		{"warnings", false, models.SeverityWarning},
+		{"strict", true, models.SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Strict: tt.strict}
+			findings, err := CheckPassthroughFiles(cfg, srcDir, refs)
+			if err != nil {
+				t.Fatalf("CheckPassthroughFiles failed: %v", err)
+			}
+
+			tables := make(map[string]bool)
+			for _, f := range findings {
+				if f.Code != CodePassthroughViolation || f.Severity != tt.severity {
+					t.Errorf("Expected %s %s, got %+v", tt.severity, CodePassthroughViolation, f)
+				}
+				if !strings.HasPrefix(f.Message, f.Table+": ") {
+					t.Errorf("Expected message to name the file, got %q", f.Message)
+				}
+				tables[f.Table] = true
+			}
+			if len(findings) != 3 || !tables["wormholeSystems.json"] || !tables["sunTypes.json"] || !tables["wormholes.json"] {
+				t.Errorf("Expected one finding for each of 3 files, got %+v", findings)
+			}
+		})
+	}
+}
+
+func TestCopyPassthroughFiles_SkipsGeneratedFiles(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "passthrough_generated_src")
+	if err != nil {
+		t.Fatalf("failed to create src temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(srcDir) }()
+
+	dstDir, err := os.MkdirTemp("", "passthrough_generated_dst")
+	if err != nil {
+		t.Fatalf("failed to create dst temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(dstDir) }()
+
+	files := map[string]string{
+		"effects.json":  `[{"name": "Pulsar"}]`,
+		"sunTypes.json": `[{"typeID": 45041}]`,
+	}
+	for filename, content := range files {
+		if err := os.WriteFile(filepath.Join(srcDir, filename), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", filename, err)
+		}
+	}
+
+	cfg := &config.Config{OutputDir: dstDir, OutputFormat: config.FormatCSV}
+	w := NewCSVWriter(cfg, nil)
+	if err := w.WriteAll(context.Background(), passthroughTestData()); err != nil {
+		t.Fatalf("WriteAll failed: %v", err)
+	}
+	if err := w.CopyPassthroughFiles(context.Background(), srcDir); err != nil {
+		t.Fatalf("CopyPassthroughFiles failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dstDir, "effects.json")); err != nil {
+		t.Errorf("Expected effects.json to be copied: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dstDir, "sunTypes.json"))
+	if err != nil {
+		t.Fatalf("failed to read sunTypes.json: %v", err)
+	}
+	if strings.Contains(string(content), "45041") {
+		t.Errorf("Expected the generated sunTypes.json to be kept, got %s", content)
+	}
+}
@@ -0,0 +1,256 @@
+package writer
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+// ManifestFileName is the SHA-256 manifest written in reproducible mode. It
+// uses the sha256sum format, so `sha256sum -c SHA256SUMS` verifies the output.
+const ManifestFileName = "SHA256SUMS"
+
+// significantDigits is the float precision kept in reproducible mode. It
+// absorbs last-bit differences in derived values between platforms.
+const significantDigits = 15
+
+// Canonicalize prepares converted data for byte-for-byte reproducible
+// output: every table is sorted by its key, stage dataset rows are stably
+// sorted by the dataset's key columns, and every float is normalized with
+// NormalizeFloat.
+// Tables are normally already sorted, but patches added by overrides are
+// appended at the end.
+func Canonicalize(data *models.ConvertedData) {
+	if data.Universe != nil {
+		sort.SliceStable(data.Universe.SolarSystems, func(i, j int) bool {
+			return data.Universe.SolarSystems[i].SolarSystemID < data.Universe.SolarSystems[j].SolarSystemID
+		})
+		sort.SliceStable(data.Universe.Regions, func(i, j int) bool {
+			return data.Universe.Regions[i].RegionID < data.Universe.Regions[j].RegionID
+		})
+		sort.SliceStable(data.Universe.Constellations, func(i, j int) bool {
+			return data.Universe.Constellations[i].ConstellationID < data.Universe.Constellations[j].ConstellationID
+		})
+	}
+	sort.SliceStable(data.InvTypes, func(i, j int) bool {
+		return data.InvTypes[i].TypeID < data.InvTypes[j].TypeID
+	})
+	for _, set := range data.TypeSets {
+		sort.SliceStable(set.Types, func(i, j int) bool {
+			return set.Types[i].TypeID < set.Types[j].TypeID
+		})
+	}
+	sort.SliceStable(data.InvGroups, func(i, j int) bool {
+		return data.InvGroups[i].GroupID < data.InvGroups[j].GroupID
+	})
+	sort.SliceStable(data.WormholeClasses, func(i, j int) bool {
+		return data.WormholeClasses[i].LocationID < data.WormholeClasses[j].LocationID
+	})
+	sort.SliceStable(data.SystemJumps, func(i, j int) bool {
+		a, b := data.SystemJumps[i], data.SystemJumps[j]
+		if a.FromSolarSystemID != b.FromSolarSystemID {
+			return a.FromSolarSystemID < b.FromSolarSystemID
+		}
+		return a.ToSolarSystemID < b.ToSolarSystemID
+	})
+	sort.SliceStable(data.NPCStations, func(i, j int) bool {
+		return data.NPCStations[i].StationID < data.NPCStations[j].StationID
+	})
+	sort.SliceStable(data.Wormholes, func(i, j int) bool {
+		a, b := data.Wormholes[i], data.Wormholes[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	sort.SliceStable(data.SunTypes, func(i, j int) bool {
+		return data.SunTypes[i].TypeID < data.SunTypes[j].TypeID
+	})
+	sort.SliceStable(data.SystemEffects, func(i, j int) bool {
+		return data.SystemEffects[i].SolarSystemID < data.SystemEffects[j].SolarSystemID
+	})
+	sort.SliceStable(data.TriglavianSystems, func(i, j int) bool {
+		return data.TriglavianSystems[i].SolarSystemID < data.TriglavianSystems[j].SolarSystemID
+	})
+	sort.SliceStable(data.ShatteredConstellations, func(i, j int) bool {
+		return data.ShatteredConstellations[i].ConstellationID < data.ShatteredConstellations[j].ConstellationID
+	})
+	sort.SliceStable(data.Datasets, func(i, j int) bool {
+		return data.Datasets[i].Name < data.Datasets[j].Name
+	})
+
+	normalizeFloats(reflect.ValueOf(data))
+
+	for _, dataset := range data.Datasets {
+		sortDatasetRows(dataset)
+	}
+}
+
+// sortDatasetRows stably sorts a dataset's rows by its key columns, in the
+// order the stage writes them. Datasets without keys keep their order.
+func sortDatasetRows(dataset models.Dataset) {
+	var columns []int
+	for _, key := range dataset.Keys {
+		for i, name := range dataset.Columns {
+			if name == key {
+				columns = append(columns, i)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return
+	}
+
+	sort.SliceStable(dataset.Rows, func(i, j int) bool {
+		for _, col := range columns {
+			if c := compareValues(dataset.Rows[i][col], dataset.Rows[j][col]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// compareValues orders two dataset values: numbers by value, strings and
+// booleans naturally and nil first. Values of different types are compared
+// by their CSV form.
+func compareValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case nil:
+		if b == nil {
+			return 0
+		}
+		return -1
+	case int64:
+		if b, ok := b.(int64); ok {
+			return cmp.Compare(a, b)
+		}
+	case int:
+		if b, ok := b.(int); ok {
+			return cmp.Compare(a, b)
+		}
+	case float64:
+		if b, ok := b.(float64); ok {
+			return cmp.Compare(a, b)
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case bool:
+		if b, ok := b.(bool); ok && a != b {
+			if a {
+				return 1
+			}
+			return -1
+		}
+	}
+	if b == nil {
+		return 1
+	}
+	return strings.Compare(models.FormatValue(a), models.FormatValue(b))
+}
+
+// NormalizeFloat rounds v to 15 significant digits and turns negative zero
+// into zero, so values derived by slightly different float arithmetic are
+// written identically.
+func NormalizeFloat(v float64) float64 {
+	if v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return math.Abs(v)
+	}
+	normalized, err := strconv.ParseFloat(strconv.FormatFloat(v, 'g', significantDigits, 64), 64)
+	if err != nil {
+		return v
+	}
+	return normalized
+}
+
+// normalizeFloats applies NormalizeFloat to every float reachable from v,
+// including dataset values.
+func normalizeFloats(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if v.CanSet() {
+			v.SetFloat(NormalizeFloat(v.Float()))
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			normalizeFloats(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		if f, ok := v.Interface().(float64); ok {
+			v.Set(reflect.ValueOf(NormalizeFloat(f)))
+			return
+		}
+		normalizeFloats(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				normalizeFloats(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			normalizeFloats(v.Index(i))
+		}
+	}
+}
+
+// WriteManifest writes the SHA-256 hash of each of files, relative to
+// outputDir, to ManifestFileName, sorted by file name. Only the files a run
+// wrote are listed, so stale files left in outputDir by an earlier run are
+// not. It returns the number of files listed.
+func WriteManifest(outputDir string, files []string) (int, error) {
+	names := append([]string(nil), files...)
+	sort.Strings(names)
+
+	var lines []string
+	for i, name := range names {
+		if name == ManifestFileName || (i > 0 && name == names[i-1]) {
+			continue
+		}
+		sum, err := hashFile(filepath.Join(outputDir, name))
+		if err != nil {
+			return 0, err
+		}
+		lines = append(lines, sum+"  "+name+"\n")
+	}
+
+	err := writeFileAtomic(filepath.Join(outputDir, ManifestFileName), 0644, func(out io.Writer) error {
+		_, err := io.WriteString(out, strings.Join(lines, ""))
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return len(lines), nil
+}
+
+// hashFile returns the hex SHA-256 of a file's contents.
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
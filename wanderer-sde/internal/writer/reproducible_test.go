@@ -0,0 +1,129 @@
+package writer
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guarzo/wanderer-sde/internal/models"
+)
+
+func TestNormalizeFloat(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected float64
+	}{
+		{0.1 + 0.2, 0.3},
+		{math.Copysign(0, -1), 0},
+		{-1.5e17, -1.5e17},
+		{0.9459, 0.9459},
+	}
+
+	for _, tt := range tests {
+		result := NormalizeFloat(tt.value)
+		if result != tt.expected || math.Signbit(result) != math.Signbit(tt.expected) {
+			t.Errorf("NormalizeFloat(%v): got %v, expected %v", tt.value, result, tt.expected)
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	trueSecurity := 0.1 + 0.2
+	data := &models.ConvertedData{
+		Universe: &models.UniverseData{
+			SolarSystems: []models.SolarSystem{
+				{SolarSystemID: 30000144, Security: 0.1 + 0.2, TrueSecurity: &trueSecurity},
+				{SolarSystemID: 30000142},
+			},
+		},
+		NPCStations: []models.NPCStation{
+			{StationID: 60000002},
+			{StationID: 60000001},
+		},
+		Datasets: []models.Dataset{
+			{Name: "b", Columns: []string{"id", "value"}, Keys: []string{"id"}, Rows: [][]interface{}{{int64(2), 0.1 + 0.2}, {int64(1), 1.0}}},
+			{Name: "a"},
+			{
+				Name: "c", Columns: []string{"id", "order"}, Keys: []string{"id"},
+				Rows: [][]interface{}{{int64(10000002), "b"}, {int64(9), "a"}, {int64(10000002), "a"}},
+			},
+			{Name: "d", Columns: []string{"id"}, Rows: [][]interface{}{{int64(2)}, {int64(1)}}},
+		},
+	}
+
+	Canonicalize(data)
+
+	if data.Universe.SolarSystems[0].SolarSystemID != 30000142 {
+		t.Errorf("Expected systems sorted by ID, got %+v", data.Universe.SolarSystems)
+	}
+	if data.NPCStations[0].StationID != 60000001 {
+		t.Errorf("Expected stations sorted by ID, got %+v", data.NPCStations)
+	}
+	sys := data.Universe.SolarSystems[1]
+	if sys.Security != 0.3 || *sys.TrueSecurity != 0.3 {
+		t.Errorf("Expected normalized floats, got %v and %v", sys.Security, *sys.TrueSecurity)
+	}
+	if data.Datasets[0].Name != "a" {
+		t.Errorf("Expected datasets sorted by name, got %s first", data.Datasets[0].Name)
+	}
+	rows := data.Datasets[1].Rows
+	if rows[0][0] != int64(1) || rows[1][1] != 0.3 {
+		t.Errorf("Expected dataset rows sorted and normalized, got %v", rows)
+	}
+	// Numeric keys sort by value, ties keep their order
+	rows = data.Datasets[2].Rows
+	if rows[0][0] != int64(9) || rows[1][1] != "b" || rows[2][1] != "a" {
+		t.Errorf("Expected rows sorted numerically by key, got %v", rows)
+	}
+	rows = data.Datasets[3].Rows
+	if rows[0][0] != int64(2) {
+		t.Errorf("Expected rows of a dataset without keys to keep their order, got %v", rows)
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "manifest_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "b.csv"), []byte("abc"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.csv"), []byte(""), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	// Left over from an earlier run
+	if err := os.WriteFile(filepath.Join(tmpDir, "old_report.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	count, err := WriteManifest(tmpDir, []string{"b.csv", "a.csv"})
+	if err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 files in manifest, got %d", count)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, ManifestFileName))
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	expected := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  a.csv\n" +
+		"ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad  b.csv\n"
+	if string(content) != expected {
+		t.Errorf("Expected manifest %q, got %q", expected, content)
+	}
+
+	// Rewriting must not list the manifest itself
+	if count, _ := WriteManifest(tmpDir, []string{"a.csv", "b.csv", ManifestFileName}); count != 2 {
+		t.Errorf("Expected manifest to exclude itself, got %d files", count)
+	}
+
+	if _, err := WriteManifest(tmpDir, []string{"missing.csv"}); err == nil {
+		t.Error("Expected an error for a listed file that does not exist")
+	}
+}
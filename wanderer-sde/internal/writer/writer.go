@@ -2,28 +2,40 @@
 package writer
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 
 	"github.com/guarzo/wanderer-sde/internal/config"
+	"github.com/guarzo/wanderer-sde/internal/logging"
 	"github.com/guarzo/wanderer-sde/internal/models"
 )
 
-// Writer defines the interface for writing converted data to files.
+// Writer defines the interface for writing converted data to files. Files
+// are written to a temporary name and moved into place when complete, so a
+// cancelled or failed run never leaves a truncated output file.
 type Writer interface {
-	// WriteAll writes all converted data to output files.
-	WriteAll(data *models.ConvertedData) error
+	// WriteAll writes all converted data to output files, stopping with the
+	// context's error between files and rows once ctx is cancelled.
+	WriteAll(ctx context.Context, data *models.ConvertedData) error
 
-	// CopyPassthroughFiles copies community-maintained files from the source directory.
-	CopyPassthroughFiles(sourceDir string) error
+	// CopyPassthroughFiles copies community-maintained files from the source
+	// directory, checking ctx between files.
+	CopyPassthroughFiles(ctx context.Context, sourceDir string) error
+
+	// Files returns the names of the files written so far, in the order
+	// they were written.
+	Files() []string
 }
 
-// NewWriter creates a new Writer based on the configured output format.
-func NewWriter(cfg *config.Config) (Writer, error) {
+// NewWriter creates a new Writer based on the configured output format. A
+// nil logger discards log output.
+func NewWriter(cfg *config.Config, logger *slog.Logger) (Writer, error) {
 	switch cfg.OutputFormat {
 	case config.FormatCSV:
-		return NewCSVWriter(cfg), nil
+		return NewCSVWriter(cfg, logger), nil
 	case config.FormatJSON:
-		return NewJSONWriter(cfg), nil
+		return NewJSONWriter(cfg, logger), nil
 	default:
 		return nil, fmt.Errorf("unsupported output format: %s", cfg.OutputFormat)
 	}
@@ -58,3 +70,34 @@ func GetOutputFiles(format config.OutputFormat) []string {
 		return nil
 	}
 }
+
+// TypeSetFile returns the output file name of an additional type set, e.g.
+// invTypes_structures.csv.
+func TypeSetFile(name string, format config.OutputFormat) string {
+	if format == config.FormatJSON {
+		return "invTypes_" + name + ".json"
+	}
+	return "invTypes_" + name + ".csv"
+}
+
+// fileLog logs and records the files a writer has written.
+type fileLog struct {
+	logger *slog.Logger
+	files  []string
+}
+
+// newFileLog creates a fileLog; a nil logger discards log output.
+func newFileLog(logger *slog.Logger) *fileLog {
+	return &fileLog{logger: logging.OrDiscard(logger)}
+}
+
+// wrote records filename as written and logs it with args as attributes.
+func (l *fileLog) wrote(filename string, args ...any) {
+	l.files = append(l.files, filename)
+	l.logger.Debug("wrote file", append([]any{"file", filename}, args...)...)
+}
+
+// names returns a copy of the recorded file names.
+func (l *fileLog) names() []string {
+	return append([]string(nil), l.files...)
+}
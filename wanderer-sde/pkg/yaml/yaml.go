@@ -2,6 +2,7 @@
 package yaml
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -11,13 +12,45 @@ import (
 
 // ParseFile reads and parses a YAML file into the provided target.
 func ParseFile(path string, target interface{}) error {
+	return ParseFileContext(context.Background(), path, target)
+}
+
+// ParseFileContext reads and parses a YAML file into the provided target,
+// stopping with the context's error once ctx is cancelled. The context is
+// checked on every read, so a large file is abandoned part-way through.
+func ParseFileContext(ctx context.Context, path string, target interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	f, err := os.Open(path)
 	if err != nil {
 		return fmt.Errorf("failed to open file %s: %w", path, err)
 	}
 	defer func() { _ = f.Close() }()
 
-	return Parse(f, target)
+	if err := Parse(&contextReader{ctx: ctx, r: f}, target); err != nil {
+		// The decoder does not wrap reader errors, so report cancellation directly
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+// contextReader fails reads once its context is cancelled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read implements io.Reader.
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
 }
 
 // Parse decodes YAML from a reader into the provided target.
@@ -32,9 +65,64 @@ func Parse(r io.Reader, target interface{}) error {
 // ParseFileMap reads and parses a YAML file where the top level is a map.
 // This is useful for files like typeIDs.yaml where keys are IDs.
 func ParseFileMap[K comparable, V any](path string) (map[K]V, error) {
+	return ParseFileMapContext[K, V](context.Background(), path)
+}
+
+// ParseFileMapContext is ParseFileMap with cancellation, see ParseFileContext.
+func ParseFileMapContext[K comparable, V any](ctx context.Context, path string) (map[K]V, error) {
 	var result map[K]V
-	if err := ParseFile(path, &result); err != nil {
+	if err := ParseFileContext(ctx, path, &result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
+
+// Entry is one value of a top-level YAML map, decoded on demand.
+type Entry struct {
+	node *yaml.Node
+}
+
+// Decode decodes the entry into target.
+func (e Entry) Decode(target interface{}) error {
+	return e.node.Decode(target)
+}
+
+// FilterFileMap copies the top-level map in src to dst, keeping the entries
+// for which keep returns true. Kept entries are written unchanged, ordered
+// by key. It returns the number of entries kept.
+func FilterFileMap[K comparable](ctx context.Context, src, dst string, keep func(key K, entry Entry) (bool, error)) (int, error) {
+	entries, err := ParseFileMapContext[K, yaml.Node](ctx, src)
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make(map[K]*yaml.Node, len(entries))
+	for key, node := range entries {
+		ok, err := keep(key, Entry{node: &node})
+		if err != nil {
+			return 0, fmt.Errorf("failed to filter %v in %s: %w", key, src, err)
+		}
+		if ok {
+			kept[key] = &node
+		}
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file %s: %w", dst, err)
+	}
+	encoder := yaml.NewEncoder(f)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(kept); err != nil {
+		_ = f.Close()
+		return 0, fmt.Errorf("failed to encode YAML to %s: %w", dst, err)
+	}
+	if err := encoder.Close(); err != nil {
+		_ = f.Close()
+		return 0, fmt.Errorf("failed to encode YAML to %s: %w", dst, err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close file %s: %w", dst, err)
+	}
+	return len(kept), nil
+}
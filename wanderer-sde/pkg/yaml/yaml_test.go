@@ -1,6 +1,7 @@
 package yaml
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -286,3 +287,60 @@ func TestParseFileMap_NestedStructs(t *testing.T) {
 		t.Errorf("Expected stats value 100, got %d", entry.Stats.Value)
 	}
 }
+
+func TestFilterFileMap(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "yaml_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	yamlContent := `588:
+  name: "Slasher"
+  groupID: 25
+  extra: {keep: [1, 2]}
+587:
+  name: "Rifter"
+  groupID: 25
+670:
+  name: "Capsule"
+  groupID: 29
+`
+	src := filepath.Join(tmpDir, "types.yaml")
+	dst := filepath.Join(tmpDir, "filtered.yaml")
+	if err := os.WriteFile(src, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("failed to write yaml file: %v", err)
+	}
+
+	kept, err := FilterFileMap(context.Background(), src, dst, func(id int64, entry Entry) (bool, error) {
+		var data struct {
+			GroupID int64 `yaml:"groupID"`
+		}
+		if err := entry.Decode(&data); err != nil {
+			return false, err
+		}
+		return data.GroupID == 25, nil
+	})
+	if err != nil {
+		t.Fatalf("FilterFileMap failed: %v", err)
+	}
+	if kept != 2 {
+		t.Errorf("Expected 2 entries kept, got %d", kept)
+	}
+
+	content, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("failed to read filtered file: %v", err)
+	}
+	want := `587:
+  name: "Rifter"
+  groupID: 25
+588:
+  name: "Slasher"
+  groupID: 25
+  extra: {keep: [1, 2]}
+`
+	if string(content) != want {
+		t.Errorf("Expected entries unchanged and sorted by key:\n%s\ngot:\n%s", want, content)
+	}
+}